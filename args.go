@@ -0,0 +1,228 @@
+package structflag
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// argsParser parses GNU style command line arguments. Flags can be given as
+// --name=value, --name value, -name value, -n value or -nvalue. Boolean flags do
+// not take a separate argument and single letter boolean flags can be combined,
+// e.g. -abc. Arguments not starting with a dash are collected as positional
+// arguments wherever they appear and all arguments after "--" are positional.
+// For compatibility with flag package, an argument with a single dash is first
+// matched against all flag names before trying to split it into letters.
+type argsParser struct {
+	lookup map[string]Value
+	// unknown receives flags not matching any value if it is not nil
+	unknown map[string]string
+	// passUnknown returns flags not matching any value with positional arguments
+	passUnknown bool
+	// renamed maps previous names of renamed values to their current names
+	renamed map[string]string
+	// onRenamed is called when a previous name is used if it is not nil
+	onRenamed func(old, name string)
+	// canonicalize matches names which are not found to the flag with the same
+	// canonical name if it is not nil. Single letter names are matched exactly.
+	canonicalize func(name string) string
+	// messages formats errors
+	messages *Messages
+	// used records the values set from the arguments if it is not nil
+	used map[Value]bool
+}
+
+func newArgsParser(values map[string]Value) (*argsParser, error) {
+	lookup := map[string]Value{}
+	renamed := map[string]string{}
+	add := func(name string, value Value) error {
+		if _, ok := lookup[name]; ok {
+			return fmt.Errorf("flag %q is already defined", name)
+		}
+		lookup[name] = value
+		return nil
+	}
+	for name, value := range values {
+		if err := add(name, value); err != nil {
+			return nil, err
+		}
+		for _, alias := range value.Aliases() {
+			if err := add(alias, value); err != nil {
+				return nil, err
+			}
+		}
+		for _, old := range value.OldNames() {
+			if err := add(old, value); err != nil {
+				return nil, err
+			}
+			renamed[old] = name
+		}
+	}
+	return &argsParser{lookup: lookup, renamed: renamed, messages: DefaultMessages}, nil
+}
+
+// parse sets the values from args and returns the positional arguments.
+func (thiz *argsParser) parse(args []string) ([]string, error) {
+	positional := []string{}
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		var consumed int
+		var err error
+		switch {
+		case arg == "--":
+			return append(positional, args[i+1:]...), nil
+		case len(arg) < 2 || arg[0] != '-':
+			positional = append(positional, arg)
+		case strings.HasPrefix(arg, "--"):
+			consumed, err = thiz.parseLong("--", arg[2:], args[i+1:])
+		case thiz.lookup[strings.SplitN(arg[1:], "=", 2)[0]] != nil:
+			consumed, err = thiz.parseLong("-", arg[1:], args[i+1:])
+		default:
+			consumed, err = thiz.parseShort(arg[1:], args[i+1:])
+		}
+		if _, ok := err.(unknownFlagError); ok && (thiz.unknown != nil || thiz.passUnknown) {
+			consumed, err = thiz.addUnknown(args[i:]), nil
+			if thiz.passUnknown {
+				positional = append(positional, args[i:i+consumed+1]...)
+			}
+		}
+		if name, ok := err.(unknownFlagError); ok {
+			err = fmt.Errorf(thiz.messages.UnknownFlag+"%s", string(name), thiz.suggest(string(name)))
+		}
+		if err != nil {
+			return nil, err
+		}
+		i += consumed
+	}
+	return positional, nil
+}
+
+// find returns the value for the flag name and reports use of previous names.
+func (thiz *argsParser) find(name string) Value {
+	if _, ok := thiz.lookup[name]; !ok && thiz.canonicalize != nil && len(name) > 1 {
+		names := make([]string, 0, len(thiz.lookup))
+		for candidate := range thiz.lookup {
+			if len(candidate) > 1 {
+				names = append(names, candidate)
+			}
+		}
+		if match, ok := canonicalMatch(name, names, thiz.canonicalize); ok {
+			name = match
+		}
+	}
+	if current, ok := thiz.renamed[name]; ok && thiz.onRenamed != nil {
+		thiz.onRenamed(name, current)
+	}
+	return thiz.lookup[name]
+}
+
+// suggest returns a hint listing the flags with names similar to the unknown
+// flag. Single letter names are not considered.
+func (thiz *argsParser) suggest(flag string) string {
+	name := strings.TrimLeft(flag, "-")
+	if len(name) < 2 {
+		return ""
+	}
+	var candidates []string
+	for candidate := range thiz.lookup {
+		if len(candidate) > 1 {
+			candidates = append(candidates, candidate)
+		}
+	}
+	matches := suggest(name, candidates)
+	for i, match := range matches {
+		matches[i] = flag[:len(flag)-len(name)] + match
+	}
+	return thiz.messages.didYouMean(matches)
+}
+
+// parseLong handles a single flag with optional "=value" suffix. It returns the
+// number of arguments consumed from rest.
+func (thiz *argsParser) parseLong(dash, spec string, rest []string) (int, error) {
+	parts := strings.SplitN(spec, "=", 2)
+	name := parts[0]
+	value := thiz.find(name)
+	if value == nil {
+		return 0, unknownFlagError(dash + name)
+	}
+	switch {
+	case len(parts) == 2:
+		return 0, thiz.setFlag(value, dash+name, parts[1])
+	case value.IsBool():
+		return 0, thiz.setFlag(value, dash+name, "true")
+	case len(rest) == 0:
+		return 0, fmt.Errorf(thiz.messages.MissingArgument, dash+name)
+	}
+	return 1, thiz.setFlag(value, dash+name, rest[0])
+}
+
+// parseShort handles a group of single letter flags. The first flag taking a
+// value uses the rest of the group or the next argument as its value.
+func (thiz *argsParser) parseShort(group string, rest []string) (int, error) {
+	for i, c := range group {
+		name := string(c)
+		value := thiz.find(name)
+		if value == nil {
+			if i == 0 {
+				return 0, unknownFlagError("-" + group)
+			}
+			return 0, fmt.Errorf(thiz.messages.UnknownFlagInGroup, name, group)
+		}
+		if value.IsBool() {
+			if err := thiz.setFlag(value, "-"+name, "true"); err != nil {
+				return 0, err
+			}
+			continue
+		}
+		if attached := group[i+len(name):]; attached != "" {
+			return 0, thiz.setFlag(value, "-"+name, strings.TrimPrefix(attached, "="))
+		}
+		if len(rest) == 0 {
+			return 0, fmt.Errorf(thiz.messages.MissingArgument, "-"+name)
+		}
+		return 1, thiz.setFlag(value, "-"+name, rest[0])
+	}
+	return 0, nil
+}
+
+// addUnknown records the unknown flag at the beginning of args. A flag without
+// "=value" suffix takes the next argument as value unless it starts with a dash.
+// It returns the number of arguments consumed after the flag.
+func (thiz *argsParser) addUnknown(args []string) int {
+	parts := strings.SplitN(strings.TrimLeft(args[0], "-"), "=", 2)
+	value, consumed := "true", 0
+	switch {
+	case len(parts) == 2:
+		value = parts[1]
+	case len(args) > 1 && !strings.HasPrefix(args[1], "-"):
+		value, consumed = args[1], 1
+	}
+	if thiz.unknown != nil {
+		thiz.unknown[parts[0]] = value
+	}
+	return consumed
+}
+
+type unknownFlagError string
+
+func (thiz unknownFlagError) Error() string {
+	return "flag provided but not defined: " + string(thiz)
+}
+
+// setFlag sets the value of the flag given on the command line with name.
+func (thiz *argsParser) setFlag(value Value, name, s string) error {
+	if thiz.used != nil {
+		thiz.used[value] = true
+	}
+	if err := value.Set(s); err != nil {
+		var valueErr *ValueError
+		if errors.As(err, &valueErr) {
+			// Report the name used on the command line, which may be an alias
+			valueErr.Path = name
+			valueErr.format = thiz.messages.InvalidValue
+			return valueErr
+		}
+		return fmt.Errorf(thiz.messages.InvalidValue, s, name, err)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package structflag_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type gnuOptions struct {
+	All     bool   `aliases:"a"`
+	Brief   bool   `aliases:"b"`
+	Color   bool   `aliases:"c"`
+	Output  string `aliases:"o"`
+	Level   int    `aliases:"l"`
+	Verbose bool
+}
+
+func TestParseGNUStyle(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		expected   gnuOptions
+		positional []string
+	}{
+		{"long with equals", []string{"--Output=out.txt", "--Level=3"}, gnuOptions{Output: "out.txt", Level: 3}, []string{}},
+		{"long with value", []string{"--Output", "out.txt", "--Level", "-3"}, gnuOptions{Output: "out.txt", Level: -3}, []string{}},
+		{"single dash long", []string{"-Output", "out.txt", "-Verbose"}, gnuOptions{Output: "out.txt", Verbose: true}, []string{}},
+		{"short with value", []string{"-o", "out.txt", "-l4"}, gnuOptions{Output: "out.txt", Level: 4}, []string{}},
+		{"combined bools", []string{"-abc"}, gnuOptions{All: true, Brief: true, Color: true}, []string{}},
+		{"combined with value", []string{"-aco", "x"}, gnuOptions{All: true, Color: true, Output: "x"}, []string{}},
+		{"combined with attached value", []string{"-bl=7"}, gnuOptions{Brief: true, Level: 7}, []string{}},
+		{"bool with value", []string{"--All=false", "--Brief=true"}, gnuOptions{Brief: true}, []string{}},
+		{"interspersed", []string{"a", "-a", "b", "--Level", "2", "c"}, gnuOptions{All: true, Level: 2}, []string{"a", "b", "c"}},
+		{"terminator", []string{"-a", "--", "-b", "--Level"}, gnuOptions{All: true}, []string{"-b", "--Level"}},
+		{"single dash positional", []string{"-", "-a"}, gnuOptions{All: true}, []string{"-"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			val := &gnuOptions{}
+			args, err := newTestParser(&bytes.Buffer{}).Parse(val, tt.args)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, *val)
+			assert.Equal(t, tt.positional, args)
+		})
+	}
+}
+
+func TestParseGNUStyleErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		msg  string
+	}{
+		{"unknown long", []string{"--Unknown"}, "flag provided but not defined: --Unknown"},
+		{"unknown single dash", []string{"-Outptu"}, "flag provided but not defined: -Outptu"},
+		{"unknown in group", []string{"-abx"}, "flag provided but not defined: -x in -abx"},
+		{"missing value", []string{"--Output"}, "flag needs an argument: --Output"},
+		{"missing short value", []string{"-ao"}, "flag needs an argument: -o"},
+		{"invalid value", []string{"-l", "x"}, `invalid value "x" for flag -l`},
+		{"invalid bool", []string{"--All=maybe"}, `invalid value "maybe" for flag --All`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newTestParser(&bytes.Buffer{}).Parse(&gnuOptions{}, tt.args)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.msg)
+		})
+	}
+}
+
+func TestParseSuggestions(t *testing.T) {
+	tests := []struct {
+		args []string
+		msg  string
+	}{
+		{[]string{"--Outptu=x"}, `flag provided but not defined: --Outptu, did you mean "--Output"?`},
+		{[]string{"-Levle", "1"}, `flag provided but not defined: -Levle, did you mean "-Level"?`},
+		{[]string{"--Brif"}, `flag provided but not defined: --Brif, did you mean "--Brief"?`},
+		{[]string{"--color"}, `flag provided but not defined: --color, did you mean "--Color"?`},
+		{[]string{"--Unknown"}, `flag provided but not defined: --Unknown`},
+		{[]string{"-x"}, `flag provided but not defined: -x`},
+	}
+	for _, tt := range tests {
+		_, err := newTestParser(&bytes.Buffer{}).Parse(&gnuOptions{}, tt.args)
+		assert.EqualError(t, err, tt.msg)
+	}
+}
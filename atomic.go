@@ -0,0 +1,62 @@
+package structflag
+
+import (
+	"reflect"
+)
+
+// atomicCodec converts sync/atomic types like atomic.Int64, atomic.Bool and
+// atomic.Pointer using their Load and Store methods, so that fields can be
+// read concurrently while they are set. atomic.Value is not supported because
+// the type of its content is not known.
+type atomicCodec struct{}
+
+func init() {
+	RegisterCodec(atomicCodec{})
+}
+
+// isAtomic returns true for sync/atomic types with typed Load and Store methods.
+func isAtomic(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct || t.PkgPath() != "sync/atomic" || t.Name() == "Value" {
+		return false
+	}
+	ptr := reflect.PtrTo(t)
+	_, load := ptr.MethodByName("Load")
+	_, store := ptr.MethodByName("Store")
+	return load && store
+}
+
+// atomicType returns the type of values stored in the atomic type.
+func atomicType(t reflect.Type) reflect.Type {
+	load, _ := reflect.PtrTo(t).MethodByName("Load")
+	return load.Type.Out(0)
+}
+
+// atomicLoad returns the content of an addressable atomic value.
+func atomicLoad(val reflect.Value) reflect.Value {
+	return val.Addr().MethodByName("Load").Call(nil)[0]
+}
+
+// atomicStore replaces the content of an addressable atomic value.
+func atomicStore(val, content reflect.Value) {
+	val.Addr().MethodByName("Store").Call([]reflect.Value{content})
+}
+
+// Match returns true for supported sync/atomic types.
+func (atomicCodec) Match(t reflect.Type) bool {
+	return isAtomic(t)
+}
+
+// Decode parses the content and stores it atomically.
+func (atomicCodec) Decode(s string, val reflect.Value) error {
+	content := reflect.New(atomicType(val.Type())).Elem()
+	if err := decodeString(s, content); err != nil {
+		return err
+	}
+	atomicStore(val, content)
+	return nil
+}
+
+// Encode loads the content atomically and converts it.
+func (atomicCodec) Encode(val reflect.Value) (string, error) {
+	return encodeString(atomicLoad(addressable(val))), nil
+}
@@ -0,0 +1,102 @@
+package structflag_test
+
+import (
+	"bytes"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/surajbarkale/structflag"
+)
+
+type tunables struct {
+	Limit   atomic.Int64 `description:"Request limit"`
+	Enabled atomic.Bool
+	Name    atomic.Pointer[string]
+	Ratio   atomic.Uint32
+}
+
+func TestAtomicValues(t *testing.T) {
+	val := &tunables{}
+	val.Limit.Store(10)
+	values, err := structflag.DefaultStructToFlagsConverter.Convert(val)
+	require.NoError(t, err)
+	assert.Equal(t, "10", values["Limit"].Default())
+	assert.Equal(t, "", values["Name"].Default())
+	assert.True(t, values["Enabled"].IsBool())
+	assert.Equal(t, int64(10), values["Limit"].Get())
+
+	require.NoError(t, values["Limit"].Set("20"))
+	assert.Equal(t, int64(20), val.Limit.Load())
+	assert.Equal(t, "20", values["Limit"].String())
+	require.NoError(t, values["Name"].Set("test"))
+	assert.Equal(t, "test", *val.Name.Load())
+	assert.Error(t, values["Ratio"].Set("-1"))
+
+	values["Limit"].Reset()
+	assert.Equal(t, int64(10), val.Limit.Load())
+}
+
+func TestAtomicValuesInParser(t *testing.T) {
+	val := &tunables{}
+	parser := newTestParser(&bytes.Buffer{})
+	_, err := parser.Parse(val, []string{"--Enabled", "--Limit=5"})
+	require.NoError(t, err)
+	assert.True(t, val.Enabled.Load())
+	assert.Equal(t, int64(5), val.Limit.Load())
+}
+
+func TestAtomicValuesConcurrentAccess(t *testing.T) {
+	val := &tunables{}
+	values, err := structflag.DefaultStructToFlagsConverter.Convert(val)
+	require.NoError(t, err)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			_ = val.Limit.Load()
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		require.NoError(t, values["Limit"].Set("1"))
+	}
+	wg.Wait()
+}
+
+func TestAtomicValuesConcurrentResetAndRollback(t *testing.T) {
+	val := &tunables{}
+	val.Limit.Store(10)
+	values, err := structflag.DefaultStructToFlagsConverter.Convert(val)
+	require.NoError(t, err)
+	loader := structflag.NewConfigLoader()
+	src := &tunables{}
+	src.Limit.Store(30)
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				_ = val.Limit.Load()
+			}
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		values["Limit"].Reset()
+		assert.Error(t, loader.Load(values, []byte(`{"Limit": 20, "Ratio": -1}`)))
+		require.NoError(t, structflag.Merge(val, src))
+	}
+	close(done)
+	wg.Wait()
+	assert.Equal(t, int64(30), val.Limit.Load())
+	values["Limit"].Reset()
+	assert.Equal(t, int64(10), val.Limit.Load())
+}
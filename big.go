@@ -0,0 +1,90 @@
+package structflag
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+)
+
+var (
+	bigIntType   = reflect.TypeOf(big.Int{})
+	bigFloatType = reflect.TypeOf(big.Float{})
+)
+
+// bigCodec converts big.Int and big.Float values from decimal strings or
+// strings with base prefix, e.g. "0x1f".
+type bigCodec struct{}
+
+func init() {
+	RegisterCodec(bigCodec{})
+}
+
+// Match returns true for big.Int, big.Float and pointers to them.
+func (bigCodec) Match(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t == bigIntType || t == bigFloatType
+}
+
+// Decode parses the number. Precision of big.Float values is kept if it was
+// set, otherwise it is 64 bits. Existing pointers are updated in place.
+func (thiz bigCodec) Decode(s string, val reflect.Value) error {
+	if val.Kind() == reflect.Ptr {
+		res := reflect.New(val.Type().Elem())
+		if !val.IsNil() {
+			res.Elem().Set(val.Elem())
+		}
+		if err := thiz.Decode(s, res.Elem()); err != nil {
+			return err
+		}
+		if val.IsNil() {
+			val.Set(res)
+		} else {
+			val.Elem().Set(res.Elem())
+		}
+		return nil
+	}
+	if val.Type() == bigIntType {
+		res, ok := new(big.Int).SetString(s, 0)
+		if !ok {
+			return fmt.Errorf("invalid integer %q", s)
+		}
+		val.Set(reflect.ValueOf(*res))
+		return nil
+	}
+	res := new(big.Float).SetPrec(addressable(val).Addr().Interface().(*big.Float).Prec())
+	if _, _, err := res.Parse(s, 0); err != nil {
+		return fmt.Errorf("invalid number %q: %v", s, err)
+	}
+	val.Set(reflect.ValueOf(*res))
+	return nil
+}
+
+// Encode returns decimal representation of the number. Floats use the
+// shortest representation which parses back to the same value.
+func (thiz bigCodec) Encode(val reflect.Value) (string, error) {
+	if val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			return "", nil
+		}
+		return thiz.Encode(val.Elem())
+	}
+	switch x := addressable(val).Addr().Interface().(type) {
+	case *big.Int:
+		return x.String(), nil
+	case *big.Float:
+		return x.Text('g', -1), nil
+	}
+	return "", fmt.Errorf("unsupported type %s", val.Type())
+}
+
+// addressable returns val or its copy which can be addressed.
+func addressable(val reflect.Value) reflect.Value {
+	if val.CanAddr() {
+		return val
+	}
+	ptr := reflect.New(val.Type())
+	ptr.Elem().Set(val)
+	return ptr.Elem()
+}
@@ -0,0 +1,39 @@
+package structflag_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/surajbarkale/structflag"
+)
+
+func TestBigValues(t *testing.T) {
+	val := &struct {
+		Amount  big.Int
+		Limit   *big.Int
+		Price   big.Float
+		Precise *big.Float
+	}{Amount: *big.NewInt(42), Precise: new(big.Float).SetPrec(200)}
+	values, err := structflag.DefaultStructToFlagsConverter.Convert(val)
+	require.NoError(t, err)
+	assert.Len(t, values, 4)
+	assert.Equal(t, "42", values["Amount"].Default())
+	assert.Equal(t, "", values["Limit"].Default())
+
+	require.NoError(t, values["Amount"].Set("123456789012345678901234567890"))
+	assert.Equal(t, "123456789012345678901234567890", val.Amount.String())
+	require.NoError(t, values["Limit"].Set("0xff"))
+	assert.Equal(t, int64(255), val.Limit.Int64())
+	assert.Equal(t, "255", values["Limit"].String())
+	assert.EqualError(t, values["Limit"].Set("12a"), `invalid value "12a" for flag Limit: invalid integer "12a"`)
+
+	require.NoError(t, values["Price"].Set("0.1"))
+	assert.Equal(t, "0.1", values["Price"].String())
+	assert.Error(t, values["Price"].Set("abc"))
+	require.NoError(t, values["Precise"].Set("0.1000000000000000000000000001"))
+	assert.Equal(t, uint(200), val.Precise.Prec())
+	assert.Equal(t, "0.1000000000000000000000000001", val.Precise.Text('g', 28))
+}
@@ -0,0 +1,119 @@
+package structflag
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+)
+
+// Binder binds a struct to its own flag set and environment variables, so that
+// several configurations can be kept apart in one process, e.g. one for each
+// plugin or listener. Use PrintBinders to show the usage of all binders.
+type Binder struct {
+	// Name identifies the binder in usage output and is the name of its flag
+	// set.
+	Name string
+	// Converter is used to generate flag values from the struct.
+	Converter *StructToFlagsConverter
+	// Env sets values which were not set by flags from environment variables if
+	// it is not nil, e.g. NewEnvLoader with a prefix for each binder.
+	Env *EnvLoader
+
+	flagSet *flag.FlagSet
+	values  map[string]Value
+}
+
+// NewBinder returns a binder with given name using DefaultStructToFlagsConverter
+// and environment variables with envPrefix. Environment variables are not read
+// if envPrefix is empty. The returned instance can be customized by changing
+// fields.
+func NewBinder(name, envPrefix string) *Binder {
+	thiz := &Binder{
+		Name:      name,
+		Converter: DefaultStructToFlagsConverter,
+	}
+	if envPrefix != "" {
+		thiz.Env = NewEnvLoader(envPrefix)
+	}
+	return thiz
+}
+
+// Bind converts target into values and defines them in a new flag set of the
+// binder. You must pass a pointer to the value.
+func (thiz *Binder) Bind(target interface{}) error {
+	values, err := thiz.Converter.Convert(target)
+	if err != nil {
+		return err
+	}
+	return thiz.bind(values)
+}
+
+// BindContributors converts the configuration structs of the contributors into
+// values using ConvertContributors and defines them in a new flag set of the
+// binder. The registered contributors are used if none are given.
+func (thiz *Binder) BindContributors(contributors ...Contributor) error {
+	if len(contributors) == 0 {
+		contributors = Contributors()
+	}
+	values, err := thiz.Converter.ConvertContributors(contributors...)
+	if err != nil {
+		return err
+	}
+	return thiz.bind(values)
+}
+
+// bind defines the values in a new flag set.
+func (thiz *Binder) bind(values map[string]Value) error {
+	fs := flag.NewFlagSet(thiz.Name, flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	if err := Register(fs, values); err != nil {
+		return err
+	}
+	thiz.flagSet, thiz.values = fs, values
+	return nil
+}
+
+// Parse parses args using the flag set of the binder and then applies
+// environment variables. The positional arguments are returned.
+func (thiz *Binder) Parse(args []string) ([]string, error) {
+	if thiz.flagSet == nil {
+		return nil, fmt.Errorf("binder %s is not bound", thiz.Name)
+	}
+	if err := thiz.flagSet.Parse(args); err != nil {
+		return nil, fmt.Errorf("%s: %v", thiz.Name, err)
+	}
+	if thiz.Env != nil {
+		if err := thiz.Env.Apply(thiz.values); err != nil {
+			return nil, fmt.Errorf("%s: %v", thiz.Name, err)
+		}
+	}
+	return thiz.flagSet.Args(), nil
+}
+
+// FlagSet returns the flag set of the binder or nil if it is not bound.
+func (thiz *Binder) FlagSet() *flag.FlagSet {
+	return thiz.flagSet
+}
+
+// Values returns the values bound by the binder.
+func (thiz *Binder) Values() map[string]Value {
+	return thiz.values
+}
+
+// PrintBinders writes the usage of all binders to w as sections sorted by
+// binder name, each in the format of PrintDefaults.
+func PrintBinders(w io.Writer, binders ...*Binder) {
+	sorted := append([]*Binder{}, binders...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+	for i, binder := range sorted {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "%s flags:\n", binder.Name)
+		PrintDefaults(w, binder.values)
+	}
+}
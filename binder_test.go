@@ -0,0 +1,54 @@
+package structflag_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/surajbarkale/structflag"
+)
+
+type listenerOptions struct {
+	Addr    string `description:"Listen address"`
+	Verbose bool
+}
+
+func TestBinders(t *testing.T) {
+	public, admin := &listenerOptions{Addr: ":80"}, &listenerOptions{Addr: ":9000"}
+	publicBinder := structflag.NewBinder("public", "PUBLIC_")
+	publicBinder.Env.LookupEnv = fakeEnv(map[string]string{"PUBLIC_VERBOSE": "true", "ADMIN_ADDR": ":1"})
+	require.NoError(t, publicBinder.Bind(public))
+	adminBinder := structflag.NewBinder("admin", "ADMIN_")
+	adminBinder.Env.LookupEnv = fakeEnv(map[string]string{"ADMIN_ADDR": ":9001"})
+	require.NoError(t, adminBinder.Bind(admin))
+
+	args, err := publicBinder.Parse([]string{"-Addr", ":8080", "x"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"x"}, args)
+	_, err = adminBinder.Parse(nil)
+	require.NoError(t, err)
+	assert.Equal(t, listenerOptions{Addr: ":8080", Verbose: true}, *public)
+	assert.Equal(t, listenerOptions{Addr: ":9001"}, *admin)
+	assert.NotNil(t, publicBinder.FlagSet().Lookup("Addr"))
+	assert.Len(t, adminBinder.Values(), 2)
+
+	_, err = adminBinder.Parse([]string{"-Port", "1"})
+	assert.EqualError(t, err, "admin: flag provided but not defined: -Port")
+	_, err = structflag.NewBinder("unbound", "").Parse(nil)
+	assert.Error(t, err)
+
+	var out bytes.Buffer
+	structflag.PrintBinders(&out, publicBinder, adminBinder)
+	assert.Equal(t, `admin flags:
+  -Addr string
+    	Listen address (default :9000)
+  -Verbose
+
+public flags:
+  -Addr string
+    	Listen address (default :80)
+  -Verbose
+`, out.String())
+}
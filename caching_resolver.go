@@ -0,0 +1,179 @@
+package structflag
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// CachingResolver keeps the values returned by another resolver keyed by URI,
+// in memory and optionally on disk, so that they are not fetched again within
+// TTL and, if enabled, the last known good values are used when the backend is
+// not available.
+type CachingResolver struct {
+	// Resolver fetches the values which are not cached.
+	Resolver Resolver
+	// TTL is how long cached values are used without calling the resolver.
+	TTL time.Duration
+	// Dir stores the cache in files if it is not empty, so it survives
+	// restarts. The files contain the values in plain text and are only
+	// readable by the owner.
+	Dir string
+	// UseStale returns cached values older than TTL when the resolver fails.
+	UseStale bool
+	// Now returns the current time.
+	Now func() time.Time
+
+	lock  sync.Mutex
+	cache map[string]cachedValue
+}
+
+// cachedValue is a resolved value with the time it was fetched.
+type cachedValue struct {
+	Value   string    `json:"value"`
+	Fetched time.Time `json:"fetched"`
+}
+
+// NewCachingResolver returns a resolver caching values in memory for ttl which
+// does not use stale values. The returned instance can be customized by
+// changing fields.
+func NewCachingResolver(resolver Resolver, ttl time.Duration) *CachingResolver {
+	return &CachingResolver{
+		Resolver: resolver,
+		TTL:      ttl,
+		Now:      time.Now,
+	}
+}
+
+// Resolve returns the cached value if it is fresh and calls the resolver
+// otherwise.
+func (thiz *CachingResolver) Resolve(ctx context.Context, uri string) (string, error) {
+	cached, ok := thiz.load(uri)
+	if ok && thiz.Now().Sub(cached.Fetched) < thiz.TTL {
+		return cached.Value, nil
+	}
+	s, err := thiz.Resolver.Resolve(ctx, uri)
+	if err != nil {
+		if ok && thiz.UseStale {
+			return cached.Value, nil
+		}
+		return "", err
+	}
+	return s, thiz.store(uri, s)
+}
+
+// ResolveBatch returns the fresh cached values and fetches the others in one
+// batch if the resolver is a BatchResolver or one by one otherwise.
+func (thiz *CachingResolver) ResolveBatch(ctx context.Context, uris []string) (map[string]string, error) {
+	batch, ok := thiz.Resolver.(BatchResolver)
+	if !ok {
+		res := make(map[string]string, len(uris))
+		var failed error
+		for _, uri := range uris {
+			s, err := thiz.Resolve(ctx, uri)
+			if errors.Is(err, ErrResolveFailed) {
+				failed = err
+				continue
+			} else if err != nil {
+				return nil, err
+			}
+			res[uri] = s
+		}
+		return res, failed
+	}
+	res := make(map[string]string, len(uris))
+	stale := map[string]string{}
+	var missing []string
+	for _, uri := range uris {
+		cached, ok := thiz.load(uri)
+		switch {
+		case ok && thiz.Now().Sub(cached.Fetched) < thiz.TTL:
+			res[uri] = cached.Value
+			continue
+		case ok && thiz.UseStale:
+			stale[uri] = cached.Value
+		}
+		missing = append(missing, uri)
+	}
+	if len(missing) == 0 {
+		return res, nil
+	}
+	fetched, err := batch.ResolveBatch(ctx, missing)
+	failedOpen := errors.Is(err, ErrResolveFailed)
+	for _, uri := range missing {
+		if s, ok := fetched[uri]; ok && (err == nil || failedOpen) {
+			if err := thiz.store(uri, s); err != nil {
+				return nil, err
+			}
+			res[uri] = s
+		} else if s, ok := stale[uri]; ok && err != nil {
+			res[uri] = s
+		}
+	}
+	if len(res) < len(uris) {
+		return res, err
+	}
+	return res, nil
+}
+
+// load returns the cached value from memory or from the file.
+func (thiz *CachingResolver) load(uri string) (cachedValue, bool) {
+	thiz.lock.Lock()
+	defer thiz.lock.Unlock()
+	if cached, ok := thiz.cache[uri]; ok {
+		return cached, true
+	}
+	if thiz.Dir == "" {
+		return cachedValue{}, false
+	}
+	data, err := ioutil.ReadFile(thiz.file(uri))
+	if err != nil {
+		return cachedValue{}, false
+	}
+	var cached cachedValue
+	if json.Unmarshal(data, &cached) != nil {
+		return cachedValue{}, false
+	}
+	thiz.remember(uri, cached)
+	return cached, true
+}
+
+// store caches the value fetched now.
+func (thiz *CachingResolver) store(uri, s string) error {
+	thiz.lock.Lock()
+	defer thiz.lock.Unlock()
+	cached := cachedValue{Value: s, Fetched: thiz.Now()}
+	thiz.remember(uri, cached)
+	if thiz.Dir == "" {
+		return nil
+	}
+	data, err := json.Marshal(cached)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(thiz.Dir, 0700); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(thiz.file(uri), data, 0600)
+}
+
+// remember adds the value to the memory cache. The lock must be held.
+func (thiz *CachingResolver) remember(uri string, cached cachedValue) {
+	if thiz.cache == nil {
+		thiz.cache = map[string]cachedValue{}
+	}
+	thiz.cache[uri] = cached
+}
+
+// file returns the name of the cache file of the URI.
+func (thiz *CachingResolver) file(uri string) string {
+	sum := sha256.Sum256([]byte(uri))
+	return filepath.Join(thiz.Dir, hex.EncodeToString(sum[:])+".json")
+}
@@ -0,0 +1,100 @@
+package structflag_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/surajbarkale/structflag"
+)
+
+// countingResolver returns values with the number of calls or fails when down.
+type countingResolver struct {
+	calls int
+	down  bool
+}
+
+func (thiz *countingResolver) Resolve(ctx context.Context, uri string) (string, error) {
+	if thiz.down {
+		return "", errors.New("unavailable")
+	}
+	thiz.calls++
+	return uri + "#" + string(rune('0'+thiz.calls)), nil
+}
+
+func TestCachingResolver(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	backend := &countingResolver{}
+	resolver := structflag.NewCachingResolver(backend, time.Minute)
+	resolver.Now = func() time.Time { return now }
+	ctx := context.Background()
+
+	s, err := resolver.Resolve(ctx, "vault://a")
+	require.NoError(t, err)
+	assert.Equal(t, "vault://a#1", s)
+	s, _ = resolver.Resolve(ctx, "vault://a")
+	assert.Equal(t, "vault://a#1", s)
+	now = now.Add(2 * time.Minute)
+	s, _ = resolver.Resolve(ctx, "vault://a")
+	assert.Equal(t, "vault://a#2", s)
+
+	backend.down = true
+	now = now.Add(2 * time.Minute)
+	_, err = resolver.Resolve(ctx, "vault://a")
+	assert.EqualError(t, err, "unavailable")
+	resolver.UseStale = true
+	s, err = resolver.Resolve(ctx, "vault://a")
+	require.NoError(t, err)
+	assert.Equal(t, "vault://a#2", s)
+	_, err = resolver.Resolve(ctx, "vault://b")
+	assert.Error(t, err)
+}
+
+func TestCachingResolverDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "structflag")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	backend := &countingResolver{}
+	resolver := structflag.NewCachingResolver(backend, time.Hour)
+	resolver.Dir = dir
+	_, err = resolver.Resolve(context.Background(), "vault://a")
+	require.NoError(t, err)
+
+	backend.down = true
+	restarted := structflag.NewCachingResolver(backend, 0)
+	restarted.Dir = dir
+	_, err = restarted.Resolve(context.Background(), "vault://a")
+	assert.Error(t, err)
+	restarted.UseStale = true
+	s, err := restarted.Resolve(context.Background(), "vault://a")
+	require.NoError(t, err)
+	assert.Equal(t, "vault://a#1", s)
+	files, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, os.FileMode(0600), files[0].Mode().Perm())
+}
+
+func TestCachingResolverBatch(t *testing.T) {
+	batch := &batchSecrets{}
+	resolver := structflag.NewCachingResolver(batch, time.Hour)
+	val := &resolverOptions{Password: "vault://db", Token: "vault://api"}
+	values, err := structflag.DefaultStructToFlagsConverter.Convert(val)
+	require.NoError(t, err)
+	resolvers := map[string]structflag.Resolver{"vault": resolver}
+	require.NoError(t, structflag.Resolve(context.Background(), values, resolvers))
+	assert.Equal(t, resolverOptions{Password: "db", Token: "api"}, *val)
+
+	val = &resolverOptions{Password: "vault://db", Token: "vault://other"}
+	values, err = structflag.DefaultStructToFlagsConverter.Convert(val)
+	require.NoError(t, err)
+	require.NoError(t, structflag.Resolve(context.Background(), values, resolvers))
+	assert.Equal(t, resolverOptions{Password: "db", Token: "other"}, *val)
+	assert.Equal(t, [][]string{{"vault://db", "vault://api"}, {"vault://other"}}, batch.batches)
+}
@@ -0,0 +1,38 @@
+package structflag
+
+import (
+	"strings"
+	"unicode"
+)
+
+// CanonicalName returns name in lower case without separators, so that
+// "Nested-IntPtr", "nested.int_ptr" and "NESTED_INTPTR" have the same canonical
+// name "nestedintptr". It can be used as Parser.Canonicalize and
+// ConfigLoader.Canonicalize.
+func CanonicalName(name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(unicode.ToLower(r))
+		}
+	}
+	return b.String()
+}
+
+// canonicalMatch returns the only name in names with the same canonical name as
+// name. Nothing is matched if several names have the same canonical name.
+func canonicalMatch(name string, names []string, canonicalize func(string) string) (string, bool) {
+	canonical := canonicalize(name)
+	var match string
+	var found bool
+	for _, candidate := range names {
+		if canonicalize(candidate) != canonical {
+			continue
+		}
+		if found && candidate != match {
+			return "", false
+		}
+		match, found = candidate, true
+	}
+	return match, found
+}
@@ -0,0 +1,41 @@
+package structflag_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/surajbarkale/structflag"
+)
+
+func TestCanonicalName(t *testing.T) {
+	for _, name := range []string{"Nested-IntPtr", "nested.int_ptr", "NESTED_INTPTR", "nested-int-ptr"} {
+		assert.Equal(t, "nestedintptr", structflag.CanonicalName(name), name)
+	}
+	assert.Equal(t, "port8080", structflag.CanonicalName("Port_8080"))
+}
+
+func TestParseCanonicalize(t *testing.T) {
+	val := &struct {
+		Nested struct {
+			IntPtr *int
+		}
+		MaxConns  int
+		Max_Conns int
+		V         bool
+	}{}
+	p := newTestParser(&bytes.Buffer{})
+	p.Canonicalize = structflag.CanonicalName
+	_, err := p.Parse(val, []string{"--nested.int_ptr", "3", "-V"})
+	require.NoError(t, err)
+	require.NotNil(t, val.Nested.IntPtr)
+	assert.Equal(t, 3, *val.Nested.IntPtr)
+	assert.True(t, val.V)
+
+	_, err = p.Parse(val, []string{"--max-conns=1"})
+	assert.EqualError(t, err, `flag provided but not defined: --max-conns, did you mean "--MaxConns" or "--Max_Conns"?`)
+	_, err = newTestParser(&bytes.Buffer{}).Parse(val, []string{"--nested.int_ptr", "3"})
+	assert.Error(t, err)
+}
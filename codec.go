@@ -0,0 +1,53 @@
+package structflag
+
+import (
+	"reflect"
+	"sync"
+)
+
+// Codec converts values of specific types to and from strings. Codecs are
+// used for types that can not be handled by the default conversion rules.
+// Fields with a matching codec are always treated as leaf values, even if
+// they are structs or pointers to structs.
+type Codec interface {
+	// Match returns true if the codec can convert values of the given type.
+	Match(t reflect.Type) bool
+	// Decode parses the string and stores the result into val.
+	Decode(s string, val reflect.Value) error
+	// Encode returns string representation of val.
+	Encode(val reflect.Value) (string, error)
+}
+
+var codecs struct {
+	sync.RWMutex
+	list []Codec
+}
+
+// RegisterCodec adds a codec used by all values created after the call. Codecs
+// registered later take precedence over the ones registered earlier. This
+// function is usually called from init function of a package providing
+// support for third party types.
+func RegisterCodec(c Codec) {
+	codecs.Lock()
+	defer codecs.Unlock()
+	codecs.list = append(codecs.list, c)
+}
+
+func findCodec(t reflect.Type) Codec {
+	codecs.RLock()
+	defer codecs.RUnlock()
+	for i := len(codecs.list) - 1; i >= 0; i-- {
+		if codecs.list[i].Match(t) {
+			return codecs.list[i]
+		}
+	}
+	return nil
+}
+
+// isCodecType returns true if t or the type pointed by t has a codec.
+func isCodecType(t reflect.Type) bool {
+	if findCodec(t) != nil {
+		return true
+	}
+	return t.Kind() == reflect.Ptr && findCodec(t.Elem()) != nil
+}
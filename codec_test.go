@@ -0,0 +1,67 @@
+package structflag_test
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/surajbarkale/structflag"
+)
+
+type point struct {
+	X, Y int
+}
+
+type pointCodec struct{}
+
+func (pointCodec) Match(t reflect.Type) bool {
+	return t == reflect.TypeOf(point{})
+}
+
+func (pointCodec) Decode(s string, val reflect.Value) error {
+	var p point
+	if _, err := fmt.Sscanf(strings.TrimSpace(s), "%d:%d", &p.X, &p.Y); err != nil {
+		return err
+	}
+	val.Set(reflect.ValueOf(p))
+	return nil
+}
+
+func (pointCodec) Encode(val reflect.Value) (string, error) {
+	p := val.Interface().(point)
+	return fmt.Sprintf("%d:%d", p.X, p.Y), nil
+}
+
+func init() {
+	structflag.RegisterCodec(pointCodec{})
+}
+
+func TestCodecValue(t *testing.T) {
+	var val point
+	require.NoError(t, reflectValue(&val).Set("3:4"))
+	assert.Equal(t, point{3, 4}, val)
+	assert.Equal(t, "3:4", reflectValue(&val).String())
+	assert.Error(t, reflectValue(&val).Set("3"))
+
+	var ptr *point
+	require.NoError(t, reflectValue(&ptr).Set("5:6"))
+	require.NotNil(t, ptr)
+	assert.Equal(t, point{5, 6}, *ptr)
+}
+
+func TestCodecFieldIsLeaf(t *testing.T) {
+	val := struct {
+		Origin point
+		Target *point
+	}{}
+	sv, err := structflag.NewStructToFlagsConverter().Convert(&val)
+	require.NoError(t, err)
+	assert.Len(t, sv, 2)
+	assert.Contains(t, sv, "Origin")
+	assert.Contains(t, sv, "Target")
+	assert.Nil(t, val.Target)
+}
@@ -0,0 +1,67 @@
+package structflag
+
+import (
+	"fmt"
+	"image/color"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var (
+	rgbaType  = reflect.TypeOf(color.RGBA{})
+	nrgbaType = reflect.TypeOf(color.NRGBA{})
+)
+
+// colorCodec converts color.RGBA and color.NRGBA values from hex strings.
+type colorCodec struct{}
+
+func init() {
+	RegisterCodec(colorCodec{})
+}
+
+// Match returns true for color.RGBA and color.NRGBA.
+func (colorCodec) Match(t reflect.Type) bool {
+	return t == rgbaType || t == nrgbaType
+}
+
+// Decode parses "#RRGGBB", "#RRGGBBAA", "#RGB" or "#RGBA". The leading "#" is
+// optional. Alpha is not premultiplied in the input, so it is applied to the
+// color channels of color.RGBA values.
+func (colorCodec) Decode(s string, val reflect.Value) error {
+	hex := strings.TrimPrefix(s, "#")
+	if len(hex) == 3 || len(hex) == 4 {
+		var b strings.Builder
+		for _, c := range hex {
+			b.WriteRune(c)
+			b.WriteRune(c)
+		}
+		hex = b.String()
+	}
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+	if len(hex) != 8 {
+		return fmt.Errorf("invalid color %q, expected #RRGGBB or #RRGGBBAA", s)
+	}
+	n, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return fmt.Errorf("invalid color %q, expected #RRGGBB or #RRGGBBAA", s)
+	}
+	c := color.NRGBA{R: uint8(n >> 24), G: uint8(n >> 16), B: uint8(n >> 8), A: uint8(n)}
+	if val.Type() == rgbaType {
+		val.Set(reflect.ValueOf(color.RGBAModel.Convert(c)))
+	} else {
+		val.Set(reflect.ValueOf(c))
+	}
+	return nil
+}
+
+// Encode returns "#rrggbb" for opaque colors and "#rrggbbaa" otherwise.
+func (colorCodec) Encode(val reflect.Value) (string, error) {
+	c := color.NRGBAModel.Convert(val.Interface().(color.Color)).(color.NRGBA)
+	if c.A == 0xff {
+		return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B), nil
+	}
+	return fmt.Sprintf("#%02x%02x%02x%02x", c.R, c.G, c.B, c.A), nil
+}
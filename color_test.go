@@ -0,0 +1,39 @@
+package structflag_test
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/surajbarkale/structflag"
+)
+
+func TestColorValues(t *testing.T) {
+	val := &struct {
+		Background color.RGBA
+		Foreground color.NRGBA
+		Border     *color.RGBA
+	}{Background: color.RGBA{R: 0xff, A: 0xff}}
+	values, err := structflag.DefaultStructToFlagsConverter.Convert(val)
+	require.NoError(t, err)
+	assert.Len(t, values, 3)
+	assert.Equal(t, "#ff0000", values["Background"].Default())
+
+	require.NoError(t, values["Background"].Set("#336699"))
+	assert.Equal(t, color.RGBA{R: 0x33, G: 0x66, B: 0x99, A: 0xff}, val.Background)
+	require.NoError(t, values["Background"].Set("#ff000080"))
+	assert.Equal(t, color.RGBA{R: 0x80, A: 0x80}, val.Background)
+	assert.Equal(t, "#ff000080", values["Background"].String())
+
+	require.NoError(t, values["Foreground"].Set("fff8"))
+	assert.Equal(t, color.NRGBA{R: 0xff, G: 0xff, B: 0xff, A: 0x88}, val.Foreground)
+	assert.Equal(t, "#ffffff88", values["Foreground"].String())
+	require.NoError(t, values["Border"].Set("#000"))
+	assert.Equal(t, &color.RGBA{A: 0xff}, val.Border)
+
+	for _, invalid := range []string{"", "#12345", "#gg0000", "red"} {
+		assert.Error(t, values["Foreground"].Set(invalid), invalid)
+	}
+}
@@ -0,0 +1,88 @@
+package structflag
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// SecretCipher encrypts secret values written to configuration files and
+// decrypts them when the files are loaded, e.g. using age or a key management
+// service. The flag name is passed so that it can be bound to the ciphertext.
+type SecretCipher interface {
+	Encrypt(name, plaintext string) (string, error)
+	Decrypt(name, ciphertext string) (string, error)
+}
+
+// encryptedPrefix and encryptedSuffix surround encrypted values in
+// configuration files, e.g. "ENC[...]".
+const (
+	encryptedPrefix = "ENC["
+	encryptedSuffix = "]"
+)
+
+// ConfigDumper writes values as a configuration file mapping flag names to
+// their string representation which can be read by ConfigLoader, e.g. to create
+// a sample configuration file.
+type ConfigDumper struct {
+	// Marshal encodes the object mapping flag names to strings. Other formats
+	// can be used by providing an encoder, e.g. yaml.Marshal.
+	Marshal func(v interface{}) ([]byte, error)
+	// Cipher encrypts non-empty secret values which are written as
+	// "ENC[ciphertext]". Secret values are left out if it is nil.
+	Cipher SecretCipher
+	// SkipDefaults leaves out values which are not set from any source.
+	SkipDefaults bool
+}
+
+// NewConfigDumper returns a dumper writing indented JSON which leaves out
+// secret values. The returned instance can be customized by changing fields.
+func NewConfigDumper() *ConfigDumper {
+	return &ConfigDumper{
+		Marshal: func(v interface{}) ([]byte, error) {
+			data, err := json.MarshalIndent(v, "", "  ")
+			return append(data, '\n'), err
+		},
+	}
+}
+
+// Dump writes the values to w.
+func (thiz *ConfigDumper) Dump(w io.Writer, values map[string]Value) error {
+	config := make(map[string]string, len(values))
+	for name, value := range values {
+		if thiz.SkipDefaults && isDefault(value) {
+			continue
+		}
+		s := value.String()
+		if value.IsSecret() && s != "" {
+			if thiz.Cipher == nil {
+				continue
+			}
+			ciphertext, err := thiz.Cipher.Encrypt(name, s)
+			if err != nil {
+				return fmt.Errorf("can not encrypt flag %s: %v", name, err)
+			}
+			s = encryptedPrefix + ciphertext + encryptedSuffix
+		}
+		config[name] = s
+	}
+	data, err := thiz.Marshal(config)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// decrypt returns the plaintext of an encrypted secret value or s if the
+// value is not encrypted.
+func (thiz *ConfigLoader) decrypt(name string, value Value, s string) (string, error) {
+	if !value.IsSecret() || !strings.HasPrefix(s, encryptedPrefix) || !strings.HasSuffix(s, encryptedSuffix) {
+		return s, nil
+	}
+	if thiz.Cipher == nil {
+		return "", fmt.Errorf("encrypted value needs a cipher")
+	}
+	return thiz.Cipher.Decrypt(name, s[len(encryptedPrefix):len(s)-len(encryptedSuffix)])
+}
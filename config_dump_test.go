@@ -0,0 +1,81 @@
+package structflag_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/surajbarkale/structflag"
+)
+
+// base64Cipher encodes values with the flag name for testing.
+type base64Cipher struct{}
+
+func (base64Cipher) Encrypt(name, plaintext string) (string, error) {
+	return base64.StdEncoding.EncodeToString([]byte(name + ":" + plaintext)), nil
+}
+
+func (base64Cipher) Decrypt(name, ciphertext string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return "", err
+	}
+	if !strings.HasPrefix(string(data), name+":") {
+		return "", errors.New("wrong flag")
+	}
+	return strings.TrimPrefix(string(data), name+":"), nil
+}
+
+type dumpOptions struct {
+	Host     string
+	Port     int
+	Password string `secret:"true"`
+}
+
+func TestConfigDumper(t *testing.T) {
+	values, err := structflag.DefaultStructToFlagsConverter.Convert(&dumpOptions{Host: "localhost"})
+	require.NoError(t, err)
+	require.NoError(t, values["Port"].Set("80"))
+	require.NoError(t, values["Password"].Set("hunter2"))
+
+	dumper := structflag.NewConfigDumper()
+	var buf bytes.Buffer
+	require.NoError(t, dumper.Dump(&buf, values))
+	assert.Equal(t, "{\n  \"Host\": \"localhost\",\n  \"Port\": \"80\"\n}\n", buf.String())
+
+	buf.Reset()
+	dumper.SkipDefaults = true
+	dumper.Cipher = base64Cipher{}
+	require.NoError(t, dumper.Dump(&buf, values))
+	assert.Equal(t, "{\n  \"Password\": \"ENC[UGFzc3dvcmQ6aHVudGVyMg==]\",\n  \"Port\": \"80\"\n}\n", buf.String())
+
+	val := &dumpOptions{}
+	values, err = structflag.DefaultStructToFlagsConverter.Convert(val)
+	require.NoError(t, err)
+	loader := structflag.NewConfigLoader()
+	assert.EqualError(t, loader.Load(values, buf.Bytes()), `invalid value "ENC[UGFzc3dvcmQ6aHVudGVyMg==]" for flag Password: encrypted value needs a cipher`)
+	loader.Cipher = base64Cipher{}
+	require.NoError(t, loader.Load(values, buf.Bytes()))
+	assert.Equal(t, dumpOptions{Port: 80, Password: "hunter2"}, *val)
+}
+
+func TestConfigLoaderDecrypt(t *testing.T) {
+	val := &struct {
+		Token string
+		Key   string `secret:"true"`
+	}{}
+	values, err := structflag.DefaultStructToFlagsConverter.Convert(val)
+	require.NoError(t, err)
+	loader := structflag.NewConfigLoader()
+	loader.Cipher = base64Cipher{}
+	require.NoError(t, loader.Load(values, []byte(`{"Token": "ENC[abc]", "Key": "plain"}`)))
+	assert.Equal(t, "ENC[abc]", val.Token)
+	assert.Equal(t, "plain", val.Key)
+	assert.EqualError(t, loader.Load(values, []byte(`{"Key": "ENC[VG9rZW46eA==]"}`)), `invalid value "ENC[VG9rZW46eA==]" for flag Key: wrong flag`)
+	assert.Equal(t, "plain", val.Key)
+}
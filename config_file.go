@@ -0,0 +1,502 @@
+package structflag
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// SourceFile is the source of values set from configuration files.
+const SourceFile Source = "file"
+
+// ConfigLoader sets values from configuration files containing an object which
+// maps flag names to values. Nested objects are matched against the names of
+// nested struct fields, so {"Server": {"Port": 80}} and {"Server-Port": 80}
+// both set the Server-Port flag. Strings are used as is and other values are
+// converted to JSON before setting them. Null values are ignored.
+type ConfigLoader struct {
+	// Unmarshal parses the content of configuration files. Other formats can be
+	// used by providing a parser, e.g. yamlflag.Unmarshal for YAML or a TOML
+	// parser decoding into map[string]interface{}.
+	Unmarshal func(data []byte, v interface{}) error
+	// Separator joins the keys of nested objects into flag names. It must match
+	// the WordSeparator of the converter creating the values.
+	Separator string
+	// ResolvePaths makes relative paths in values with `format:"path"` struct
+	// tag relative to the directory of the file instead of the working
+	// directory. It is not used by Load.
+	ResolvePaths bool
+	// AppendSlices appends arrays from later files to the earlier ones instead
+	// of replacing them.
+	AppendSlices bool
+	// DeepMergeMaps merges objects from later files into the earlier ones key
+	// by key instead of replacing them.
+	DeepMergeMaps bool
+	// MergeTag is used to query struct tag to get how values of a field from
+	// multiple files are combined, overriding the options. The strategy is
+	// "append" for arrays, "deep" for objects or "replace" to use the value
+	// from the later file, e.g. `merge:"append"`. The options and the tag match
+	// the ones of Merger.
+	MergeTag string
+	// IncludeKey is the top level key listing files which are loaded before the
+	// file containing it, e.g. {"include": ["base.json"]}. Relative paths are
+	// relative to the including file. Empty string disables includes.
+	IncludeKey string
+	// Canonicalize normalizes keys which do not match any flag exactly, e.g.
+	// CanonicalName to match "nested.int_ptr" or "NESTED_INTPTR" against the
+	// Nested-IntPtr flag. Keys matching several flags are unknown.
+	Canonicalize func(name string) string
+	// FS is used to read files instead of the operating system if it is not nil,
+	// e.g. embed.FS with default configuration. Paths use forward slashes and
+	// are relative to the root of FS. ResolvePaths is not used with FS because
+	// values are paths in the operating system.
+	FS fs.FS
+	// Strict returns an error for keys which do not match any flag instead of
+	// ignoring them. The error suggests flags with similar names.
+	Strict bool
+	// Cipher decrypts secret values written as "ENC[ciphertext]" by
+	// ConfigDumper. Encrypted values are invalid if it is nil.
+	Cipher SecretCipher
+
+	unknown map[string]interface{}
+}
+
+// NewConfigLoader returns a loader for JSON files which ignores unknown keys,
+// resolves paths relative to the file, reads included files from "include" key,
+// replaces arrays, merges objects key by key like NewMerger, reads merge
+// strategies from "merge" struct tag and uses "-" as separator. The returned
+// instance can be customized by changing fields.
+func NewConfigLoader() *ConfigLoader {
+	return &ConfigLoader{
+		Unmarshal:     unmarshalJSON,
+		Separator:     "-",
+		ResolvePaths:  true,
+		IncludeKey:    "include",
+		DeepMergeMaps: true,
+		MergeTag:      "merge",
+	}
+}
+
+// unmarshalJSON keeps numbers as json.Number so that large integers are not
+// rounded.
+func unmarshalJSON(data []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decoder.Decode(v)
+}
+
+// LoadFile sets the values from the configuration file at path.
+func (thiz *ConfigLoader) LoadFile(values map[string]Value, path string) error {
+	return thiz.loadFiles(values, []string{path}, nil)
+}
+
+// LoadLayered sets the values from the configuration files in order, so keys in
+// later files override the same keys in earlier files, e.g. to apply settings
+// for an environment on top of a base file. Either all values are updated or
+// none of them.
+func (thiz *ConfigLoader) LoadLayered(values map[string]Value, paths ...string) error {
+	return thiz.loadFiles(values, paths, nil)
+}
+
+// loadFiles sets the values from the files. If canSet is not nil, then only
+// the values for which it returns true are changed.
+func (thiz *ConfigLoader) loadFiles(values map[string]Value, paths []string, canSet func(Value) bool) error {
+	update := map[string]configEntry{}
+	thiz.unknown = map[string]interface{}{}
+	for _, path := range paths {
+		if err := thiz.readFile(values, path, nil, update); err != nil {
+			return err
+		}
+	}
+	return thiz.apply(values, update, canSet)
+}
+
+// readFile adds the entries of the file at path to update after the entries of
+// the files it includes. The including files are listed in parents.
+func (thiz *ConfigLoader) readFile(values map[string]Value, path string, parents []string, update map[string]configEntry) error {
+	abs, err := thiz.abs(path)
+	if err != nil {
+		return err
+	}
+	for _, parent := range parents {
+		if parent == abs {
+			return fmt.Errorf("%s: include cycle", path)
+		}
+	}
+	var data []byte
+	if thiz.FS != nil {
+		data, err = fs.ReadFile(thiz.FS, abs)
+	} else {
+		data, err = ioutil.ReadFile(path)
+	}
+	if err != nil {
+		return err
+	}
+	if err := thiz.read(values, data, path, append(parents, abs), update); err != nil {
+		return fmt.Errorf("%s: %v", path, err)
+	}
+	return nil
+}
+
+// abs returns the absolute path of a file, which is the cleaned path relative
+// to the root of FS if it is set.
+func (thiz *ConfigLoader) abs(p string) (string, error) {
+	if thiz.FS != nil {
+		return path.Clean(strings.TrimPrefix(p, "/")), nil
+	}
+	return filepath.Abs(p)
+}
+
+// dir returns the directory of an absolute path.
+func (thiz *ConfigLoader) dir(p string) string {
+	if thiz.FS != nil {
+		return path.Dir(p)
+	}
+	return filepath.Dir(p)
+}
+
+// join returns p relative to dir unless p is absolute.
+func (thiz *ConfigLoader) join(dir, p string) string {
+	if thiz.FS != nil {
+		if strings.HasPrefix(p, "/") {
+			return p
+		}
+		return path.Join(dir, p)
+	}
+	if filepath.IsAbs(p) {
+		return p
+	}
+	return filepath.Join(dir, p)
+}
+
+// Load sets the values from the configuration data. Included files are
+// relative to the working directory or the root of FS. Either all values are
+// updated or none of them.
+func (thiz *ConfigLoader) Load(values map[string]Value, data []byte) error {
+	update := map[string]configEntry{}
+	thiz.unknown = map[string]interface{}{}
+	if err := thiz.read(values, data, "", nil, update); err != nil {
+		return err
+	}
+	return thiz.apply(values, update, nil)
+}
+
+// configEntry is a value read from a configuration file.
+type configEntry struct {
+	// value is the decoded value
+	value interface{}
+	// file is the path of the file containing the value, if any
+	file string
+}
+
+// read adds the entries of data read from file to update after the entries of
+// the files it includes. Relative paths are resolved against the directory of
+// the file if it is not empty and against the working directory otherwise.
+func (thiz *ConfigLoader) read(values map[string]Value, data []byte, file string, parents []string, update map[string]configEntry) error {
+	var config map[string]interface{}
+	if err := thiz.Unmarshal(data, &config); err != nil {
+		return err
+	}
+	includes, err := thiz.includes(config)
+	if err != nil {
+		return err
+	}
+	var dir string
+	if file != "" {
+		dir = thiz.dir(parents[len(parents)-1])
+	}
+	for _, include := range includes {
+		include = thiz.join(dir, include)
+		if err := thiz.readFile(values, include, parents, update); err != nil {
+			return err
+		}
+	}
+	entries, unknown := map[string]interface{}{}, map[string]interface{}{}
+	if err := thiz.collect(values, "", "", config, entries, unknown); err != nil {
+		return err
+	}
+	thiz.unknown = mergeObjects(thiz.unknown, unknown).(map[string]interface{})
+	for name, entry := range entries {
+		if s, ok := entry.(string); ok && thiz.ResolvePaths && thiz.FS == nil && dir != "" && isPathValue(values[name]) {
+			if entry, err = resolvePath(s, dir); err != nil {
+				return err
+			}
+		}
+		if previous, ok := update[name]; ok {
+			entry = thiz.combine(values[name], previous.value, entry)
+		}
+		update[name] = configEntry{entry, file}
+	}
+	return nil
+}
+
+// includes removes IncludeKey from config and returns the included files. The
+// key can contain a single path or a list of paths.
+func (thiz *ConfigLoader) includes(config map[string]interface{}) ([]string, error) {
+	if thiz.IncludeKey == "" {
+		return nil, nil
+	}
+	include, ok := config[thiz.IncludeKey]
+	if !ok {
+		return nil, nil
+	}
+	delete(config, thiz.IncludeKey)
+	switch include := include.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return []string{include}, nil
+	case []interface{}:
+		paths := make([]string, len(include))
+		for i, path := range include {
+			s, ok := path.(string)
+			if !ok {
+				return nil, fmt.Errorf("invalid %s entry %v", thiz.IncludeKey, path)
+			}
+			paths[i] = s
+		}
+		return paths, nil
+	}
+	return nil, fmt.Errorf("invalid %s value %v", thiz.IncludeKey, include)
+}
+
+// Unknown returns the entries of the files read by the last load which do not
+// match any flag, e.g. to pass them to other components. Nested objects keep
+// the structure of the files and keys from later files replace the same keys
+// from earlier files.
+func (thiz *ConfigLoader) Unknown() map[string]interface{} {
+	return thiz.unknown
+}
+
+// apply sets the values and restores the previous state if any of them fails.
+func (thiz *ConfigLoader) apply(values map[string]Value, update map[string]configEntry, canSet func(Value) bool) error {
+	names := make([]string, 0, len(update))
+	for name := range update {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var restore []func()
+	for _, name := range names {
+		value := values[name]
+		if canSet != nil && !canSet(value) {
+			continue
+		}
+		if r, ok := value.(restorer); ok {
+			restore = append(restore, r.save())
+		}
+		entry := update[name]
+		s, err := configString(entry.value)
+		plaintext := s
+		if err == nil {
+			plaintext, err = thiz.decrypt(name, value, s)
+		}
+		if err == nil && canSet != nil {
+			// Values set by sources with lower priority are replaced
+			err = replaceFrom(value, plaintext, SourceFile)
+		} else if err == nil {
+			err = setFrom(value, plaintext, SourceFile)
+		}
+		if err != nil {
+			for i := len(restore) - 1; i >= 0; i-- {
+				restore[i]()
+			}
+			err = fmt.Errorf("invalid value %q for flag %s: %s", s, name, errorDetail(err))
+			if entry.file != "" {
+				err = fmt.Errorf("%s: %v", entry.file, err)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// collect adds the entries of config to update keyed by flag names and the
+// entries which do not match any flag to unknown. The key is the dotted path of
+// config in the file, used in error messages.
+func (thiz *ConfigLoader) collect(values map[string]Value, prefix, key string, config map[string]interface{}, update, unknown map[string]interface{}) error {
+	keys := make([]string, 0, len(config))
+	for k := range config {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		name, path, elem := prefix+k, key+k, config[k]
+		if elem == nil {
+			continue
+		}
+		if match, ok := thiz.match(values, name); ok {
+			update[match] = elem
+			continue
+		}
+		if nested, ok := elem.(map[string]interface{}); ok && thiz.hasPrefix(values, name+thiz.Separator) {
+			nestedUnknown := map[string]interface{}{}
+			if err := thiz.collect(values, name+thiz.Separator, path+".", nested, update, nestedUnknown); err != nil {
+				return err
+			}
+			if len(nestedUnknown) > 0 {
+				unknown[k] = nestedUnknown
+			}
+			continue
+		}
+		if thiz.Strict {
+			return fmt.Errorf("unknown key %q%s", path, didYouMean(suggest(name, flagNames(values))))
+		}
+		unknown[k] = elem
+	}
+	return nil
+}
+
+// hasPrefix returns true if any flag name starts with prefix, comparing
+// canonical names if Canonicalize is set.
+func (thiz *ConfigLoader) hasPrefix(values map[string]Value, prefix string) bool {
+	canonical := prefix
+	if thiz.Canonicalize != nil {
+		canonical = thiz.Canonicalize(prefix)
+	}
+	for name := range values {
+		if strings.HasPrefix(name, prefix) || (thiz.Canonicalize != nil && strings.HasPrefix(thiz.Canonicalize(name), canonical)) {
+			return true
+		}
+	}
+	return false
+}
+
+// match returns the name of the flag matching the key name.
+func (thiz *ConfigLoader) match(values map[string]Value, name string) (string, bool) {
+	if _, ok := values[name]; ok || thiz.Canonicalize == nil {
+		return name, ok
+	}
+	return canonicalMatch(name, flagNames(values), thiz.Canonicalize)
+}
+
+// configString converts a decoded configuration value into the string passed to
+// Set.
+func configString(v interface{}) (string, error) {
+	if s, ok := v.(string); ok {
+		return s, nil
+	}
+	encoded, err := json.Marshal(v)
+	return string(encoded), err
+}
+
+// flagNames returns the sorted names of the values.
+func flagNames(values map[string]Value) []string {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// FindConfigFile returns the path of the first existing file with one of the
+// names in the configuration directories of the application. The directories
+// are searched in this order:
+//
+//  1. $XDG_CONFIG_HOME/appName, or ~/.config/appName if XDG_CONFIG_HOME is not set
+//  2. /etc/appName
+//  3. the directory containing the executable
+//
+// All names are tried in each directory before moving to the next one. An error
+// matching os.ErrNotExist is returned if there is no such file.
+func FindConfigFile(appName string, names ...string) (string, error) {
+	for _, dir := range configDirs(appName) {
+		for _, name := range names {
+			path := filepath.Join(dir, name)
+			if info, err := os.Stat(path); err == nil && !info.IsDir() {
+				return path, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("no config file %s found for %s: %w", strings.Join(names, ", "), appName, os.ErrNotExist)
+}
+
+// configDirs returns the directories searched by FindConfigFile. Directories
+// which can not be determined are left out.
+func configDirs(appName string) []string {
+	var dirs []string
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
+		dirs = append(dirs, filepath.Join(xdg, appName))
+	} else if home, err := os.UserHomeDir(); err == nil {
+		dirs = append(dirs, filepath.Join(home, ".config", appName))
+	}
+	dirs = append(dirs, filepath.Join("/etc", appName))
+	if exe, err := os.Executable(); err == nil {
+		dirs = append(dirs, filepath.Dir(exe))
+	}
+	return dirs
+}
+
+// loadConfig applies the configuration file at path, or the file found using
+// ConfigNames if path is empty, to the values which were not set.
+func (thiz *Parser) loadConfig(values map[string]Value, path string) error {
+	if thiz.ConfigLoader == nil {
+		return nil
+	}
+	if path == "" && len(thiz.ConfigNames) > 0 {
+		found, err := FindConfigFile(thiz.Name, thiz.ConfigNames...)
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		path = found
+	}
+	if path == "" {
+		return nil
+	}
+	return thiz.ConfigLoader.loadFiles(values, []string{path}, thiz.canSet(SourceFile))
+}
+
+// combine returns the value of a flag read from a later file combined with the
+// value read from an earlier file according to the options and the merge
+// strategy of the flag.
+func (thiz *ConfigLoader) combine(value Value, earlier, later interface{}) interface{} {
+	var strategy string
+	if thiz.MergeTag != "" {
+		strategy = value.Field().Tag.Get(thiz.MergeTag)
+	}
+	if strategy == "replace" {
+		return later
+	}
+	if thiz.AppendSlices || strategy == "append" {
+		a, ok1 := earlier.([]interface{})
+		b, ok2 := later.([]interface{})
+		if ok1 && ok2 {
+			return append(append([]interface{}{}, a...), b...)
+		}
+	}
+	if thiz.DeepMergeMaps || strategy == "deep" {
+		return mergeObjects(earlier, later)
+	}
+	return later
+}
+
+// mergeObjects merges decoded JSON objects key by key. Other values are
+// replaced.
+func mergeObjects(earlier, later interface{}) interface{} {
+	a, ok1 := earlier.(map[string]interface{})
+	b, ok2 := later.(map[string]interface{})
+	if !ok1 || !ok2 {
+		return later
+	}
+	res := make(map[string]interface{}, len(a)+len(b))
+	for key, item := range a {
+		res[key] = item
+	}
+	for key, item := range b {
+		if existing, ok := res[key]; ok {
+			item = mergeObjects(existing, item)
+		}
+		res[key] = item
+	}
+	return res
+}
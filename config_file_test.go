@@ -0,0 +1,263 @@
+package structflag_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/surajbarkale/structflag"
+)
+
+type configFileTest struct {
+	Host   string
+	Port   int
+	ID     int64
+	Tags   []string
+	Server struct {
+		Timeout int
+		Retries int
+	}
+}
+
+func TestConfigLoader(t *testing.T) {
+	val := &configFileTest{Port: 80}
+	values, err := structflag.DefaultStructToFlagsConverter.Convert(val)
+	require.NoError(t, err)
+	loader := structflag.NewConfigLoader()
+	require.NoError(t, loader.Load(values, []byte(`{
+		"Host": "example.com",
+		"ID": 9007199254740993,
+		"Tags": ["a", "b"],
+		"Server": {"Timeout": 30},
+		"Server-Retries": 3,
+		"Port": null,
+		"Debug": true
+	}`)))
+	assert.Equal(t, "example.com", val.Host)
+	assert.Equal(t, 80, val.Port)
+	assert.Equal(t, int64(9007199254740993), val.ID)
+	assert.Equal(t, []string{"a", "b"}, val.Tags)
+	assert.Equal(t, 30, val.Server.Timeout)
+	assert.Equal(t, 3, val.Server.Retries)
+	assert.Equal(t, structflag.SourceFile, values["Host"].Source())
+	assert.Equal(t, structflag.SourceDefault, values["Port"].Source())
+
+	err = loader.Load(values, []byte(`{"Host": "other", "Port": "x"}`))
+	assert.EqualError(t, err, `invalid value "x" for flag Port: strconv.ParseInt: parsing "x": invalid syntax, expected integer`)
+	assert.Equal(t, "example.com", val.Host)
+	assert.Error(t, loader.Load(values, []byte(`[1]`)))
+}
+
+func TestConfigLoaderStrict(t *testing.T) {
+	val := &configFileTest{}
+	values, err := structflag.DefaultStructToFlagsConverter.Convert(val)
+	require.NoError(t, err)
+	loader := structflag.NewConfigLoader()
+	loader.Strict = true
+	assert.EqualError(t, loader.Load(values, []byte(`{"Prot": 1}`)), `unknown key "Prot", did you mean "Port"?`)
+	assert.EqualError(t, loader.Load(values, []byte(`{"Server": {"Retry": 1}}`)), `unknown key "Server.Retry", did you mean "Server-Retries"?`)
+	assert.EqualError(t, loader.Load(values, []byte(`{"Hort": 1}`)), `unknown key "Hort", did you mean "Host" or "Port"?`)
+	assert.EqualError(t, loader.Load(values, []byte(`{"Verbose": true}`)), `unknown key "Verbose"`)
+	assert.Equal(t, 0, val.Port)
+}
+
+func TestConfigLoaderUnknown(t *testing.T) {
+	dir, err := ioutil.TempDir("", "structflag")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	writeTempFile(t, dir, "base.json", `{"Host": "base", "Plugins": {"a": 1}, "Server": {"Timeout": 10, "TLS": {"Cert": "x"}}}`)
+	prod := writeTempFile(t, dir, "prod.json", `{"include": "base.json", "Plugins": {"b": 2}, "Server": {"TLS": {"Key": "y"}}}`)
+	val := &configFileTest{}
+	values, err := structflag.DefaultStructToFlagsConverter.Convert(val)
+	require.NoError(t, err)
+	loader := structflag.NewConfigLoader()
+	require.NoError(t, loader.LoadFile(values, prod))
+	assert.Equal(t, "base", val.Host)
+	assert.Equal(t, 10, val.Server.Timeout)
+	assert.Equal(t, map[string]interface{}{
+		"Plugins": map[string]interface{}{"a": json.Number("1"), "b": json.Number("2")},
+		"Server":  map[string]interface{}{"TLS": map[string]interface{}{"Cert": "x", "Key": "y"}},
+	}, loader.Unknown())
+
+	require.NoError(t, loader.Load(values, []byte(`{"Host": "other"}`)))
+	assert.Empty(t, loader.Unknown())
+}
+
+func TestConfigLoaderFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "structflag")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := writeTempFile(t, dir, "config.json", `{"Port": "x"}`)
+	val := &configFileTest{}
+	values, err := structflag.DefaultStructToFlagsConverter.Convert(val)
+	require.NoError(t, err)
+	loader := structflag.NewConfigLoader()
+	assert.EqualError(t, loader.LoadFile(values, path), path+`: invalid value "x" for flag Port: strconv.ParseInt: parsing "x": invalid syntax, expected integer`)
+	assert.Error(t, loader.LoadFile(values, filepath.Join(dir, "missing.json")))
+}
+
+func TestFindConfigFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "structflag")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "myapp"), 0700))
+	path := writeTempFile(t, filepath.Join(dir, "myapp"), "config.yaml", "")
+
+	found, err := structflag.FindConfigFile("myapp", "config.json", "config.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, path, found)
+
+	_, err = structflag.FindConfigFile("myapp", "missing.json")
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+	assert.EqualError(t, err, "no config file missing.json found for myapp: file does not exist")
+}
+
+func TestParseConfigFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "structflag")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "test"), 0700))
+	writeTempFile(t, filepath.Join(dir, "test"), "config.json", `{"Host": "found", "Port": 1}`)
+	explicit := writeTempFile(t, dir, "explicit.json", `{"Host": "explicit"}`)
+
+	newParser := func() *structflag.Parser {
+		parser := newTestParser(&bytes.Buffer{})
+		parser.ConfigLoader = structflag.NewConfigLoader()
+		parser.ConfigFlag = "config"
+		parser.ConfigNames = []string{"config.json"}
+		return parser
+	}
+	val := &configFileTest{}
+	parser := newParser()
+	_, err = parser.Parse(val, []string{"--Port", "2"})
+	require.NoError(t, err)
+	assert.Equal(t, "found", val.Host)
+	assert.Equal(t, 2, val.Port)
+	assert.Equal(t, structflag.SourceFile, parser.Values()["Host"].Source())
+
+	val = &configFileTest{}
+	_, err = newParser().Parse(val, []string{"--config", explicit})
+	require.NoError(t, err)
+	assert.Equal(t, "explicit", val.Host)
+	assert.Equal(t, 0, val.Port)
+
+	val = &configFileTest{}
+	parser = newParser()
+	parser.ConfigNames = []string{"missing.json"}
+	_, err = parser.Parse(val, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "", val.Host)
+}
+
+func TestConfigLoaderLayered(t *testing.T) {
+	dir, err := ioutil.TempDir("", "structflag")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	base := writeTempFile(t, dir, "base.json", `{"Host": "base", "Port": 80, "Tags": ["a", "b"], "Server": {"Timeout": 10, "Retries": 1}}`)
+	prod := writeTempFile(t, dir, "prod.json", `{"Host": "prod", "Tags": ["c"], "Server": {"Retries": 5}}`)
+	val := &configFileTest{}
+	values, err := structflag.DefaultStructToFlagsConverter.Convert(val)
+	require.NoError(t, err)
+	loader := structflag.NewConfigLoader()
+	require.NoError(t, loader.LoadLayered(values, base, prod))
+	expected := configFileTest{Host: "prod", Port: 80, Tags: []string{"c"}}
+	expected.Server.Timeout = 10
+	expected.Server.Retries = 5
+	assert.Equal(t, expected, *val)
+
+	bad := writeTempFile(t, dir, "bad.json", `{"Host": "bad", "Port": "x"}`)
+	assert.EqualError(t, loader.LoadLayered(values, base, bad), bad+`: invalid value "x" for flag Port: strconv.ParseInt: parsing "x": invalid syntax, expected integer`)
+	assert.Equal(t, expected, *val)
+}
+
+func TestConfigLoaderInclude(t *testing.T) {
+	dir, err := ioutil.TempDir("", "structflag")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "conf.d"), 0700))
+	writeTempFile(t, dir, "base.json", `{"Host": "base", "Port": 80}`)
+	writeTempFile(t, filepath.Join(dir, "conf.d"), "tags.json", `{"Tags": ["x"], "Port": 81}`)
+	main := writeTempFile(t, dir, "main.json", `{"include": ["base.json", "conf.d/tags.json"], "Host": "main"}`)
+	val := &configFileTest{}
+	values, err := structflag.DefaultStructToFlagsConverter.Convert(val)
+	require.NoError(t, err)
+	loader := structflag.NewConfigLoader()
+	loader.Strict = true
+	require.NoError(t, loader.LoadFile(values, main))
+	assert.Equal(t, "main", val.Host)
+	assert.Equal(t, 81, val.Port)
+	assert.Equal(t, []string{"x"}, val.Tags)
+
+	cycle := writeTempFile(t, dir, "cycle.json", `{"include": "cycle.json"}`)
+	assert.EqualError(t, loader.LoadFile(values, cycle), cycle+": "+cycle+": include cycle")
+	assert.EqualError(t, loader.Load(values, []byte(`{"include": 1}`)), "invalid include value 1")
+}
+
+func TestConfigLoaderMergeTags(t *testing.T) {
+	dir, err := ioutil.TempDir("", "structflag")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	base := writeTempFile(t, dir, "base.json", `{"Hosts": ["a"], "Tags": ["a"], "Limits": {"cpu": {"max": 1}}, "Labels": {"x": "1"}}`)
+	extra := writeTempFile(t, dir, "extra.json", `{"Hosts": ["b"], "Tags": ["b"], "Limits": {"cpu": {"min": 0}, "mem": {"max": 2}}, "Labels": {"y": "2"}}`)
+	val := &struct {
+		Hosts  []string `merge:"append"`
+		Tags   []string
+		Limits map[string]map[string]int
+		Labels map[string]string `merge:"replace"`
+	}{}
+	values, err := structflag.DefaultStructToFlagsConverter.Convert(val)
+	require.NoError(t, err)
+	require.NoError(t, structflag.NewConfigLoader().LoadLayered(values, base, extra))
+	assert.Equal(t, []string{"a", "b"}, val.Hosts)
+	assert.Equal(t, []string{"b"}, val.Tags)
+	assert.Equal(t, map[string]map[string]int{"cpu": {"max": 1, "min": 0}, "mem": {"max": 2}}, val.Limits)
+	assert.Equal(t, map[string]string{"y": "2"}, val.Labels)
+
+	val.Hosts, val.Limits, val.Labels = nil, nil, nil
+	loader := structflag.NewConfigLoader()
+	loader.AppendSlices = true
+	loader.DeepMergeMaps = false
+	require.NoError(t, loader.LoadLayered(values, base, extra))
+	assert.Equal(t, []string{"a", "b"}, val.Tags)
+	assert.Equal(t, map[string]map[string]int{"cpu": {"min": 0}, "mem": {"max": 2}}, val.Limits)
+}
+
+func TestConfigLoaderCanonicalize(t *testing.T) {
+	val := &configFileTest{}
+	values, err := structflag.DefaultStructToFlagsConverter.Convert(val)
+	require.NoError(t, err)
+	loader := structflag.NewConfigLoader()
+	loader.Canonicalize = structflag.CanonicalName
+	require.NoError(t, loader.Load(values, []byte(`{"HOST": "example.com", "server": {"time_out": 30}, "server.retries": 2, "plugin": {"x": 1}}`)))
+	assert.Equal(t, "example.com", val.Host)
+	assert.Equal(t, 30, val.Server.Timeout)
+	assert.Equal(t, 2, val.Server.Retries)
+	assert.Equal(t, map[string]interface{}{"plugin": map[string]interface{}{"x": json.Number("1")}}, loader.Unknown())
+}
+
+func TestConfigLoaderFS(t *testing.T) {
+	val := &configFileTest{}
+	values, err := structflag.DefaultStructToFlagsConverter.Convert(val)
+	require.NoError(t, err)
+	loader := structflag.NewConfigLoader()
+	loader.FS = fstest.MapFS{
+		"config/base.json": {Data: []byte(`{"Host": "base", "Port": 80}`)},
+		"config/app.json":  {Data: []byte(`{"include": "base.json", "Host": "app"}`)},
+		"loop.json":        {Data: []byte(`{"include": "/loop.json"}`)},
+	}
+	require.NoError(t, loader.LoadFile(values, "config/app.json"))
+	assert.Equal(t, "app", val.Host)
+	assert.Equal(t, 80, val.Port)
+	assert.EqualError(t, loader.LoadFile(values, "loop.json"), "loop.json: /loop.json: include cycle")
+	assert.Error(t, loader.LoadFile(values, "missing.json"))
+}
@@ -0,0 +1,162 @@
+package structflag
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+)
+
+// ConfigHandler is an http.Handler serving current values as a JSON object
+// mapping flag names to their string representation. It can be mounted on an
+// internal endpoint like /debug/config. If enabled, PATCH requests with a JSON
+// object mapping flag names to strings update the values. Either all values in
+// a PATCH request are updated or none of them.
+//
+// Values are read and updated while holding Locker. PATCH requests write the
+// fields of the target, so code reading fields which can be updated must hold
+// the same lock, e.g. RLock of a shared sync.RWMutex, otherwise the reads race
+// with the updates.
+type ConfigHandler struct {
+	// SecretMask replaces non-empty secret values in the output.
+	SecretMask string
+	// ShowSource changes the output to include the source of each value,
+	// e.g. {"Port": {"value": "80", "source": "flag"}}.
+	ShowSource bool
+	// AllowPatch enables updating values using PATCH requests.
+	AllowPatch bool
+	// Locker is held while the values are read or updated. The handler only
+	// serializes its own requests if it is nil.
+	Locker sync.Locker
+
+	mutex  sync.Mutex
+	values map[string]Value
+}
+
+// NewConfigHandler returns a handler serving the values which masks secrets with
+// "******" and does not allow updates. The returned instance can be customized
+// by changing fields.
+func NewConfigHandler(values map[string]Value) *ConfigHandler {
+	return &ConfigHandler{
+		SecretMask: "******",
+		values:     values,
+	}
+}
+
+// Option customizes the handler returned by Handler.
+type Option func(*ConfigHandler)
+
+// WithSecretMask replaces non-empty secret values with mask.
+func WithSecretMask(mask string) Option {
+	return func(h *ConfigHandler) { h.SecretMask = mask }
+}
+
+// WithSource includes the source of each value in the output.
+func WithSource() Option {
+	return func(h *ConfigHandler) { h.ShowSource = true }
+}
+
+// WithPatch enables updating values using PATCH requests.
+func WithPatch() Option {
+	return func(h *ConfigHandler) { h.AllowPatch = true }
+}
+
+// WithLocker holds locker while the values are read or updated, e.g. a mutex
+// guarding reads of the target elsewhere in the program.
+func WithLocker(locker sync.Locker) Option {
+	return func(h *ConfigHandler) { h.Locker = locker }
+}
+
+// Handler returns a ConfigHandler serving the values of target generated by
+// DefaultStructToFlagsConverter, customized by opts. It panics if the target
+// can not be converted. You must pass a pointer to the value.
+func Handler(target interface{}, opts ...Option) http.Handler {
+	values, err := DefaultStructToFlagsConverter.Convert(target)
+	if err != nil {
+		panic(err)
+	}
+	h := NewConfigHandler(values)
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+type sourcedValue struct {
+	Value  string `json:"value"`
+	Source Source `json:"source"`
+}
+
+// ServeHTTP handles GET and PATCH requests.
+func (thiz *ConfigHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var locker sync.Locker = &thiz.mutex
+	if thiz.Locker != nil {
+		locker = thiz.Locker
+	}
+	locker.Lock()
+	defer locker.Unlock()
+	switch {
+	case r.Method == http.MethodGet || r.Method == http.MethodHead:
+	case r.Method == http.MethodPatch && thiz.AllowPatch:
+		var update map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+			http.Error(w, fmt.Sprintf("invalid request: %v", err), http.StatusBadRequest)
+			return
+		}
+		if err := thiz.update(update); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	default:
+		if thiz.AllowPatch {
+			w.Header().Set("Allow", "GET, HEAD, PATCH")
+		} else {
+			w.Header().Set("Allow", "GET, HEAD")
+		}
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	output := map[string]interface{}{}
+	for name, value := range thiz.values {
+		s := value.String()
+		if value.IsSecret() && s != "" {
+			s = thiz.SecretMask
+		}
+		if thiz.ShowSource {
+			output[name] = sourcedValue{s, value.Source()}
+		} else {
+			output[name] = s
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(output)
+}
+
+// update sets the values and restores the previous state if any of them fails.
+func (thiz *ConfigHandler) update(update map[string]string) error {
+	names := make([]string, 0, len(update))
+	for name := range update {
+		if thiz.values[name] == nil {
+			return fmt.Errorf("unknown flag %q", name)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var restore []func()
+	for _, name := range names {
+		value := thiz.values[name]
+		if r, ok := value.(restorer); ok {
+			restore = append(restore, r.save())
+		}
+		if err := setFrom(value, update[name], SourceAPI); err != nil {
+			for i := len(restore) - 1; i >= 0; i-- {
+				restore[i]()
+			}
+			return fmt.Errorf("invalid value for flag %q: %s", name, errorDetail(err))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package structflag_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/surajbarkale/structflag"
+)
+
+type handlerConfig struct {
+	Port     int
+	Password string `secret:"true"`
+	Token    string `secret:"true"`
+	Debug    bool
+}
+
+func serveConfig(t *testing.T, h http.Handler, method, body string) (*httptest.ResponseRecorder, map[string]interface{}) {
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(method, "/debug/config", strings.NewReader(body)))
+	var res map[string]interface{}
+	if w.Code == http.StatusOK {
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	}
+	return w, res
+}
+
+func TestConfigHandlerGet(t *testing.T) {
+	val := &handlerConfig{Port: 80, Password: "hunter2"}
+	sv, err := structflag.NewStructToFlagsConverter().Convert(val)
+	require.NoError(t, err)
+	h := structflag.NewConfigHandler(sv)
+	w, res := serveConfig(t, h, http.MethodGet, "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.Equal(t, map[string]interface{}{
+		"Port":     "80",
+		"Password": "******",
+		"Token":    "",
+		"Debug":    "false",
+	}, res)
+
+	w, _ = serveConfig(t, h, http.MethodPatch, `{"Port": "90"}`)
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	assert.Equal(t, 80, val.Port)
+}
+
+func TestConfigHandlerShowSource(t *testing.T) {
+	val := &handlerConfig{}
+	sv, err := structflag.NewStructToFlagsConverter().Convert(val)
+	require.NoError(t, err)
+	require.NoError(t, sv["Port"].Set("8080"))
+	h := structflag.NewConfigHandler(sv)
+	h.ShowSource = true
+	_, res := serveConfig(t, h, http.MethodGet, "")
+	assert.Equal(t, map[string]interface{}{"value": "8080", "source": "flag"}, res["Port"])
+	assert.Equal(t, map[string]interface{}{"value": "false", "source": "default"}, res["Debug"])
+}
+
+func TestConfigHandlerPatch(t *testing.T) {
+	val := &handlerConfig{Port: 80}
+	sv, err := structflag.NewStructToFlagsConverter().Convert(val)
+	require.NoError(t, err)
+	h := structflag.NewConfigHandler(sv)
+	h.AllowPatch = true
+	w, res := serveConfig(t, h, http.MethodPatch, `{"Port": "90", "Debug": "true"}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "90", res["Port"])
+	assert.Equal(t, handlerConfig{Port: 90, Debug: true}, *val)
+	assert.Equal(t, structflag.SourceAPI, sv["Port"].Source())
+
+	w, _ = serveConfig(t, h, http.MethodPatch, `{"Debug": "false", "Port": "x"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"Port"`)
+	assert.Equal(t, handlerConfig{Port: 90, Debug: true}, *val)
+
+	w, _ = serveConfig(t, h, http.MethodPatch, `{"Missing": "1"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w, _ = serveConfig(t, h, http.MethodPatch, `[1]`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w, _ = serveConfig(t, h, http.MethodDelete, "")
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}
+
+func TestHandlerOptions(t *testing.T) {
+	val := &handlerConfig{Port: 80, Password: "hunter2"}
+	h := structflag.Handler(val, structflag.WithSecretMask("xxx"), structflag.WithSource(), structflag.WithPatch())
+	w, res := serveConfig(t, h, http.MethodPatch, `{"Port": "90"}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 90, val.Port)
+	assert.Equal(t, map[string]interface{}{"value": "90", "source": "api"}, res["Port"])
+	assert.Equal(t, map[string]interface{}{"value": "xxx", "source": "default"}, res["Password"])
+
+	w, _ = serveConfig(t, structflag.Handler(val), http.MethodPatch, `{"Port": "100"}`)
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	assert.Panics(t, func() {
+		structflag.Handler(&struct {
+			Size int `format:"missing"`
+		}{})
+	})
+}
+
+func TestHandlerLocker(t *testing.T) {
+	val := &handlerConfig{Port: 80}
+	var mutex sync.RWMutex
+	h := structflag.Handler(val, structflag.WithPatch(), structflag.WithLocker(&mutex))
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			serveConfig(t, h, http.MethodPatch, fmt.Sprintf(`{"Port": "%d"}`, i))
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		mutex.RLock()
+		port := val.Port
+		mutex.RUnlock()
+		assert.True(t, port == 80 || port < 100)
+	}
+	<-done
+	assert.Equal(t, 99, val.Port)
+}
@@ -0,0 +1,87 @@
+package structflag
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// Contributor provides the configuration struct of an independent package,
+// e.g. a database driver or a plugin, to the application binding the flags.
+type Contributor interface {
+	// Config returns a pointer to the configuration struct.
+	Config() interface{}
+	// Prefix is prepended to the flag names of the struct, followed by the
+	// word separator. Empty string adds the flags without a prefix.
+	Prefix() string
+	// Group is the group of the values which do not have a group tag, shown as
+	// the title of their section in usage output.
+	Group() string
+}
+
+var (
+	contributorsLock sync.Mutex
+	contributors     []Contributor
+)
+
+// RegisterContributor adds a contributor to the list returned by Contributors.
+// It is usually called from init functions of the contributing packages.
+func RegisterContributor(contributor Contributor) {
+	contributorsLock.Lock()
+	defer contributorsLock.Unlock()
+	contributors = append(contributors, contributor)
+}
+
+// Contributors returns the registered contributors in registration order.
+func Contributors() []Contributor {
+	contributorsLock.Lock()
+	defer contributorsLock.Unlock()
+	return append([]Contributor{}, contributors...)
+}
+
+// NewContributor returns a contributor with given prefix, group and pointer to
+// configuration struct.
+func NewContributor(prefix, group string, config interface{}) Contributor {
+	return staticContributor{prefix, group, config}
+}
+
+type staticContributor struct {
+	prefix string
+	group  string
+	config interface{}
+}
+
+func (thiz staticContributor) Config() interface{} { return thiz.config }
+func (thiz staticContributor) Prefix() string      { return thiz.prefix }
+func (thiz staticContributor) Group() string       { return thiz.group }
+
+// ConvertContributors generates the flag values for the configuration structs
+// of the contributors. Contributors can not share a prefix and name collisions
+// between their flags are handled in the same way as in Convert.
+func (thiz *StructToFlagsConverter) ConvertContributors(contributors ...Contributor) (map[string]Value, error) {
+	output := map[string]Value{}
+	prefixes := map[string]bool{}
+	for _, contributor := range contributors {
+		prefix := thiz.Prefix
+		if contributor.Prefix() != "" {
+			prefix += contributor.Prefix() + thiz.WordSeparator
+		}
+		if prefixes[prefix] && prefix != thiz.Prefix {
+			return nil, fmt.Errorf("prefix %q is used by multiple contributors", contributor.Prefix())
+		}
+		prefixes[prefix] = true
+		existing := make(map[string]bool, len(output))
+		for name := range output {
+			existing[name] = true
+		}
+		if err := thiz.convertStruct(prefix, reflect.ValueOf(contributor.Config()), output); err != nil {
+			return nil, err
+		}
+		for name, value := range output {
+			if rv, ok := value.(*reflectedValue); ok && !existing[name] && rv.group == "" {
+				rv.group = contributor.Group()
+			}
+		}
+	}
+	return output, nil
+}
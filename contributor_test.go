@@ -0,0 +1,73 @@
+package structflag_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/surajbarkale/structflag"
+)
+
+type cacheConfig struct {
+	Size int  `description:"Cache size"`
+	TLS  bool `group:"tls"`
+}
+
+type dbConfig struct {
+	URL  string
+	Size int
+}
+
+func TestConvertContributors(t *testing.T) {
+	cache, db, app := &cacheConfig{Size: 10}, &dbConfig{}, &struct{ Debug bool }{}
+	values, err := structflag.DefaultStructToFlagsConverter.ConvertContributors(
+		structflag.NewContributor("", "", app),
+		structflag.NewContributor("cache", "Cache", cache),
+		structflag.NewContributor("db", "Database", db),
+	)
+	require.NoError(t, err)
+	assert.Len(t, values, 5)
+	assert.Equal(t, "", values["Debug"].Group())
+	assert.Equal(t, "Cache", values["cache-Size"].Group())
+	assert.Equal(t, "tls", values["cache-TLS"].Group())
+	assert.Equal(t, "Database", values["db-URL"].Group())
+	require.NoError(t, values["db-Size"].Set("5"))
+	assert.Equal(t, 5, db.Size)
+	assert.Equal(t, 10, cache.Size)
+
+	_, err = structflag.DefaultStructToFlagsConverter.ConvertContributors(
+		structflag.NewContributor("db", "", &dbConfig{}),
+		structflag.NewContributor("db", "", &dbConfig{}),
+	)
+	assert.EqualError(t, err, `prefix "db" is used by multiple contributors`)
+	_, err = structflag.DefaultStructToFlagsConverter.ConvertContributors(
+		structflag.NewContributor("", "", &dbConfig{}),
+		structflag.NewContributor("", "", &cacheConfig{}),
+	)
+	assert.Error(t, err)
+}
+
+func TestBindContributors(t *testing.T) {
+	cache := &cacheConfig{}
+	structflag.RegisterContributor(structflag.NewContributor("cache", "Cache", cache))
+	assert.NotEmpty(t, structflag.Contributors())
+	binder := structflag.NewBinder("app", "")
+	require.NoError(t, binder.BindContributors())
+	_, err := binder.Parse([]string{"-cache-Size", "3"})
+	require.NoError(t, err)
+	assert.Equal(t, 3, cache.Size)
+
+	var out bytes.Buffer
+	structflag.PrintBinders(&out, binder)
+	assert.Equal(t, `app flags:
+
+Cache flags:
+  -cache-Size int
+    	Cache size
+
+tls flags:
+  -cache-TLS
+`, out.String())
+}
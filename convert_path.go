@@ -0,0 +1,64 @@
+package structflag
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ConvertPath generates the flag values for the nested struct at path. The path
+// uses the same naming as flag names generated by Convert, e.g. "Server" or
+// "Server-TLS", without the Prefix. Generated flag names are relative to the
+// nested struct and start with Prefix. Set Prefix to path followed by
+// WordSeparator to get the same names as Convert. You must pass a pointer to
+// the value.
+func (thiz *StructToFlagsConverter) ConvertPath(input interface{}, path string) (map[string]Value, error) {
+	target, err := thiz.findStruct("", reflect.ValueOf(input), path)
+	if err != nil {
+		return nil, err
+	}
+	output := map[string]Value{}
+	if err := thiz.convertStruct(thiz.Prefix, target, output); err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+// findStruct returns the nested struct at path. Nil struct pointers on the way
+// are initialized just like in Convert.
+func (thiz *StructToFlagsConverter) findStruct(prefix string, input reflect.Value, path string) (reflect.Value, error) {
+	for input.Kind() == reflect.Ptr || input.Kind() == reflect.Interface {
+		input = input.Elem()
+	}
+	inputType := input.Type()
+	for i := 0; i < input.NumField(); i++ {
+		field := input.Field(i)
+		if !field.CanSet() {
+			continue
+		}
+		fieldName, ok := thiz.fieldName(inputType.Field(i))
+		if !ok {
+			continue
+		}
+		fieldPath := prefix + thiz.NameConverterFunc(fieldName)
+		if fieldPath != path && !strings.HasPrefix(path, fieldPath+thiz.WordSeparator) {
+			continue
+		}
+		if !isStructField(field) {
+			if fieldPath == path {
+				return reflect.Value{}, fmt.Errorf("field %s is not a struct", path)
+			}
+			continue
+		}
+		if field.Kind() == reflect.Ptr && field.IsNil() {
+			field.Set(reflect.New(field.Type().Elem()))
+		}
+		if fieldPath == path {
+			return field, nil
+		}
+		if res, err := thiz.findStruct(fieldPath+thiz.WordSeparator, field, path); err == nil {
+			return res, nil
+		}
+	}
+	return reflect.Value{}, fmt.Errorf("struct field %s not found", path)
+}
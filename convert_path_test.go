@@ -0,0 +1,59 @@
+package structflag_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/surajbarkale/structflag"
+)
+
+type serverConfig struct {
+	Addr string
+	TLS  *struct {
+		Cert string
+		Key  string
+	}
+}
+
+type appConfig struct {
+	Server serverConfig
+	Debug  bool
+}
+
+func TestConvertPath(t *testing.T) {
+	val := &appConfig{}
+	c := structflag.NewStructToFlagsConverter()
+	sv, err := c.ConvertPath(val, "Server")
+	require.NoError(t, err)
+	assert.Len(t, sv, 3)
+	assert.Contains(t, sv, "Addr")
+	assert.Contains(t, sv, "TLS-Cert")
+	assert.Contains(t, sv, "TLS-Key")
+	require.NoError(t, sv["Addr"].Set(":80"))
+	assert.Equal(t, ":80", val.Server.Addr)
+}
+
+func TestConvertNestedPathWithPrefix(t *testing.T) {
+	val := &appConfig{}
+	c := structflag.NewStructToFlagsConverter()
+	c.Prefix = "Server-TLS-"
+	sv, err := c.ConvertPath(val, "Server-TLS")
+	require.NoError(t, err)
+	assert.Len(t, sv, 2)
+	require.Contains(t, sv, "Server-TLS-Cert")
+	require.NotNil(t, val.Server.TLS)
+	require.NoError(t, sv["Server-TLS-Cert"].Set("cert.pem"))
+	assert.Equal(t, "cert.pem", val.Server.TLS.Cert)
+}
+
+func TestConvertPathErrors(t *testing.T) {
+	c := structflag.NewStructToFlagsConverter()
+	_, err := c.ConvertPath(&appConfig{}, "Debug")
+	assert.Error(t, err)
+	_, err = c.ConvertPath(&appConfig{}, "Client")
+	assert.Error(t, err)
+	_, err = c.ConvertPath(&appConfig{}, "Server-Missing")
+	assert.Error(t, err)
+}
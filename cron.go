@@ -0,0 +1,200 @@
+package structflag
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CronSpec is a cron expression with 5 fields (minute, hour, day of month,
+// month, day of week) or 6 fields with leading seconds. Fields accept "*",
+// numbers, ranges, lists and steps, months and days of week also accept three
+// letter names. Descriptors @yearly, @monthly, @weekly, @daily and @hourly are
+// supported too. Expressions are validated when they are set.
+type CronSpec string
+
+var cronSpecType = reflect.TypeOf(CronSpec(""))
+
+// cronCodec validates CronSpec values.
+type cronCodec struct{}
+
+func init() {
+	RegisterCodec(cronCodec{})
+}
+
+// Match returns true for CronSpec.
+func (cronCodec) Match(t reflect.Type) bool {
+	return t == cronSpecType
+}
+
+// Decode validates the expression. Empty string is accepted as no schedule.
+func (cronCodec) Decode(s string, val reflect.Value) error {
+	if s != "" {
+		if _, err := CronSpec(s).Schedule(); err != nil {
+			return err
+		}
+	}
+	val.SetString(s)
+	return nil
+}
+
+// Encode returns the expression.
+func (cronCodec) Encode(val reflect.Value) (string, error) {
+	return val.String(), nil
+}
+
+// CronSchedule is a parsed cron expression. Each field is a bit set of
+// matching values.
+type CronSchedule struct {
+	second, minute, hour, dom, month, dow uint64
+	// Day matches if either day of month or day of week matches when both are
+	// restricted, just like in crontab.
+	domRestricted, dowRestricted bool
+}
+
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var (
+	monthNames = []string{"", "jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}
+	dowNames   = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+)
+
+// Schedule parses the expression.
+func (thiz CronSpec) Schedule() (*CronSchedule, error) {
+	spec := strings.TrimSpace(string(thiz))
+	if strings.HasPrefix(spec, "@") {
+		expanded, ok := cronDescriptors[strings.ToLower(spec)]
+		if !ok {
+			return nil, fmt.Errorf("unknown cron descriptor %q", spec)
+		}
+		spec = expanded
+	}
+	fields := strings.Fields(spec)
+	switch len(fields) {
+	case 5:
+		fields = append([]string{"0"}, fields...)
+	case 6:
+	default:
+		return nil, fmt.Errorf("cron expression %q must have 5 or 6 fields", string(thiz))
+	}
+	res := &CronSchedule{}
+	var err error
+	parts := []struct {
+		bits     *uint64
+		min, max int
+		names    []string
+	}{
+		{&res.second, 0, 59, nil},
+		{&res.minute, 0, 59, nil},
+		{&res.hour, 0, 23, nil},
+		{&res.dom, 1, 31, nil},
+		{&res.month, 1, 12, monthNames},
+		{&res.dow, 0, 7, dowNames},
+	}
+	for i, part := range parts {
+		if *part.bits, err = parseCronField(fields[i], part.min, part.max, part.names); err != nil {
+			return nil, fmt.Errorf("invalid cron field %q: %v", fields[i], err)
+		}
+	}
+	// Sunday can be written as 0 or 7
+	if res.dow&(1<<7) != 0 {
+		res.dow |= 1
+	}
+	res.domRestricted = fields[3] != "*" && fields[3] != "?"
+	res.dowRestricted = fields[5] != "*" && fields[5] != "?"
+	return res, nil
+}
+
+// parseCronField returns the bit set of values matching the field.
+func parseCronField(field string, min, max int, names []string) (uint64, error) {
+	var bits uint64
+	for _, item := range strings.Split(field, ",") {
+		rangePart, step := item, 1
+		if idx := strings.Index(item, "/"); idx >= 0 {
+			var err error
+			if step, err = strconv.Atoi(item[idx+1:]); err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step %q", item[idx+1:])
+			}
+			rangePart = item[:idx]
+		}
+		low, high := min, max
+		if rangePart != "*" && rangePart != "?" {
+			bounds := strings.SplitN(rangePart, "-", 2)
+			var err error
+			if low, err = parseCronNumber(bounds[0], names); err != nil {
+				return 0, err
+			}
+			high = low
+			if len(bounds) == 2 {
+				if high, err = parseCronNumber(bounds[1], names); err != nil {
+					return 0, err
+				}
+			} else if step > 1 {
+				high = max
+			}
+		}
+		if low < min || high > max || low > high {
+			return 0, fmt.Errorf("range %d-%d is outside of %d-%d", low, high, min, max)
+		}
+		for v := low; v <= high; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+func parseCronNumber(s string, names []string) (int, error) {
+	for i, name := range names {
+		if name != "" && strings.EqualFold(s, name) {
+			return i, nil
+		}
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", s)
+	}
+	return n, nil
+}
+
+// Next returns the first time matching the schedule after t, in the location of
+// t. Zero time is returned if there is no such time within five years.
+func (thiz *CronSchedule) Next(t time.Time) time.Time {
+	t = t.Add(time.Second - time.Duration(t.Nanosecond())).Truncate(time.Second)
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		switch {
+		case thiz.month&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !thiz.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		case thiz.hour&(1<<uint(t.Hour())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+		case thiz.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Truncate(time.Minute).Add(time.Minute)
+		case thiz.second&(1<<uint(t.Second())) == 0:
+			t = t.Add(time.Second)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func (thiz *CronSchedule) dayMatches(t time.Time) bool {
+	dom := thiz.dom&(1<<uint(t.Day())) != 0
+	dow := thiz.dow&(1<<uint(t.Weekday())) != 0
+	if thiz.domRestricted && thiz.dowRestricted {
+		return dom || dow
+	}
+	return dom && dow
+}
@@ -0,0 +1,49 @@
+package structflag_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/surajbarkale/structflag"
+)
+
+func TestCronValues(t *testing.T) {
+	val := &struct {
+		Backup structflag.CronSpec
+	}{Backup: "@daily"}
+	values, err := structflag.DefaultStructToFlagsConverter.Convert(val)
+	require.NoError(t, err)
+	assert.Equal(t, "@daily", values["Backup"].Default())
+
+	require.NoError(t, values["Backup"].Set("*/15 9-17 * * mon-fri"))
+	assert.Equal(t, structflag.CronSpec("*/15 9-17 * * mon-fri"), val.Backup)
+	for _, invalid := range []string{"* * *", "60 * * * *", "* * * * 8", "*/0 * * * *", "@sometimes", "a * * * *"} {
+		assert.Error(t, values["Backup"].Set(invalid), invalid)
+	}
+	assert.Equal(t, structflag.CronSpec("*/15 9-17 * * mon-fri"), val.Backup)
+	require.NoError(t, values["Backup"].Set(""))
+}
+
+func TestCronSchedule(t *testing.T) {
+	start := time.Date(2021, 3, 5, 17, 50, 10, 5, time.UTC) // Friday
+	for _, tc := range []struct {
+		spec structflag.CronSpec
+		next time.Time
+	}{
+		{"*/15 9-17 * * mon-fri", time.Date(2021, 3, 8, 9, 0, 0, 0, time.UTC)},
+		{"* * * * *", time.Date(2021, 3, 5, 17, 51, 0, 0, time.UTC)},
+		{"30 * * * * *", time.Date(2021, 3, 5, 17, 50, 30, 0, time.UTC)},
+		{"0 0 1 jan *", time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 12 13 * 5", time.Date(2021, 3, 12, 12, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2021, 3, 7, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2021, 3, 5, 18, 0, 0, 0, time.UTC)},
+		{"0 0 30 2 *", time.Time{}},
+	} {
+		schedule, err := tc.spec.Schedule()
+		require.NoError(t, err, tc.spec)
+		assert.Equal(t, tc.next, schedule.Next(start), tc.spec)
+	}
+}
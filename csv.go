@@ -0,0 +1,127 @@
+package structflag
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"strings"
+)
+
+// csvCodec converts slices of structs from CSV with a header row. Header
+// columns are matched to fields by their names as they are used in flag names.
+// Values starting with "@" are names of files containing the CSV.
+type csvCodec struct {
+	converter *StructToFlagsConverter
+}
+
+func init() {
+	RegisterFormat("csv", csvCodec{})
+}
+
+func (thiz csvCodec) withConverter(converter *StructToFlagsConverter) Codec {
+	return csvCodec{converter: converter}
+}
+
+// Match returns true for slices of structs or pointers to structs.
+func (thiz csvCodec) Match(t reflect.Type) bool {
+	if t.Kind() != reflect.Slice {
+		return false
+	}
+	elem := t.Elem()
+	if elem.Kind() == reflect.Ptr {
+		elem = elem.Elem()
+	}
+	return elem.Kind() == reflect.Struct && !isCodecType(elem)
+}
+
+// Decode parses the rows. Empty cells leave fields with zero values.
+func (thiz csvCodec) Decode(s string, val reflect.Value) error {
+	if strings.HasPrefix(s, "@") {
+		data, err := ioutil.ReadFile(s[1:])
+		if err != nil {
+			return err
+		}
+		s = string(data)
+	}
+	records, err := csv.NewReader(strings.NewReader(s)).ReadAll()
+	if err != nil {
+		return err
+	}
+	res := reflect.MakeSlice(val.Type(), 0, len(records))
+	if len(records) == 0 {
+		val.Set(reflect.Zero(val.Type()))
+		return nil
+	}
+	elemType := val.Type().Elem()
+	structType := elemType
+	if structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+	converter := formatConverter(thiz.converter)
+	header := records[0]
+	for _, column := range header {
+		if _, ok := converter.fieldByKey(reflect.New(structType).Elem(), column); !ok {
+			return fmt.Errorf("unknown column %q", column)
+		}
+	}
+	for line, record := range records[1:] {
+		row := reflect.New(structType).Elem()
+		for i, cell := range record {
+			if cell == "" {
+				continue
+			}
+			field, _ := converter.fieldByKey(row, header[i])
+			if err := decodeString(cell, field); err != nil {
+				return fmt.Errorf("invalid value for %s on line %d: %v", header[i], line+2, err)
+			}
+		}
+		if elemType.Kind() == reflect.Ptr {
+			row = row.Addr()
+		}
+		res = reflect.Append(res, row)
+	}
+	val.Set(res)
+	return nil
+}
+
+// Encode returns CSV with a header containing all fields.
+func (thiz csvCodec) Encode(val reflect.Value) (string, error) {
+	structType := val.Type().Elem()
+	if structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+	converter := formatConverter(thiz.converter)
+	var header []string
+	var indexes []int
+	for i := 0; i < structType.NumField(); i++ {
+		if key, ok := converter.fieldKey(structType.Field(i)); ok {
+			header = append(header, key)
+			indexes = append(indexes, i)
+		}
+	}
+	var b bytes.Buffer
+	w := csv.NewWriter(&b)
+	if err := w.Write(header); err != nil {
+		return "", err
+	}
+	for i := 0; i < val.Len(); i++ {
+		row := val.Index(i)
+		if row.Kind() == reflect.Ptr {
+			if row.IsNil() {
+				continue
+			}
+			row = row.Elem()
+		}
+		record := make([]string, len(indexes))
+		for j, index := range indexes {
+			record[j] = encodeString(row.Field(index))
+		}
+		if err := w.Write(record); err != nil {
+			return "", err
+		}
+	}
+	w.Flush()
+	return b.String(), w.Error()
+}
@@ -0,0 +1,44 @@
+package structflag_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/surajbarkale/structflag"
+)
+
+type user struct {
+	Name  string
+	Age   int
+	Admin bool
+}
+
+func TestCSVFormat(t *testing.T) {
+	val := &struct {
+		Users  []user  `format:"csv"`
+		Admins []*user `format:"csv"`
+	}{}
+	values, err := structflag.DefaultStructToFlagsConverter.Convert(val)
+	require.NoError(t, err)
+
+	require.NoError(t, values["Users"].Set("name,age\nalice,30\n\"Bob, Jr.\",\n"))
+	assert.Equal(t, []user{{Name: "alice", Age: 30}, {Name: "Bob, Jr."}}, val.Users)
+	assert.Equal(t, "Name,Age,Admin\nalice,30,false\n\"Bob, Jr.\",0,false\n", values["Users"].String())
+
+	dir, err := ioutil.TempDir("", "structflag")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	file := writeTempFile(t, dir, "admins.csv", "Admin,Name\ntrue,root\n")
+	require.NoError(t, values["Admins"].Set("@"+file))
+	assert.Equal(t, []*user{{Name: "root", Admin: true}}, val.Admins)
+
+	assert.EqualError(t, values["Users"].Set("name,email\nx,y"), `invalid value "name,email\nx,y" for flag Users: unknown column "email"`)
+	assert.EqualError(t, values["Users"].Set("name,age\nx,y"), `invalid value "name,age\nx,y" for flag Users: invalid value for age on line 2: strconv.ParseInt: parsing "y": invalid syntax`)
+	assert.Error(t, values["Users"].Set("name,age\nx"))
+	assert.Error(t, values["Users"].Set("@missing.csv"))
+	assert.Len(t, val.Users, 2)
+}
@@ -0,0 +1,74 @@
+package structflag
+
+import (
+	"reflect"
+)
+
+// deepCopy returns a copy of val which does not share pointers, maps or slices
+// with the original. Unexported struct fields are copied as is.
+func deepCopy(val reflect.Value) reflect.Value {
+	if !val.IsValid() {
+		return val
+	}
+	res := reflect.New(val.Type()).Elem()
+	switch val.Kind() {
+	case reflect.Ptr:
+		if !val.IsNil() {
+			ptr := reflect.New(val.Type().Elem())
+			ptr.Elem().Set(deepCopy(val.Elem()))
+			res.Set(ptr)
+		}
+	case reflect.Interface:
+		if !val.IsNil() {
+			res.Set(deepCopy(val.Elem()))
+		}
+	case reflect.Map:
+		if !val.IsNil() {
+			res.Set(reflect.MakeMapWithSize(val.Type(), val.Len()))
+			for _, key := range val.MapKeys() {
+				res.SetMapIndex(key, deepCopy(val.MapIndex(key)))
+			}
+		}
+	case reflect.Slice:
+		if !val.IsNil() {
+			res.Set(reflect.MakeSlice(val.Type(), val.Len(), val.Len()))
+			for i := 0; i < val.Len(); i++ {
+				res.Index(i).Set(deepCopy(val.Index(i)))
+			}
+		}
+	case reflect.Array:
+		for i := 0; i < val.Len(); i++ {
+			res.Index(i).Set(deepCopy(val.Index(i)))
+		}
+	case reflect.Struct:
+		if isAtomic(val.Type()) {
+			atomicStore(res, deepCopy(atomicLoad(addressable(val))))
+			break
+		}
+		res.Set(val)
+		for i := 0; i < val.NumField(); i++ {
+			if res.Field(i).CanSet() {
+				res.Field(i).Set(deepCopy(val.Field(i)))
+			}
+		}
+	default:
+		res.Set(val)
+	}
+	return res
+}
+
+// assign copies src into dst. Existing pointer targets are updated in place
+// instead of being replaced, matching the behavior of Set.
+func assign(dst, src reflect.Value) {
+	if dst.Kind() == reflect.Ptr && !dst.IsNil() && !src.IsNil() {
+		assign(dst.Elem(), src.Elem())
+		return
+	}
+	// Atomic values are replaced using Store like Set does, so that concurrent
+	// readers are safe
+	if isAtomic(dst.Type()) {
+		atomicStore(dst, atomicLoad(addressable(src)))
+		return
+	}
+	dst.Set(src)
+}
@@ -0,0 +1,33 @@
+package structflag
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"unicode/utf8"
+)
+
+// DefaultMaxWidth is the length of JSON defaults shown by PrintDefaults before
+// they are truncated.
+const DefaultMaxWidth = 40
+
+// formatDefault returns the default shown in help. JSON objects and arrays
+// longer than maxWidth are replaced by "{...}" or "[...]" with their length,
+// unless full is true, which pretty-prints them instead. A maxWidth of zero
+// disables truncation.
+func formatDefault(def string, maxWidth int, full bool, messages *Messages) string {
+	if def == "" || (def[0] != '{' && def[0] != '[') || !json.Valid([]byte(def)) {
+		return def
+	}
+	if full {
+		var b bytes.Buffer
+		if err := json.Indent(&b, []byte(def), "", "  "); err != nil {
+			return def
+		}
+		return b.String()
+	}
+	if n := utf8.RuneCountInString(def); maxWidth > 0 && n > maxWidth {
+		return fmt.Sprintf(messages.Truncated, def[:1]+"..."+def[len(def)-1:], n)
+	}
+	return def
+}
@@ -0,0 +1,85 @@
+package structflag_test
+
+import (
+	"bytes"
+	"flag"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/surajbarkale/structflag"
+)
+
+type limitsOptions struct {
+	Limits map[string]int `description:"Limits"`
+	Tags   []string
+}
+
+func newLimitsOptions() *limitsOptions {
+	return &limitsOptions{
+		Limits: map[string]int{"cpu": 2, "memory": 1024, "connections": 100},
+		Tags:   []string{"a"},
+	}
+}
+
+func TestPrintDefaultsTruncated(t *testing.T) {
+	values, err := structflag.DefaultStructToFlagsConverter.Convert(newLimitsOptions())
+	require.NoError(t, err)
+	var out bytes.Buffer
+	structflag.PrintDefaults(&out, values)
+	assert.Equal(t, "  -Limits value\n    \tLimits (default {...} (41 characters))\n  -Tags value\n    \t(default [\"a\"])\n", out.String())
+
+	out.Reset()
+	structflag.PrintFullDefaults(&out, values)
+	assert.Equal(t, `  -Limits value
+    	Limits (default {
+    	  "connections": 100,
+    	  "cpu": 2,
+    	  "memory": 1024
+    	})
+  -Tags value
+    	(default [
+    	  "a"
+    	])
+`, out.String())
+}
+
+func TestHelpPrinterTruncated(t *testing.T) {
+	values, err := structflag.DefaultStructToFlagsConverter.Convert(newLimitsOptions())
+	require.NoError(t, err)
+	printer := structflag.NewHelpPrinter()
+	printer.Width = 80
+	var out bytes.Buffer
+	printer.PrintDefaults(&out, values)
+	assert.Equal(t, "  -Limits value  Limits (default {...} (41 characters))\n  -Tags value    (default [\"a\"])\n", out.String())
+
+	out.Reset()
+	printer.Full = true
+	printer.PrintDefaults(&out, values)
+	assert.Equal(t, `  -Limits value  Limits (default {
+                   "connections": 100,
+                   "cpu": 2,
+                   "memory": 1024
+                 })
+  -Tags value    (default [
+                   "a"
+                 ])
+`, out.String())
+}
+
+func TestParseHelpFull(t *testing.T) {
+	var out bytes.Buffer
+	parser := newTestParser(&out)
+	parser.HelpFullFlag = "help-full"
+	_, err := parser.Parse(newLimitsOptions(), []string{"--help-full"})
+	assert.Equal(t, flag.ErrHelp, err)
+	assert.Contains(t, out.String(), "Usage of test:\n  -Limits value\n    \tLimits (default {\n    \t  \"connections\": 100,\n")
+	assert.Contains(t, out.String(), "  -help-full\n    \tShow this help with complete default values and exit\n")
+
+	out.Reset()
+	parser.FullUsage = nil
+	_, err = parser.Parse(newLimitsOptions(), []string{"--help-full"})
+	assert.Equal(t, flag.ErrHelp, err)
+	assert.Contains(t, out.String(), "(default {...} (41 characters))")
+}
@@ -0,0 +1,46 @@
+package structflag
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// checkDependencies verifies requires and conflicts tags of values which have
+// a value. Values have a value if they are not zero values or empty slices or
+// maps.
+func (thiz *Parser) checkDependencies(values map[string]Value) error {
+	messages := messagesOrDefault(thiz.Messages)
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value := values[name]
+		if !hasValue(value) {
+			continue
+		}
+		for _, other := range tagList(value.Field().Tag, thiz.RequiresTag) {
+			if values[other] == nil {
+				return fmt.Errorf(messages.RequiresUnknown, name, other)
+			}
+			if !hasValue(values[other]) {
+				return fmt.Errorf(messages.Requires, name, other)
+			}
+		}
+		for _, other := range tagList(value.Field().Tag, thiz.ConflictsTag) {
+			if values[other] == nil {
+				return fmt.Errorf(messages.ConflictsUnknown, name, other)
+			}
+			if hasValue(values[other]) {
+				return fmt.Errorf(messages.Conflicts, name, other)
+			}
+		}
+	}
+	return nil
+}
+
+func hasValue(value Value) bool {
+	return !isEmpty(reflect.ValueOf(value.Get()))
+}
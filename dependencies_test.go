@@ -0,0 +1,55 @@
+package structflag_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type tlsConfig struct {
+	TLSCert  string `requires:"TLSKey" conflicts:"Insecure"`
+	TLSKey   string `requires:"TLSCert"`
+	Insecure bool
+	Hosts    []string `requires:"Missing"`
+}
+
+func TestParseDependencies(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		err  string
+	}{
+		{"none", nil, ""},
+		{"both", []string{"--TLSCert", "c", "--TLSKey", "k"}, ""},
+		{"insecure", []string{"--Insecure"}, ""},
+		{"missing key", []string{"--TLSCert", "c"}, "flag -TLSCert requires flag -TLSKey"},
+		{"missing cert", []string{"--TLSKey", "k"}, "flag -TLSKey requires flag -TLSCert"},
+		{"conflict", []string{"--TLSCert", "c", "--TLSKey", "k", "--Insecure"}, "flag -TLSCert conflicts with flag -Insecure"},
+		{"disabled insecure", []string{"--TLSCert", "c", "--TLSKey", "k", "--Insecure=false"}, ""},
+		{"unknown", []string{"--Hosts", `["a"]`}, "flag -Hosts requires unknown flag -Missing"},
+		{"empty slice", []string{"--Hosts", `[]`}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newTestParser(&bytes.Buffer{}).Parse(&tlsConfig{}, tt.args)
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.Equal(t, tt.err, err.Error())
+			}
+		})
+	}
+}
+
+func TestParseDependenciesUseDefaults(t *testing.T) {
+	_, err := newTestParser(&bytes.Buffer{}).Parse(&tlsConfig{TLSKey: "default.key"}, []string{"--TLSCert", "c"})
+	assert.NoError(t, err)
+
+	p := newTestParser(&bytes.Buffer{})
+	p.RequiresTag = ""
+	_, err = p.Parse(&tlsConfig{}, []string{"--TLSCert", "c"})
+	assert.NoError(t, err)
+}
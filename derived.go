@@ -0,0 +1,39 @@
+package structflag
+
+import (
+	"fmt"
+	"sort"
+)
+
+// SourceDerived is the source of values set from derived defaults.
+const SourceDerived Source = "derived"
+
+// applyDerivedDefaults computes derived defaults for the values which were not
+// set by any source. All defaults are computed before any of them is set.
+func (thiz *Parser) applyDerivedDefaults(values map[string]Value) error {
+	canSet := thiz.canSet(SourceDerived)
+	names := make([]string, 0, len(thiz.DerivedDefaults))
+	for name := range thiz.DerivedDefaults {
+		if values[name] == nil {
+			return fmt.Errorf("derived default for unknown flag %s", name)
+		}
+		if canSet(values[name]) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	derived := make([]string, len(names))
+	for i, name := range names {
+		s, err := thiz.DerivedDefaults[name](values)
+		if err != nil {
+			return fmt.Errorf("can not derive default for flag %s: %v", name, err)
+		}
+		derived[i] = s
+	}
+	for i, name := range names {
+		if err := replaceFrom(values[name], derived[i], SourceDerived); err != nil {
+			return fmt.Errorf("invalid derived value %q for flag %s: %s", derived[i], name, errorDetail(err))
+		}
+	}
+	return nil
+}
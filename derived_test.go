@@ -0,0 +1,60 @@
+package structflag_test
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/surajbarkale/structflag"
+)
+
+type listenConfig struct {
+	ListenAddr  string
+	MetricsAddr string
+}
+
+func newDerivedParser() *structflag.Parser {
+	p := newTestParser(&bytes.Buffer{})
+	p.DerivedDefaults = map[string]func(map[string]structflag.Value) (string, error){
+		"MetricsAddr": func(values map[string]structflag.Value) (string, error) {
+			host, _, err := net.SplitHostPort(values["ListenAddr"].String())
+			if err != nil {
+				return "", err
+			}
+			return net.JoinHostPort(host, "9090"), nil
+		},
+	}
+	return p
+}
+
+func TestParseDerivedDefaults(t *testing.T) {
+	val := &listenConfig{ListenAddr: "localhost:80"}
+	p := newDerivedParser()
+	_, err := p.Parse(val, []string{"--ListenAddr", "10.0.0.1:8080"})
+	require.NoError(t, err)
+	assert.Equal(t, listenConfig{"10.0.0.1:8080", "10.0.0.1:9090"}, *val)
+	assert.Equal(t, structflag.SourceDerived, p.Values()["MetricsAddr"].Source())
+
+	val = &listenConfig{ListenAddr: "localhost:80"}
+	_, err = p.Parse(val, []string{"--MetricsAddr", ":7000"})
+	require.NoError(t, err)
+	assert.Equal(t, listenConfig{"localhost:80", ":7000"}, *val)
+}
+
+func TestParseDerivedDefaultErrors(t *testing.T) {
+	p := newDerivedParser()
+	_, err := p.Parse(&listenConfig{ListenAddr: "invalid"}, nil)
+	assert.Error(t, err)
+
+	p.DerivedDefaults = map[string]func(map[string]structflag.Value) (string, error){
+		"Missing": func(map[string]structflag.Value) (string, error) {
+			return "", errors.New("not called")
+		},
+	}
+	_, err = p.Parse(&listenConfig{}, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,45 @@
+package structflag
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Change describes a difference in a single flag value.
+type Change struct {
+	// Old is the string representation of the value in the first struct.
+	Old string
+	// New is the string representation of the value in the second struct.
+	New string
+}
+
+// Diff compares two values of the same struct type and returns changed values
+// keyed by flag name. Values are compared using their string representation.
+// The inputs are not modified. You must pass pointers to the values.
+func (thiz *StructToFlagsConverter) Diff(a, b interface{}) (map[string]Change, error) {
+	if reflect.TypeOf(a) != reflect.TypeOf(b) {
+		return nil, fmt.Errorf("can not compare %T with %T", a, b)
+	}
+	// Convert initializes nil struct pointers so work on copies
+	oldValues, err := thiz.Convert(deepCopy(reflect.ValueOf(a)).Interface())
+	if err != nil {
+		return nil, err
+	}
+	newValues, err := thiz.Convert(deepCopy(reflect.ValueOf(b)).Interface())
+	if err != nil {
+		return nil, err
+	}
+	changes := map[string]Change{}
+	for name, oldValue := range oldValues {
+		oldString, newString := oldValue.String(), newValues[name].String()
+		if oldString != newString {
+			changes[name] = Change{Old: oldString, New: newString}
+		}
+	}
+	return changes, nil
+}
+
+// Diff compares two values using DefaultStructToFlagsConverter.
+func Diff(a, b interface{}) (map[string]Change, error) {
+	return DefaultStructToFlagsConverter.Diff(a, b)
+}
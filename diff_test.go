@@ -0,0 +1,45 @@
+package structflag_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/surajbarkale/structflag"
+)
+
+func TestDiff(t *testing.T) {
+	i := 4
+	a := &param{
+		Nested:   nested{Int: 1, IntPtr: &i},
+		String:   "same",
+		IntArray: []int{1, 2},
+	}
+	b := &param{
+		Nested:    nested{Int: 2},
+		String:    "same",
+		IntArray:  []int{1, 3},
+		NestedPtr: &nested{Float: 1.5},
+	}
+	changes, err := structflag.Diff(a, b)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]structflag.Change{
+		"Nested-Int":      {Old: "1", New: "2"},
+		"Nested-IntPtr":   {Old: "4", New: ""},
+		"IntArray":        {Old: "[1,2]", New: "[1,3]"},
+		"NestedPtr-Float": {Old: "0", New: "1.5"},
+	}, changes)
+	assert.Nil(t, a.NestedPtr)
+}
+
+func TestDiffEqual(t *testing.T) {
+	changes, err := structflag.Diff(&param{String: "x"}, &param{String: "x"})
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+}
+
+func TestDiffTypeMismatch(t *testing.T) {
+	_, err := structflag.Diff(&param{}, &nested{})
+	assert.Error(t, err)
+}
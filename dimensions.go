@@ -0,0 +1,106 @@
+package structflag
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Dimensions is a size with an optional frame rate, e.g. "1920x1080@60". The
+// same text form can be used with other structs having integer Width and
+// Height fields and an optional numeric FPS field by adding `format:"dims"`
+// struct tag.
+type Dimensions struct {
+	Width  int
+	Height int
+	FPS    float64
+}
+
+var dimensionsType = reflect.TypeOf(Dimensions{})
+
+// dimsCodec converts "WxH" and "WxH@FPS" strings.
+type dimsCodec struct {
+	// any matches any struct with the dimension fields, otherwise only
+	// Dimensions is matched.
+	any bool
+}
+
+func init() {
+	RegisterCodec(dimsCodec{})
+	RegisterFormat("dims", dimsCodec{any: true})
+}
+
+// Match returns true for Dimensions or structs with dimension fields.
+func (thiz dimsCodec) Match(t reflect.Type) bool {
+	if !thiz.any {
+		return t == dimensionsType
+	}
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	for _, name := range []string{"Width", "Height"} {
+		if f, ok := t.FieldByName(name); !ok || !isIntKind(f.Type.Kind()) {
+			return false
+		}
+	}
+	if f, ok := t.FieldByName("FPS"); ok && !isIntKind(f.Type.Kind()) && !isFloatKind(f.Type.Kind()) {
+		return false
+	}
+	return true
+}
+
+// Decode parses the dimensions. Frame rate can only be given if the struct has
+// FPS field.
+func (thiz dimsCodec) Decode(s string, val reflect.Value) error {
+	size, rate := s, ""
+	if idx := strings.Index(s, "@"); idx >= 0 {
+		size, rate = s[:idx], s[idx+1:]
+	}
+	idx := strings.IndexAny(size, "xX")
+	if idx < 0 {
+		return fmt.Errorf("invalid dimensions %q, expected WxH or WxH@FPS", s)
+	}
+	res := reflect.New(val.Type()).Elem()
+	res.Set(val)
+	if err := decodeString(size[:idx], res.FieldByName("Width")); err != nil {
+		return fmt.Errorf("invalid width in %q: %v", s, err)
+	}
+	if err := decodeString(size[idx+1:], res.FieldByName("Height")); err != nil {
+		return fmt.Errorf("invalid height in %q: %v", s, err)
+	}
+	fps := res.FieldByName("FPS")
+	if rate != "" {
+		if !fps.IsValid() {
+			return fmt.Errorf("frame rate is not supported in %q", s)
+		}
+		if err := decodeString(rate, fps); err != nil {
+			return fmt.Errorf("invalid frame rate in %q: %v", s, err)
+		}
+	} else if fps.IsValid() {
+		fps.Set(reflect.Zero(fps.Type()))
+	}
+	val.Set(res)
+	return nil
+}
+
+// Encode returns "WxH" or "WxH@FPS" if the frame rate is not zero.
+func (thiz dimsCodec) Encode(val reflect.Value) (string, error) {
+	res := encodeString(val.FieldByName("Width")) + "x" + encodeString(val.FieldByName("Height"))
+	if fps := val.FieldByName("FPS"); fps.IsValid() && !isEmpty(fps) {
+		res += "@" + encodeString(fps)
+	}
+	return res, nil
+}
+
+func isIntKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+func isFloatKind(kind reflect.Kind) bool {
+	return kind == reflect.Float32 || kind == reflect.Float64
+}
@@ -0,0 +1,58 @@
+package structflag_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/surajbarkale/structflag"
+)
+
+type resolution struct {
+	Width, Height uint
+}
+
+func TestDimensionsValues(t *testing.T) {
+	val := &struct {
+		Output  structflag.Dimensions
+		Preview resolution  `format:"dims"`
+		Thumb   *resolution `format:"dims"`
+		Window  resolution
+	}{Output: structflag.Dimensions{Width: 1280, Height: 720}}
+	values, err := structflag.DefaultStructToFlagsConverter.Convert(val)
+	require.NoError(t, err)
+	assert.Contains(t, values, "Window-Width")
+	assert.NotContains(t, values, "Preview-Width")
+	assert.Equal(t, "1280x720", values["Output"].Default())
+	assert.Equal(t, "", values["Preview"].Default())
+
+	require.NoError(t, values["Output"].Set("1920x1080@59.94"))
+	assert.Equal(t, structflag.Dimensions{Width: 1920, Height: 1080, FPS: 59.94}, val.Output)
+	assert.Equal(t, "1920x1080@59.94", values["Output"].String())
+	require.NoError(t, values["Output"].Set("640X480"))
+	assert.Equal(t, structflag.Dimensions{Width: 640, Height: 480}, val.Output)
+
+	require.NoError(t, values["Preview"].Set("320x240"))
+	assert.Equal(t, resolution{320, 240}, val.Preview)
+	assert.Equal(t, "320x240", values["Preview"].String())
+	assert.EqualError(t, values["Preview"].Set("320x240@30"), `invalid value "320x240@30" for flag Preview: frame rate is not supported in "320x240@30"`)
+	for _, invalid := range []string{"320", "ax240", "320x-1"} {
+		assert.Error(t, values["Preview"].Set(invalid), invalid)
+	}
+	assert.Equal(t, resolution{320, 240}, val.Preview)
+
+	require.NoError(t, values["Thumb"].Set("64x64"))
+	assert.Equal(t, &resolution{64, 64}, val.Thumb)
+}
+
+func TestUnknownFormat(t *testing.T) {
+	_, err := structflag.DefaultStructToFlagsConverter.Convert(&struct {
+		Size resolution `format:"size"`
+	}{})
+	assert.EqualError(t, err, `invalid format for field Size: unknown format "size"`)
+	_, err = structflag.DefaultStructToFlagsConverter.Convert(&struct {
+		Size string `format:"dims"`
+	}{})
+	assert.EqualError(t, err, `invalid format for field Size: format "dims" can not be used with string`)
+}
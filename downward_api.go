@@ -0,0 +1,179 @@
+package structflag
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SourceDownwardAPI is the source of values set from Kubernetes downward API.
+const SourceDownwardAPI Source = "downwardAPI"
+
+var downwardFieldPattern = regexp.MustCompile(`^([a-zA-Z.]+)(?:\['([^']*)'\])?$`)
+
+// DownwardAPI sets values from Kubernetes pod metadata exposed using the
+// downward API. Fields select the metadata using the syntax of fieldRef, e.g.
+// `fromK8s:"metadata.labels['app']"` or `fromK8s:"metadata.namespace"`. The
+// metadata is read from environment variables listed in Env or from the files
+// of a downward API volume. Metadata which is not available is ignored, so the
+// same configuration works outside of Kubernetes.
+type DownwardAPI struct {
+	// Tag is used to query struct tag to get the metadata field of values.
+	Tag string
+	// Dir is the mount path of the downward API volume.
+	Dir string
+	// Files maps metadata fields to file names in Dir. Fields which are not
+	// listed use the last element of the field as file name, e.g. "labels" for
+	// "metadata.labels". Files of labels and annotations contain one key="value"
+	// line for each item.
+	Files map[string]string
+	// FS is used to read the files instead of the operating system if it is not
+	// nil. Dir is relative to the root of FS then.
+	FS fs.FS
+	// Env maps metadata fields to environment variables set using fieldRef.
+	// Environment variables are used before files.
+	Env map[string]string
+	// LookupEnv returns the value of an environment variable.
+	LookupEnv func(key string) (string, bool)
+}
+
+// NewDownwardAPI returns a downward API source which reads "fromK8s" struct tag,
+// reads files from /etc/podinfo and maps metadata.name, metadata.namespace,
+// spec.nodeName and status.podIP fields to POD_NAME, POD_NAMESPACE, NODE_NAME
+// and POD_IP environment variables. The returned instance can be customized by
+// changing fields.
+func NewDownwardAPI() *DownwardAPI {
+	return &DownwardAPI{
+		Tag: "fromK8s",
+		Dir: "/etc/podinfo",
+		Env: map[string]string{
+			"metadata.name":      "POD_NAME",
+			"metadata.namespace": "POD_NAMESPACE",
+			"spec.nodeName":      "NODE_NAME",
+			"status.podIP":       "POD_IP",
+		},
+		LookupEnv: os.LookupEnv,
+	}
+}
+
+// Apply sets the values with the tag which were not set by any other source.
+// An error is returned for invalid field selectors, unreadable files and values
+// which can not be set.
+func (thiz *DownwardAPI) Apply(values map[string]Value) error {
+	return thiz.apply(values, isDefault)
+}
+
+// apply sets the values for which canSet returns true.
+func (thiz *DownwardAPI) apply(values map[string]Value, canSet func(Value) bool) error {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value := values[name]
+		field, ok := value.Field().Tag.Lookup(thiz.Tag)
+		if !ok || !canSet(value) {
+			continue
+		}
+		s, found, err := thiz.lookup(field)
+		if err != nil {
+			return fmt.Errorf("can not read %s for flag %s: %v", field, name, err)
+		}
+		if !found {
+			continue
+		}
+		if err := replaceFrom(value, s, SourceDownwardAPI); err != nil {
+			return fmt.Errorf("invalid value %q for flag %s from %s: %s", s, name, field, errorDetail(err))
+		}
+	}
+	return nil
+}
+
+// lookup returns the value of the metadata field and whether it is available.
+func (thiz *DownwardAPI) lookup(field string) (string, bool, error) {
+	match := downwardFieldPattern.FindStringSubmatch(field)
+	if match == nil {
+		return "", false, fmt.Errorf("invalid field selector %q", field)
+	}
+	fieldPath, key := match[1], match[2]
+	if s, ok := thiz.env(field); ok {
+		return s, true, nil
+	}
+	if s, ok := thiz.env(fieldPath); ok && key != "" {
+		return downwardItem([]byte(s), key)
+	}
+	file, ok := thiz.Files[fieldPath]
+	if !ok {
+		file = fieldPath[strings.LastIndex(fieldPath, ".")+1:]
+	}
+	var data []byte
+	var err error
+	if thiz.FS != nil {
+		data, err = fs.ReadFile(thiz.FS, path.Join(thiz.Dir, file))
+	} else {
+		data, err = ioutil.ReadFile(filepath.Join(thiz.Dir, file))
+	}
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", false, nil
+	} else if err != nil {
+		return "", false, err
+	}
+	if key != "" {
+		return downwardItem(data, key)
+	}
+	return strings.TrimSpace(string(data)), true, nil
+}
+
+// env returns the value of the environment variable mapped to the field.
+func (thiz *DownwardAPI) env(field string) (string, bool) {
+	name, ok := thiz.Env[field]
+	if !ok || thiz.LookupEnv == nil {
+		return "", false
+	}
+	return thiz.LookupEnv(name)
+}
+
+// downwardItem returns the item with given key from the content of a labels or
+// annotations file.
+func downwardItem(data []byte, key string) (string, bool, error) {
+	items, err := parseDownwardItems(data)
+	if err != nil {
+		return "", false, err
+	}
+	s, ok := items[key]
+	return s, ok, nil
+}
+
+// parseDownwardItems parses the content of labels and annotations files, which
+// contain one key="value" line for each item with Go quoted values.
+func parseDownwardItems(data []byte) (map[string]string, error) {
+	items := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid line %q", line)
+		}
+		s, err := strconv.Unquote(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid value in line %q", line)
+		}
+		items[parts[0]] = s
+	}
+	return items, scanner.Err()
+}
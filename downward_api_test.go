@@ -0,0 +1,96 @@
+package structflag_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/surajbarkale/structflag"
+)
+
+type podOptions struct {
+	App       string `fromK8s:"metadata.labels['app']"`
+	Team      string `fromK8s:"metadata.labels['team']"`
+	Replicas  int    `fromK8s:"metadata.annotations['replicas']"`
+	Namespace string `fromK8s:"metadata.namespace"`
+	Pod       string `fromK8s:"metadata.name"`
+	Node      string `fromK8s:"spec.nodeName"`
+}
+
+func TestDownwardAPI(t *testing.T) {
+	dir, err := ioutil.TempDir("", "structflag")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	writeTempFile(t, dir, "labels", "app=\"web\"\nteam=\"a \\\"b\\\"\"\n")
+	writeTempFile(t, dir, "annotations", "replicas=\"3\"\n")
+	writeTempFile(t, dir, "namespace", "prod\n")
+
+	val := &podOptions{Pod: "local"}
+	values, err := structflag.DefaultStructToFlagsConverter.Convert(val)
+	require.NoError(t, err)
+	require.NoError(t, values["App"].Set("api"))
+	source := structflag.NewDownwardAPI()
+	source.Dir = dir
+	source.LookupEnv = func(key string) (string, bool) {
+		if key == "NODE_NAME" {
+			return "node-1", true
+		}
+		return "", false
+	}
+	require.NoError(t, source.Apply(values))
+	assert.Equal(t, podOptions{App: "api", Team: `a "b"`, Replicas: 3, Namespace: "prod", Pod: "local", Node: "node-1"}, *val)
+	assert.Equal(t, structflag.SourceDownwardAPI, values["Namespace"].Source())
+	assert.Equal(t, structflag.SourceDefault, values["Pod"].Source())
+}
+
+func TestDownwardAPIErrors(t *testing.T) {
+	dir, err := ioutil.TempDir("", "structflag")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	writeTempFile(t, dir, "annotations", "replicas=\"many\"\n")
+	source := structflag.NewDownwardAPI()
+	source.Dir = dir
+
+	values, err := structflag.DefaultStructToFlagsConverter.Convert(&podOptions{})
+	require.NoError(t, err)
+	assert.EqualError(t, source.Apply(values), `invalid value "many" for flag Replicas from metadata.annotations['replicas']: strconv.ParseInt: parsing "many": invalid syntax, expected integer`)
+
+	values, err = structflag.DefaultStructToFlagsConverter.Convert(&struct {
+		Bad string `fromK8s:"metadata.labels[app]"`
+	}{})
+	require.NoError(t, err)
+	assert.EqualError(t, source.Apply(values), `can not read metadata.labels[app] for flag Bad: invalid field selector "metadata.labels[app]"`)
+}
+
+func TestParseDownwardAPI(t *testing.T) {
+	val := &podOptions{}
+	parser := newTestParser(&bytes.Buffer{})
+	parser.DownwardAPI = structflag.NewDownwardAPI()
+	parser.DownwardAPI.Dir = "/nonexistent"
+	parser.DownwardAPI.LookupEnv = func(key string) (string, bool) {
+		return map[string]string{"POD_NAME": "web-0"}[key], key == "POD_NAME"
+	}
+	_, err := parser.Parse(val, []string{"--Node", "n"})
+	require.NoError(t, err)
+	assert.Equal(t, podOptions{Pod: "web-0", Node: "n"}, *val)
+}
+
+func TestDownwardAPIFS(t *testing.T) {
+	val := &podOptions{}
+	values, err := structflag.DefaultStructToFlagsConverter.Convert(val)
+	require.NoError(t, err)
+	source := structflag.NewDownwardAPI()
+	source.Dir = "podinfo"
+	source.FS = fstest.MapFS{
+		"podinfo/labels":    {Data: []byte("app=\"web\"\n")},
+		"podinfo/namespace": {Data: []byte("prod\n")},
+	}
+	source.LookupEnv = fakeEnv(nil)
+	require.NoError(t, source.Apply(values))
+	assert.Equal(t, podOptions{App: "web", Namespace: "prod"}, *val)
+}
@@ -0,0 +1,167 @@
+package structflag
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// SourceEnv is the source of values set from environment variables.
+const SourceEnv Source = "env"
+
+// EnvLoader sets values from environment variables. Variable names are derived
+// from flag names by EnvName, e.g. MYAPP_NESTED_INTPTR for Nested-IntPtr with
+// "MYAPP_" prefix. A field can bind a specific variable using the tag, e.g.
+// `env:"DATABASE_URL"`, or opt out of environment variables with `env:"-"`,
+// e.g. for secrets which must only be read from files. Previous names of a
+// renamed variable can follow the current name, e.g. `env:"NEW_NAME,OLD_NAME"`,
+// and are read if the current name is not set.
+type EnvLoader struct {
+	// Tag is used to query struct tag to get the environment variable of
+	// values.
+	Tag string
+	// Prefix is prepended to the derived names of environment variables. It is
+	// not added to the names given in the tag.
+	Prefix string
+	// Auto binds the values without the tag to the variables with derived
+	// names. Only the values with the tag are bound otherwise.
+	Auto bool
+	// Canonicalize normalizes the names of environment variables which are not
+	// set, e.g. CanonicalName to read MyApp_Nested_IntPtr for
+	// MYAPP_NESTED_INTPTR. Names matching several variables are ignored.
+	Canonicalize func(name string) string
+	// LookupEnv returns the value of an environment variable.
+	LookupEnv func(key string) (string, bool)
+	// OnDeprecated is called when a value is read from a previous name of a
+	// variable if it is not nil.
+	OnDeprecated func(old, name string)
+	// Environ returns all environment variables in the form "key=value". It is
+	// only used with Canonicalize.
+	Environ func() []string
+}
+
+// NewEnvLoader returns a loader which binds all values to environment variables
+// with derived names starting with prefix and reads "env" struct tag. The
+// returned instance can be customized by changing fields.
+func NewEnvLoader(prefix string) *EnvLoader {
+	return &EnvLoader{
+		Tag:       "env",
+		Prefix:    prefix,
+		Auto:      true,
+		LookupEnv: os.LookupEnv,
+		Environ:   os.Environ,
+	}
+}
+
+// EnvName returns the environment variable name derived from the flag name. The
+// name is converted to upper case and other characters than letters and digits
+// are replaced by underscores, e.g. "NESTED_INTPTR" for "Nested-IntPtr".
+func EnvName(prefix, name string) string {
+	return prefix + strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToUpper(r)
+		}
+		return '_'
+	}, name)
+}
+
+// Apply sets the values bound to environment variables which were not set by
+// any other source. An error is returned for values which can not be set.
+func (thiz *EnvLoader) Apply(values map[string]Value) error {
+	return thiz.apply(values, isDefault)
+}
+
+// apply sets the values for which canSet returns true.
+func (thiz *EnvLoader) apply(values map[string]Value, canSet func(Value) bool) error {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var environ []string
+	for _, name := range names {
+		value := values[name]
+		envs := thiz.Names(name, value)
+		if len(envs) == 0 || !canSet(value) {
+			continue
+		}
+		var env, s string
+		var found bool
+		for i, candidate := range envs {
+			if env, s, found = thiz.lookup(candidate, &environ); found {
+				if i > 0 && thiz.OnDeprecated != nil {
+					thiz.OnDeprecated(env, envs[0])
+				}
+				break
+			}
+		}
+		if !found {
+			continue
+		}
+		if err := replaceFrom(value, s, SourceEnv); err != nil {
+			return fmt.Errorf("invalid value %q for flag %s from %s: %s", s, name, env, errorDetail(err))
+		}
+	}
+	return nil
+}
+
+// lookup returns the name of the environment variable matching env and its
+// value. Names of all variables are read into
+// environ when they are needed to match canonical names.
+func (thiz *EnvLoader) lookup(env string, environ *[]string) (string, string, bool) {
+	if s, ok := thiz.LookupEnv(env); ok || thiz.Canonicalize == nil {
+		return env, s, ok
+	}
+	if *environ == nil {
+		*environ = envNames(thiz.Environ())
+	}
+	if match, ok := canonicalMatch(env, *environ, thiz.Canonicalize); ok {
+		s, ok := thiz.LookupEnv(match)
+		return match, s, ok
+	}
+	return env, "", false
+}
+
+// Name returns the environment variable bound to the value with given flag
+// name. Empty string is returned for values which are not bound.
+func (thiz *EnvLoader) Name(name string, value Value) string {
+	if names := thiz.Names(name, value); len(names) > 0 {
+		return names[0]
+	}
+	return ""
+}
+
+// Names returns the environment variables bound to the value with given flag
+// name, starting with the current name and followed by previous names which
+// are still read.
+func (thiz *EnvLoader) Names(name string, value Value) []string {
+	env, ok := value.Field().Tag.Lookup(thiz.Tag)
+	switch {
+	case env == "-":
+		return nil
+	case ok && env != "":
+		var names []string
+		for _, s := range strings.Split(env, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				names = append(names, s)
+			}
+		}
+		return names
+	case thiz.Auto:
+		return []string{EnvName(thiz.Prefix, name)}
+	}
+	return nil
+}
+
+// envNames returns the names of the variables in environ.
+func envNames(environ []string) []string {
+	names := make([]string, 0, len(environ))
+	for _, kv := range environ {
+		if i := strings.Index(kv, "="); i > 0 {
+			names = append(names, kv[:i])
+		}
+	}
+	return names
+}
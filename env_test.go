@@ -0,0 +1,122 @@
+package structflag_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/surajbarkale/structflag"
+)
+
+type envOptions struct {
+	Host     string
+	Database string `env:"DATABASE_URL"`
+	Token    string `env:"API_TOKEN, TOKEN,LEGACY_TOKEN"`
+	Password string `env:"-"`
+	Nested   struct {
+		IntPtr *int
+	}
+}
+
+func fakeEnv(env map[string]string) func(key string) (string, bool) {
+	return func(key string) (string, bool) {
+		s, ok := env[key]
+		return s, ok
+	}
+}
+
+func TestEnvName(t *testing.T) {
+	assert.Equal(t, "NESTED_INTPTR", structflag.EnvName("", "Nested-IntPtr"))
+	assert.Equal(t, "APP_MAX_CONNS", structflag.EnvName("APP_", "max.conns"))
+}
+
+func TestEnvLoader(t *testing.T) {
+	val := &envOptions{Host: "localhost"}
+	values, err := structflag.DefaultStructToFlagsConverter.Convert(val)
+	require.NoError(t, err)
+	loader := structflag.NewEnvLoader("APP_")
+	loader.LookupEnv = fakeEnv(map[string]string{
+		"APP_HOST":          "example.com",
+		"APP_DATABASE":      "ignored",
+		"DATABASE_URL":      "postgres://db",
+		"APP_PASSWORD":      "secret",
+		"APP_NESTED_INTPTR": "3",
+	})
+	require.NoError(t, loader.Apply(values))
+	assert.Equal(t, "example.com", val.Host)
+	assert.Equal(t, "postgres://db", val.Database)
+	assert.Empty(t, val.Password)
+	require.NotNil(t, val.Nested.IntPtr)
+	assert.Equal(t, 3, *val.Nested.IntPtr)
+	assert.Equal(t, structflag.SourceEnv, values["Host"].Source())
+	assert.Equal(t, "", loader.Name("Password", values["Password"]))
+
+	loader.LookupEnv = fakeEnv(map[string]string{"APP_NESTED_INTPTR": "x"})
+	values, err = structflag.DefaultStructToFlagsConverter.Convert(&envOptions{})
+	require.NoError(t, err)
+	assert.EqualError(t, loader.Apply(values), `invalid value "x" for flag Nested-IntPtr from APP_NESTED_INTPTR: strconv.ParseInt: parsing "x": invalid syntax, expected integer`)
+
+	loader.Auto = false
+	assert.Equal(t, "", loader.Name("Host", values["Host"]))
+	assert.Equal(t, "DATABASE_URL", loader.Name("Database", values["Database"]))
+}
+
+func TestEnvLoaderCanonicalize(t *testing.T) {
+	val := &envOptions{}
+	values, err := structflag.DefaultStructToFlagsConverter.Convert(val)
+	require.NoError(t, err)
+	env := map[string]string{"app_host": "example.com", "App_Nested_Int_Ptr": "3"}
+	loader := structflag.NewEnvLoader("APP_")
+	loader.Canonicalize = structflag.CanonicalName
+	loader.LookupEnv = fakeEnv(env)
+	loader.Environ = func() []string {
+		return []string{"app_host=example.com", "App_Nested_Int_Ptr=3"}
+	}
+	require.NoError(t, loader.Apply(values))
+	assert.Equal(t, "example.com", val.Host)
+	require.NotNil(t, val.Nested.IntPtr)
+	assert.Equal(t, 3, *val.Nested.IntPtr)
+}
+
+func TestEnvLoaderFallbacks(t *testing.T) {
+	val := &envOptions{}
+	values, err := structflag.DefaultStructToFlagsConverter.Convert(val)
+	require.NoError(t, err)
+	loader := structflag.NewEnvLoader("APP_")
+	assert.Equal(t, []string{"API_TOKEN", "TOKEN", "LEGACY_TOKEN"}, loader.Names("Token", values["Token"]))
+	assert.Equal(t, "API_TOKEN", loader.Name("Token", values["Token"]))
+	var deprecated []string
+	loader.OnDeprecated = func(old, name string) {
+		deprecated = append(deprecated, old+"->"+name)
+	}
+	loader.LookupEnv = fakeEnv(map[string]string{"LEGACY_TOKEN": "old"})
+	require.NoError(t, loader.Apply(values))
+	assert.Equal(t, "old", val.Token)
+	assert.Equal(t, []string{"LEGACY_TOKEN->API_TOKEN"}, deprecated)
+
+	values, err = structflag.DefaultStructToFlagsConverter.Convert(val)
+	require.NoError(t, err)
+	deprecated = nil
+	loader.LookupEnv = fakeEnv(map[string]string{"API_TOKEN": "new", "TOKEN": "old"})
+	require.NoError(t, loader.Apply(values))
+	assert.Equal(t, "new", val.Token)
+	assert.Empty(t, deprecated)
+}
+
+func TestParseEnv(t *testing.T) {
+	val := &envOptions{}
+	p := newTestParser(&bytes.Buffer{})
+	p.Env = structflag.NewEnvLoader("APP_")
+	p.Env.LookupEnv = fakeEnv(map[string]string{"APP_HOST": "env", "DATABASE_URL": "postgres://db", "TOKEN": "old"})
+	var out bytes.Buffer
+	p.Output = &out
+	_, err := p.Parse(val, []string{"-Host", "flag"})
+	require.NoError(t, err)
+	assert.Equal(t, "flag", val.Host)
+	assert.Equal(t, "postgres://db", val.Database)
+	assert.Equal(t, "old", val.Token)
+	assert.Equal(t, "warning: environment variable TOKEN is deprecated, use API_TOKEN\n", out.String())
+	assert.Nil(t, p.Env.OnDeprecated)
+}
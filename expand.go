@@ -0,0 +1,161 @@
+package structflag
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+// Expand replaces the string values containing text/template actions with the
+// result of executing them. Other values are referenced by flag path from the
+// root, with separator splitting the path into fields, e.g. "{{.DataDir}}/cache"
+// or "{{.Server.Host}}:{{.Server.Port}}" for Server-Host and Server-Port flags.
+// Referenced values are expanded first and an error is returned for cycles.
+// Expanded values are set again, so they are validated and keep their source.
+func Expand(values map[string]Value, separator string) error {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	e := &expander{values: values, separator: separator, state: map[string]int{}}
+	for _, name := range names {
+		if err := e.expand(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// States of values in expander.
+const (
+	stateExpanding = iota + 1
+	stateExpanded
+)
+
+// expander expands values in dependency order.
+type expander struct {
+	values    map[string]Value
+	separator string
+	state     map[string]int
+	stack     []string
+}
+
+// expand expands the value with given name after the values it references.
+func (thiz *expander) expand(name string) error {
+	switch thiz.state[name] {
+	case stateExpanded:
+		return nil
+	case stateExpanding:
+		cycle := append(thiz.stack[indexOf(thiz.stack, name):], name)
+		return fmt.Errorf("expansion cycle: %s", strings.Join(cycle, " -> "))
+	}
+	value := thiz.values[name]
+	s := value.String()
+	if value.Kind() != reflect.String || !strings.Contains(s, "{{") {
+		thiz.state[name] = stateExpanded
+		return nil
+	}
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(s)
+	if err != nil {
+		return fmt.Errorf("can not expand flag %s: %v", name, err)
+	}
+	thiz.state[name] = stateExpanding
+	thiz.stack = append(thiz.stack, name)
+	for _, ref := range thiz.references(tmpl.Root) {
+		if err := thiz.expand(ref); err != nil {
+			return err
+		}
+	}
+	thiz.stack = thiz.stack[:len(thiz.stack)-1]
+	var b strings.Builder
+	if err := tmpl.Execute(&b, thiz.data()); err != nil {
+		return fmt.Errorf("can not expand flag %s: %v", name, err)
+	}
+	if err := replaceFrom(value, b.String(), value.Source()); err != nil {
+		return fmt.Errorf("invalid expanded value %q for flag %s: %s", b.String(), name, errorDetail(err))
+	}
+	thiz.state[name] = stateExpanded
+	return nil
+}
+
+// references returns the names of the values referenced by fields in the
+// template.
+func (thiz *expander) references(node parse.Node) []string {
+	var refs []string
+	switch node := node.(type) {
+	case *parse.ListNode:
+		if node != nil {
+			for _, n := range node.Nodes {
+				refs = append(refs, thiz.references(n)...)
+			}
+		}
+	case *parse.ActionNode:
+		refs = thiz.references(node.Pipe)
+	case *parse.PipeNode:
+		if node != nil {
+			for _, cmd := range node.Cmds {
+				refs = append(refs, thiz.references(cmd)...)
+			}
+		}
+	case *parse.CommandNode:
+		for _, arg := range node.Args {
+			refs = append(refs, thiz.references(arg)...)
+		}
+	case *parse.IfNode:
+		refs = thiz.branchReferences(&node.BranchNode)
+	case *parse.RangeNode:
+		refs = thiz.branchReferences(&node.BranchNode)
+	case *parse.WithNode:
+		refs = thiz.branchReferences(&node.BranchNode)
+	case *parse.TemplateNode:
+		refs = thiz.references(node.Pipe)
+	case *parse.FieldNode:
+		for i := len(node.Ident); i > 0; i-- {
+			if name := strings.Join(node.Ident[:i], thiz.separator); thiz.values[name] != nil {
+				refs = append(refs, name)
+				break
+			}
+		}
+	}
+	return refs
+}
+
+// branchReferences returns the names of the values referenced in a branch.
+func (thiz *expander) branchReferences(node *parse.BranchNode) []string {
+	refs := thiz.references(node.Pipe)
+	refs = append(refs, thiz.references(node.List)...)
+	return append(refs, thiz.references(node.ElseList)...)
+}
+
+// data returns the current values as nested maps split by separator.
+func (thiz *expander) data() map[string]interface{} {
+	data := map[string]interface{}{}
+	for name, value := range thiz.values {
+		m := data
+		path := strings.Split(name, thiz.separator)
+		for _, key := range path[:len(path)-1] {
+			nested, ok := m[key].(map[string]interface{})
+			if !ok {
+				nested = map[string]interface{}{}
+				m[key] = nested
+			}
+			m = nested
+		}
+		m[path[len(path)-1]] = value.String()
+	}
+	return data
+}
+
+// indexOf returns the index of s in list or -1.
+func indexOf(list []string, s string) int {
+	for i, item := range list {
+		if item == s {
+			return i
+		}
+	}
+	return -1
+}
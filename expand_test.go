@@ -0,0 +1,80 @@
+package structflag_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/surajbarkale/structflag"
+)
+
+type expandOptions struct {
+	DataDir  string
+	CacheDir string
+	LogFile  string
+	Server   struct {
+		Host string
+		Port int
+	}
+	URL     string
+	Retries int
+}
+
+func TestExpand(t *testing.T) {
+	val := &expandOptions{
+		DataDir:  "/var/{{.Server.Host}}",
+		CacheDir: "{{.DataDir}}/cache",
+		LogFile:  "{{.CacheDir}}/../log",
+		URL:      "http://{{.Server.Host}}:{{.Server.Port}}/",
+	}
+	val.Server.Host = "example.com"
+	val.Server.Port = 80
+	values, err := structflag.DefaultStructToFlagsConverter.Convert(val)
+	require.NoError(t, err)
+	require.NoError(t, values["CacheDir"].Set("{{.DataDir}}/tmp"))
+	require.NoError(t, structflag.Expand(values, "-"))
+	assert.Equal(t, "/var/example.com", val.DataDir)
+	assert.Equal(t, "/var/example.com/tmp", val.CacheDir)
+	assert.Equal(t, "/var/example.com/tmp/../log", val.LogFile)
+	assert.Equal(t, "http://example.com:80/", val.URL)
+	assert.Equal(t, structflag.SourceFlag, values["CacheDir"].Source())
+	assert.Equal(t, structflag.SourceDefault, values["URL"].Source())
+}
+
+func TestExpandErrors(t *testing.T) {
+	convert := func(val *expandOptions) map[string]structflag.Value {
+		values, err := structflag.DefaultStructToFlagsConverter.Convert(val)
+		require.NoError(t, err)
+		return values
+	}
+	values := convert(&expandOptions{DataDir: "{{.LogFile}}", CacheDir: "{{.DataDir}}", LogFile: "{{.CacheDir}}/log"})
+	assert.EqualError(t, structflag.Expand(values, "-"), "expansion cycle: CacheDir -> DataDir -> LogFile -> CacheDir")
+	values = convert(&expandOptions{DataDir: "{{.Missing}}"})
+	assert.Error(t, structflag.Expand(values, "-"))
+	values = convert(&expandOptions{DataDir: "{{.DataDir"})
+	assert.Error(t, structflag.Expand(values, "-"))
+}
+
+func TestParseExpand(t *testing.T) {
+	val := &expandOptions{CacheDir: "{{.DataDir}}/cache"}
+	p := newTestParser(&bytes.Buffer{})
+	p.Expand = true
+	_, err := p.Parse(val, []string{"-DataDir", "/data"})
+	require.NoError(t, err)
+	assert.Equal(t, "/data/cache", val.CacheDir)
+}
+
+func TestParseExpandRepeatPolicy(t *testing.T) {
+	for _, policy := range []structflag.RepeatPolicy{structflag.FirstSetWins, structflag.RejectRepeatedSet} {
+		val := &expandOptions{}
+		p := newTestParser(&bytes.Buffer{})
+		p.Converter = structflag.NewStructToFlagsConverter()
+		p.Converter.RepeatPolicy = policy
+		p.Expand = true
+		_, err := p.Parse(val, []string{"--DataDir=/x", "--CacheDir={{.DataDir}}/cache"})
+		require.NoError(t, err)
+		assert.Equal(t, "/x/cache", val.CacheDir)
+	}
+}
@@ -0,0 +1,65 @@
+package structflag
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// Fingerprinter computes stable hashes of configuration structures, e.g. to
+// detect drift between replicas or to gate rollouts on a known configuration.
+type Fingerprinter struct {
+	// Converter generates the values which are hashed.
+	Converter *StructToFlagsConverter
+	// ExcludeSecrets leaves secret values out of the hash, so it does not
+	// change when only credentials are rotated.
+	ExcludeSecrets bool
+}
+
+// DefaultFingerprinter is the fingerprinter used by Fingerprint.
+var DefaultFingerprinter = NewFingerprinter()
+
+// NewFingerprinter returns a fingerprinter which uses
+// DefaultStructToFlagsConverter and excludes secrets. The returned instance can
+// be customized by changing fields.
+func NewFingerprinter() *Fingerprinter {
+	return &Fingerprinter{
+		Converter:      DefaultStructToFlagsConverter,
+		ExcludeSecrets: true,
+	}
+}
+
+// Fingerprint returns the hash of target using DefaultFingerprinter. You must
+// pass a pointer to the value.
+func Fingerprint(target interface{}) (string, error) {
+	return DefaultFingerprinter.Fingerprint(target)
+}
+
+// Fingerprint returns the hex encoded SHA-256 hash of the flag names and the
+// encoded values of target. The hash only depends on the values, not on the
+// order of fields or the sources of values. The target is not modified. You
+// must pass a pointer to the value.
+func (thiz *Fingerprinter) Fingerprint(target interface{}) (string, error) {
+	if v := reflect.ValueOf(target); v.Kind() != reflect.Ptr || v.IsNil() {
+		return "", fmt.Errorf("fingerprint target must be a non-nil pointer, got %T", target)
+	}
+	values, err := thiz.Converter.Convert(Snapshot(target))
+	if err != nil {
+		return "", err
+	}
+	names := make([]string, 0, len(values))
+	for name, value := range values {
+		if !thiz.ExcludeSecrets || !value.IsSecret() {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	hash := sha256.New()
+	for _, name := range names {
+		hash.Write([]byte(strconv.Quote(name) + "=" + strconv.Quote(values[name].String()) + "\n"))
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
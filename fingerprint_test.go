@@ -0,0 +1,48 @@
+package structflag_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/surajbarkale/structflag"
+)
+
+type fingerprintOptions struct {
+	Host     string
+	Labels   map[string]string
+	Password string `secret:"true"`
+	TLS      *struct {
+		Cert string
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	fingerprint := func(val *fingerprintOptions) string {
+		s, err := structflag.Fingerprint(val)
+		require.NoError(t, err)
+		return s
+	}
+	val := &fingerprintOptions{Host: "a", Labels: map[string]string{"x": "1", "y": "2", "z": "3"}, Password: "p1"}
+	base := fingerprint(val)
+	assert.Len(t, base, 64)
+	assert.Nil(t, val.TLS)
+	for i := 0; i < 5; i++ {
+		assert.Equal(t, base, fingerprint(&fingerprintOptions{Host: "a", Labels: map[string]string{"z": "3", "y": "2", "x": "1"}, Password: "p2"}))
+	}
+	assert.NotEqual(t, base, fingerprint(&fingerprintOptions{Host: "b", Labels: val.Labels}))
+
+	f := structflag.NewFingerprinter()
+	f.ExcludeSecrets = false
+	withSecret, err := f.Fingerprint(val)
+	require.NoError(t, err)
+	assert.NotEqual(t, base, withSecret)
+	val.Password = "p2"
+	changed, err := f.Fingerprint(val)
+	require.NoError(t, err)
+	assert.NotEqual(t, withSecret, changed)
+
+	_, err = structflag.Fingerprint(fingerprintOptions{})
+	assert.Error(t, err)
+}
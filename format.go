@@ -0,0 +1,70 @@
+package structflag
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+var formats = struct {
+	sync.RWMutex
+	codecs map[string]Codec
+}{codecs: map[string]Codec{}}
+
+// RegisterFormat adds a codec used for fields selecting it by name in the
+// format struct tag, e.g. `format:"dims"`. Unlike codecs registered with
+// RegisterCodec, formats are only used when requested, so the same type can be
+// converted differently in different fields. Structs with a format are leaf
+// values. A format registered later replaces the earlier one with the same name.
+func RegisterFormat(name string, c Codec) {
+	formats.Lock()
+	defer formats.Unlock()
+	formats.codecs[name] = c
+}
+
+// formatCodec returns the codec for the format which can be used with type t.
+// Pointers to matching types are handled by the returned codec.
+func (thiz *StructToFlagsConverter) formatCodec(name string, t reflect.Type) (Codec, error) {
+	formats.RLock()
+	c := formats.codecs[name]
+	formats.RUnlock()
+	if c == nil {
+		return nil, fmt.Errorf("unknown format %q", name)
+	}
+	if w, ok := c.(withConverter); ok {
+		c = w.withConverter(thiz)
+	}
+	if c.Match(t) {
+		return c, nil
+	}
+	if t.Kind() == reflect.Ptr && c.Match(t.Elem()) {
+		return pointerCodec{c}, nil
+	}
+	return nil, fmt.Errorf("format %q can not be used with %s", name, t)
+}
+
+// pointerCodec applies a codec to the values pointed by pointers. Nil pointers
+// are allocated when decoding and encoded as empty strings.
+type pointerCodec struct {
+	Codec
+}
+
+func (thiz pointerCodec) Decode(s string, val reflect.Value) error {
+	res := reflect.New(val.Type().Elem())
+	if err := thiz.Codec.Decode(s, res.Elem()); err != nil {
+		return err
+	}
+	if val.IsNil() {
+		val.Set(res)
+	} else {
+		val.Elem().Set(res.Elem())
+	}
+	return nil
+}
+
+func (thiz pointerCodec) Encode(val reflect.Value) (string, error) {
+	if val.IsNil() {
+		return "", nil
+	}
+	return thiz.Codec.Encode(val.Elem())
+}
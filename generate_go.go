@@ -0,0 +1,79 @@
+package structflag
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// SourceGenerated is the source of values set by code written by GenerateGo.
+const SourceGenerated Source = "generated"
+
+// GenerateGo writes Go source of package pkg containing the values of target as
+// a map keyed by flag name and a function setting them, e.g. to embed release
+// defaults into the binary:
+//
+//	// ReleaseDefaults contains the values of the configuration.
+//	var ReleaseDefaults = map[string]string{
+//		"Port": "8080",
+//	}
+//
+//	// ApplyReleaseDefaults sets the values in ReleaseDefaults.
+//	func ApplyReleaseDefaults(values map[string]structflag.Value) error
+//
+// The map is called name and the function is name prefixed by "Apply". The
+// generated function must get values created by a converter generating the same
+// flag names. It sets the values sorted by name and records SourceGenerated as
+// their source. Values which are empty and secret values are left out. You must
+// pass a pointer to the value.
+func (thiz *StructToFlagsConverter) GenerateGo(w io.Writer, pkg, name string, target interface{}) error {
+	if v := reflect.ValueOf(target); v.Kind() != reflect.Ptr || v.IsNil() {
+		return fmt.Errorf("generate target must be a non-nil pointer, got %T", target)
+	}
+	values, err := thiz.Convert(Snapshot(target))
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(values))
+	for n, value := range values {
+		if rv, ok := value.(*reflectedValue); ok && isEmpty(rv.target) {
+			continue
+		}
+		if !value.IsSecret() {
+			names = append(names, n)
+		}
+	}
+	sort.Strings(names)
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by structflag. DO NOT EDIT.\n\npackage %s\n\n", pkg)
+	fmt.Fprintf(&b, "import (\n\t\"fmt\"\n\t\"sort\"\n\n\t\"github.com/surajbarkale/structflag\"\n)\n\n")
+	fmt.Fprintf(&b, "// %s contains the values of the configuration keyed by flag name.\n", name)
+	fmt.Fprintf(&b, "var %s = map[string]string{\n", name)
+	for _, n := range names {
+		fmt.Fprintf(&b, "%s: %s,\n", strconv.Quote(n), strconv.Quote(values[n].String()))
+	}
+	fmt.Fprintf(&b, "}\n\n")
+	fmt.Fprintf(&b, "// Apply%s sets the values in %s.\n", name, name)
+	fmt.Fprintf(&b, "func Apply%s(values map[string]structflag.Value) error {\n", name)
+	fmt.Fprintf(&b, "names := make([]string, 0, len(%s))\n", name)
+	fmt.Fprintf(&b, "for name := range %s {\nnames = append(names, name)\n}\nsort.Strings(names)\n", name)
+	fmt.Fprintf(&b, "for _, name := range names {\n")
+	fmt.Fprintf(&b, "value, ok := values[name]\nif !ok {\nreturn fmt.Errorf(\"unknown flag %%s\", name)\n}\n")
+	fmt.Fprintf(&b, "if err := structflag.SetFrom(value, %s[name], structflag.SourceGenerated); err != nil {\nreturn err\n}\n}\nreturn nil\n}\n", name)
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(src)
+	return err
+}
+
+// GenerateGo writes Go source setting the values of target using
+// DefaultStructToFlagsConverter.
+func GenerateGo(w io.Writer, pkg, name string, target interface{}) error {
+	return DefaultStructToFlagsConverter.GenerateGo(w, pkg, name, target)
+}
@@ -0,0 +1,70 @@
+package structflag_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/surajbarkale/structflag"
+)
+
+type releaseOptions struct {
+	Host     string
+	Port     int
+	Debug    bool
+	Timeout  time.Duration
+	Tags     []string
+	Password string `secret:"true"`
+	Server   *struct {
+		Name string
+	}
+}
+
+func TestGenerateGo(t *testing.T) {
+	val := &releaseOptions{Host: "example.com", Port: 8080, Timeout: time.Minute, Tags: []string{"a", "b"}, Password: "x"}
+	var out bytes.Buffer
+	require.NoError(t, structflag.GenerateGo(&out, "config", "ReleaseDefaults", val))
+	assert.Equal(t, `// Code generated by structflag. DO NOT EDIT.
+
+package config
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/surajbarkale/structflag"
+)
+
+// ReleaseDefaults contains the values of the configuration keyed by flag name.
+var ReleaseDefaults = map[string]string{
+	"Host":    "example.com",
+	"Port":    "8080",
+	"Tags":    "[\"a\",\"b\"]",
+	"Timeout": "1m0s",
+}
+
+// ApplyReleaseDefaults sets the values in ReleaseDefaults.
+func ApplyReleaseDefaults(values map[string]structflag.Value) error {
+	names := make([]string, 0, len(ReleaseDefaults))
+	for name := range ReleaseDefaults {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value, ok := values[name]
+		if !ok {
+			return fmt.Errorf("unknown flag %s", name)
+		}
+		if err := structflag.SetFrom(value, ReleaseDefaults[name], structflag.SourceGenerated); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+`, out.String())
+	assert.Nil(t, val.Server)
+	assert.Error(t, structflag.GenerateGo(&out, "config", "ReleaseDefaults", *val))
+}
@@ -0,0 +1,88 @@
+package structflag
+
+import (
+	"path"
+	"reflect"
+	"strings"
+)
+
+// Glob is a path pattern in the syntax of path.Match, extended with "**"
+// segments matching any number of path segments. Patterns are validated when
+// they are set, so malformed patterns are reported while parsing flags.
+type Glob string
+
+var globType = reflect.TypeOf(Glob(""))
+
+// globCodec validates Glob values.
+type globCodec struct{}
+
+func init() {
+	RegisterCodec(globCodec{})
+}
+
+// Match returns true for Glob.
+func (globCodec) Match(t reflect.Type) bool {
+	return t == globType
+}
+
+// Decode validates the pattern.
+func (globCodec) Decode(s string, val reflect.Value) error {
+	if err := Glob(s).Validate(); err != nil {
+		return err
+	}
+	val.SetString(s)
+	return nil
+}
+
+// Encode returns the pattern.
+func (globCodec) Encode(val reflect.Value) (string, error) {
+	return val.String(), nil
+}
+
+// UnmarshalText validates the pattern, e.g. when it is decoded from JSON as an
+// element of a slice.
+func (thiz *Glob) UnmarshalText(text []byte) error {
+	if err := Glob(text).Validate(); err != nil {
+		return err
+	}
+	*thiz = Glob(text)
+	return nil
+}
+
+// Validate returns path.ErrBadPattern if the pattern is malformed.
+func (thiz Glob) Validate() error {
+	for _, segment := range strings.Split(string(thiz), "/") {
+		if _, err := path.Match(segment, ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Match returns true if the slash separated name matches the pattern.
+// Malformed patterns do not match anything.
+func (thiz Glob) Match(name string) bool {
+	return matchSegments(strings.Split(string(thiz), "/"), strings.Split(name, "/"))
+}
+
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			// Try to match the rest of the pattern at every position
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, err := path.Match(pattern[0], name[0]); !ok || err != nil {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
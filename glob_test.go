@@ -0,0 +1,52 @@
+package structflag_test
+
+import (
+	"errors"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/surajbarkale/structflag"
+)
+
+func TestGlobValues(t *testing.T) {
+	val := &struct {
+		Include structflag.Glob
+		Exclude []structflag.Glob
+	}{Include: "*.go"}
+	values, err := structflag.DefaultStructToFlagsConverter.Convert(val)
+	require.NoError(t, err)
+	assert.Equal(t, "*.go", values["Include"].Default())
+	assert.Equal(t, "string", values["Include"].Kind().String())
+
+	require.NoError(t, values["Include"].Set("src/**/*.go"))
+	assert.Equal(t, structflag.Glob("src/**/*.go"), val.Include)
+	assert.True(t, errors.Is(values["Include"].Set("src/[a-"), path.ErrBadPattern))
+	assert.Equal(t, structflag.Glob("src/**/*.go"), val.Include)
+
+	require.NoError(t, values["Exclude"].Set(`["vendor/**", "*_test.go"]`))
+	assert.Equal(t, []structflag.Glob{"vendor/**", "*_test.go"}, val.Exclude)
+	assert.Error(t, values["Exclude"].Set(`["[a-"]`))
+}
+
+func TestGlobMatch(t *testing.T) {
+	for _, tc := range []struct {
+		pattern structflag.Glob
+		name    string
+		match   bool
+	}{
+		{"*.go", "main.go", true},
+		{"*.go", "cmd/main.go", false},
+		{"**/*.go", "main.go", true},
+		{"**/*.go", "cmd/app/main.go", true},
+		{"src/**", "src", true},
+		{"src/**", "src/a/b", true},
+		{"src/**/test/*.txt", "src/a/test/x.txt", true},
+		{"src/**/test/*.txt", "src/a/x.txt", false},
+		{"[a-", "a", false},
+	} {
+		assert.Equal(t, tc.match, tc.pattern.Match(tc.name), "%s %s", tc.pattern, tc.name)
+	}
+}
@@ -0,0 +1,45 @@
+package structflag
+
+import (
+	"sort"
+)
+
+// FilterGroups returns the values belonging to any of the groups. Empty string
+// selects values without a group.
+func FilterGroups(values map[string]Value, groups ...string) map[string]Value {
+	selected := make(map[string]bool, len(groups))
+	for _, group := range groups {
+		selected[group] = true
+	}
+	res := map[string]Value{}
+	for name, value := range values {
+		if selected[value.Group()] {
+			res[name] = value
+		}
+	}
+	return res
+}
+
+// flagGroup lists the sorted names of the values in a group.
+type flagGroup struct {
+	name  string
+	names []string
+}
+
+// groupNames splits the names of the values by group. Values without a group
+// come first, followed by the groups sorted by name.
+func groupNames(values map[string]Value) []flagGroup {
+	byGroup := map[string][]string{}
+	for name, value := range values {
+		byGroup[value.Group()] = append(byGroup[value.Group()], name)
+	}
+	res := make([]flagGroup, 0, len(byGroup))
+	for group, names := range byGroup {
+		sort.Strings(names)
+		res = append(res, flagGroup{group, names})
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].name < res[j].name
+	})
+	return res
+}
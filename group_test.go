@@ -0,0 +1,72 @@
+package structflag_test
+
+import (
+	"bytes"
+	"flag"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/surajbarkale/structflag"
+)
+
+type groupTest struct {
+	Port int `description:"Listen port"`
+	TLS  struct {
+		Cert string
+		Key  string `group:"secrets"`
+	} `group:"tls"`
+	Turbo bool `group:"experimental"`
+}
+
+func TestGroups(t *testing.T) {
+	val := &groupTest{}
+	values, err := structflag.DefaultStructToFlagsConverter.Convert(val)
+	require.NoError(t, err)
+	assert.Equal(t, "", values["Port"].Group())
+	assert.Equal(t, "tls", values["TLS-Cert"].Group())
+	assert.Equal(t, "secrets", values["TLS-Key"].Group())
+	assert.Equal(t, "experimental", values["Turbo"].Group())
+
+	var b bytes.Buffer
+	structflag.PrintDefaults(&b, values)
+	assert.Equal(t, `  -Port int
+    	Listen port
+
+experimental flags:
+  -Turbo
+
+secrets flags:
+  -TLS-Key string
+
+tls flags:
+  -TLS-Cert string
+`, b.String())
+
+	selected := structflag.FilterGroups(values, "", "tls")
+	assert.Len(t, selected, 2)
+	assert.Contains(t, selected, "Port")
+	assert.Contains(t, selected, "TLS-Cert")
+}
+
+func TestRegisterGroups(t *testing.T) {
+	values, err := structflag.DefaultStructToFlagsConverter.Convert(&groupTest{})
+	require.NoError(t, err)
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	require.NoError(t, structflag.Register(fs, values, "experimental"))
+	assert.NotNil(t, fs.Lookup("Turbo"))
+	assert.Nil(t, fs.Lookup("Port"))
+}
+
+func TestParseGroups(t *testing.T) {
+	val := &groupTest{}
+	parser := newTestParser(&bytes.Buffer{})
+	parser.Groups = []string{""}
+	_, err := parser.Parse(val, []string{"--Port", "80"})
+	require.NoError(t, err)
+	assert.Equal(t, 80, val.Port)
+	_, err = parser.Parse(val, []string{"--Turbo"})
+	assert.EqualError(t, err, "flag provided but not defined: --Turbo")
+	assert.NotContains(t, parser.Values(), "Turbo")
+}
@@ -0,0 +1,287 @@
+package structflag
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ColorMode defines when HelpPrinter uses colors.
+type ColorMode int
+
+const (
+	// ColorNever writes plain text.
+	ColorNever ColorMode = iota
+	// ColorAuto uses colors when writing to a terminal and NO_COLOR environment
+	// variable is not set.
+	ColorAuto
+	// ColorAlways uses colors regardless of the output.
+	ColorAlways
+)
+
+// ColorScheme contains ANSI escape sequences used to highlight parts of help.
+// Empty sequence leaves the part unchanged.
+type ColorScheme struct {
+	// Name is used for flag names and aliases.
+	Name string
+	// Default is used for default values.
+	Default string
+	// Deprecated is used for deprecation warnings.
+	Deprecated string
+}
+
+// HelpPrinter writes usage information formatted for reading in a terminal.
+// Flag names and descriptions are aligned in columns and descriptions are
+// wrapped to fit the output width. Use NewHelpPrinter to create a printer.
+// The output is the same in every run when Width is set and Color is not
+// ColorAuto, e.g. for comparing it with a golden file.
+type HelpPrinter struct {
+	// Width is the maximum line width. If it is zero, then the width of the
+	// terminal is used, falling back to COLUMNS environment variable and 80.
+	Width int
+	// MaxNameWidth limits the width of the name column. Descriptions of flags
+	// with longer names start on the next line.
+	MaxNameWidth int
+	// Color defines whether Colors are used.
+	Color ColorMode
+	// Colors is the color scheme used when colors are enabled.
+	Colors ColorScheme
+	// DeprecatedTag is used to query struct tag to get deprecation message for
+	// values, e.g. `deprecated:"use -Listen"`.
+	DeprecatedTag string
+	// ExampleTag is used to query struct tag to get an example value shown with
+	// the default, e.g. `example:"redis://localhost:6379"`.
+	ExampleTag string
+	// RequiredTag is used to query struct tag to mark required values, e.g.
+	// `required:"true"`. Required values are not marked if it is empty.
+	RequiredTag string
+	// MaxDefaultWidth is the length of JSON defaults which are shortened to
+	// "{...}" or "[...]" if they are longer. Zero shows them completely.
+	MaxDefaultWidth int
+	// Full pretty-prints JSON defaults on multiple lines instead of shortening
+	// them, e.g. for Parser.FullUsage.
+	Full bool
+	// Messages translates the text added to descriptions. DefaultMessages is
+	// used if it is nil.
+	Messages *Messages
+}
+
+// NewHelpPrinter returns a printer that detects output width, does not use
+// colors, reads deprecation messages from "deprecated" struct tag and examples
+// from "example" struct tag and has a color scheme highlighting names, defaults
+// and deprecation warnings. JSON defaults longer than DefaultMaxWidth are
+// shortened. The returned instance can be customized by changing fields. It can
+// be used with Parser like this:
+//
+//	parser.Usage = NewHelpPrinter().PrintDefaults
+func NewHelpPrinter() *HelpPrinter {
+	return &HelpPrinter{
+		MaxNameWidth:    30,
+		MaxDefaultWidth: DefaultMaxWidth,
+		DeprecatedTag:   "deprecated",
+		ExampleTag:      "example",
+		Colors: ColorScheme{
+			Name:       "\x1b[1m",
+			Default:    "\x1b[2m",
+			Deprecated: "\x1b[33m",
+		},
+	}
+}
+
+// styledWord is a word of help text with the escape sequence used to show it.
+type styledWord struct {
+	text  string
+	style string
+}
+
+// PrintDefaults writes usage information for the values to w. Values are sorted
+// by name and values in groups are shown in sections after the values without a
+// group. Defaults of secret values are not shown.
+func (thiz *HelpPrinter) PrintDefaults(w io.Writer, values map[string]Value) {
+	color := thiz.useColor(w)
+	width := thiz.width(w)
+	messages := messagesOrDefault(thiz.Messages)
+
+	nameWidth := 0
+	for name, value := range values {
+		if n := utf8.RuneCountInString(thiz.head(name, value, false)); n > nameWidth && n <= thiz.MaxNameWidth {
+			nameWidth = n
+		}
+	}
+	// Leave room for descriptions even if the output is narrow
+	indent := nameWidth + 2
+	textWidth := width - indent
+	if textWidth < 20 {
+		textWidth = 20
+	}
+	for _, group := range groupNames(values) {
+		if group.name != "" {
+			fmt.Fprintf(w, "\n"+messages.Group+"\n", group.name)
+		}
+		for _, name := range group.names {
+			value := values[name]
+			var b strings.Builder
+			b.WriteString(thiz.head(name, value, color))
+			lines := wrapWords(thiz.describe(name, value, messages), textWidth)
+			headWidth := utf8.RuneCountInString(thiz.head(name, value, false))
+			if len(lines) > 0 && headWidth > nameWidth {
+				b.WriteString("\n" + strings.Repeat(" ", indent))
+			} else if len(lines) > 0 {
+				b.WriteString(strings.Repeat(" ", indent-headWidth))
+			}
+			for j, line := range lines {
+				if j > 0 {
+					b.WriteString("\n" + strings.Repeat(" ", indent))
+				}
+				for k, word := range line {
+					if k > 0 {
+						b.WriteString(" ")
+					}
+					b.WriteString(paint(word.text, word.style, color))
+				}
+			}
+			fmt.Fprintln(w, b.String())
+		}
+	}
+}
+
+// head returns the flag names and type shown in the first column.
+func (thiz *HelpPrinter) head(name string, value Value, color bool) string {
+	var b strings.Builder
+	b.WriteString("  " + paint("-"+name, thiz.Colors.Name, color))
+	for _, alias := range value.Aliases() {
+		b.WriteString(", " + paint("-"+alias, thiz.Colors.Name, color))
+	}
+	if typ := valueTypeName(value); typ != "" {
+		b.WriteString(" " + typ)
+	}
+	return b.String()
+}
+
+// describe returns words of the description followed by the default value,
+// required marker and deprecation warning. Line breaks in the description are
+// kept as empty words.
+func (thiz *HelpPrinter) describe(name string, value Value, messages *Messages) []styledWord {
+	var words []styledWord
+	usage, expanded := expandDescription(name, value)
+	for i, line := range strings.Split(usage, "\n") {
+		if i > 0 {
+			words = append(words, styledWord{})
+		}
+		for _, word := range strings.Fields(line) {
+			words = append(words, styledWord{text: word})
+		}
+	}
+	if def := value.Default(); def != "" && !expanded && !value.IsSecret() {
+		def = fmt.Sprintf(messages.Default, formatDefault(def, thiz.MaxDefaultWidth, thiz.Full, messages))
+		for i, line := range strings.Split(def, "\n") {
+			if i == 0 {
+				for _, word := range strings.Fields(line) {
+					words = append(words, styledWord{text: word, style: thiz.Colors.Default})
+				}
+				continue
+			}
+			// Keep indentation of pretty-printed JSON
+			words = append(words, styledWord{}, styledWord{text: line, style: thiz.Colors.Default})
+		}
+	}
+	if thiz.ExampleTag != "" && !expanded {
+		if example := value.Field().Tag.Get(thiz.ExampleTag); example != "" {
+			for _, word := range strings.Fields(fmt.Sprintf(messages.Example, example)) {
+				words = append(words, styledWord{text: word, style: thiz.Colors.Default})
+			}
+		}
+	}
+	if thiz.RequiredTag != "" {
+		if required, _ := strconv.ParseBool(value.Field().Tag.Get(thiz.RequiredTag)); required {
+			for _, word := range strings.Fields(messages.Required) {
+				words = append(words, styledWord{text: word})
+			}
+		}
+	}
+	if thiz.DeprecatedTag != "" {
+		if message, ok := value.Field().Tag.Lookup(thiz.DeprecatedTag); ok {
+			warning := messages.Deprecated
+			if message != "" && message != "true" {
+				warning = fmt.Sprintf(messages.DeprecatedMessage, message)
+			}
+			for _, word := range strings.Fields(warning) {
+				words = append(words, styledWord{text: word, style: thiz.Colors.Deprecated})
+			}
+		}
+	}
+	return words
+}
+
+// wrapWords splits words into lines not longer than width. Words longer than
+// width are put on separate lines. Empty words force a line break.
+func wrapWords(words []styledWord, width int) [][]styledWord {
+	var lines [][]styledWord
+	var line []styledWord
+	lineWidth := 0
+	for _, word := range words {
+		if word.text == "" {
+			lines = append(lines, line)
+			line, lineWidth = nil, 0
+			continue
+		}
+		n := utf8.RuneCountInString(word.text)
+		if len(line) > 0 && lineWidth+1+n > width {
+			lines = append(lines, line)
+			line, lineWidth = nil, 0
+		}
+		if len(line) > 0 {
+			lineWidth++
+		}
+		line = append(line, word)
+		lineWidth += n
+	}
+	if len(line) > 0 {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// paint wraps text in the escape sequence if colors are enabled.
+func paint(text, style string, color bool) string {
+	if !color || style == "" {
+		return text
+	}
+	return style + text + "\x1b[0m"
+}
+
+func (thiz *HelpPrinter) useColor(w io.Writer) bool {
+	switch thiz.Color {
+	case ColorAlways:
+		return true
+	case ColorAuto:
+		_, noColor := os.LookupEnv("NO_COLOR")
+		f, ok := w.(*os.File)
+		return !noColor && ok && isTerminal(f)
+	}
+	return false
+}
+
+func (thiz *HelpPrinter) width(w io.Writer) int {
+	if thiz.Width > 0 {
+		return thiz.Width
+	}
+	if f, ok := w.(*os.File); ok {
+		if width := terminalWidth(f); width > 0 {
+			return width
+		}
+	}
+	if width, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && width > 0 {
+		return width
+	}
+	return 80
+}
+
+// isTerminal returns true if f is a character device.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
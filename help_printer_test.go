@@ -0,0 +1,96 @@
+package structflag_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/surajbarkale/structflag"
+)
+
+func TestHelpPrinterWrapsAndAligns(t *testing.T) {
+	val := &struct {
+		Debug                          bool   `description:"Enable debug mode" aliases:"d"`
+		Listen                         string `description:"Address to listen on for incoming connections from clients"`
+		Password                       string `secret:"true"`
+		OldName                        string `deprecated:"use -Listen"`
+		VeryLongFlagNameThatDoesNotFit int    `description:"Long name"`
+	}{Listen: ":80", Password: "abc", VeryLongFlagNameThatDoesNotFit: 1}
+	values, err := structflag.DefaultStructToFlagsConverter.Convert(val)
+	require.NoError(t, err)
+	printer := structflag.NewHelpPrinter()
+	printer.Width = 50
+	var b bytes.Buffer
+	printer.PrintDefaults(&b, values)
+	exp := `  -Debug, -d        Enable debug mode
+  -Listen string    Address to listen on for
+                    incoming connections from
+                    clients (default :80)
+  -OldName string   (deprecated: use -Listen)
+  -Password string
+  -VeryLongFlagNameThatDoesNotFit int
+                    Long name (default 1)
+`
+	assert.Equal(t, exp, b.String())
+}
+
+func TestHelpPrinterColors(t *testing.T) {
+	val := &struct {
+		Count int `description:"Count"`
+	}{Count: 2}
+	values, err := structflag.DefaultStructToFlagsConverter.Convert(val)
+	require.NoError(t, err)
+	printer := structflag.NewHelpPrinter()
+	printer.Width = 80
+	printer.Colors = structflag.ColorScheme{Name: "<n>", Default: "<d>"}
+	var b bytes.Buffer
+	printer.PrintDefaults(&b, values)
+	assert.Equal(t, "  -Count int  Count (default 2)\n", b.String())
+
+	// Colors are only used for terminals in auto mode
+	printer.Color = structflag.ColorAuto
+	b.Reset()
+	printer.PrintDefaults(&b, values)
+	assert.Equal(t, "  -Count int  Count (default 2)\n", b.String())
+
+	printer.Color = structflag.ColorAlways
+	b.Reset()
+	printer.PrintDefaults(&b, values)
+	assert.Equal(t, "  <n>-Count\x1b[0m int  Count <d>(default\x1b[0m <d>2)\x1b[0m\n", b.String())
+}
+
+func TestHelpPrinterKeepsLineBreaks(t *testing.T) {
+	val := &struct {
+		Names []string `description:"Names to use\nas input"`
+	}{}
+	values, err := structflag.DefaultStructToFlagsConverter.Convert(val)
+	require.NoError(t, err)
+	printer := structflag.NewHelpPrinter()
+	printer.Width = 80
+	var b bytes.Buffer
+	printer.PrintDefaults(&b, values)
+	assert.Equal(t, "  -Names value  Names to use\n                as input\n", b.String())
+}
+
+func TestHelpPrinterGroups(t *testing.T) {
+	values, err := structflag.DefaultStructToFlagsConverter.Convert(&groupTest{})
+	require.NoError(t, err)
+	printer := structflag.NewHelpPrinter()
+	printer.Width = 80
+	var b bytes.Buffer
+	printer.PrintDefaults(&b, values)
+	exp := `  -Port int         Listen port
+
+experimental flags:
+  -Turbo
+
+secrets flags:
+  -TLS-Key string
+
+tls flags:
+  -TLS-Cert string
+`
+	assert.Equal(t, exp, b.String())
+}
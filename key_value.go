@@ -0,0 +1,190 @@
+package structflag
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// keyValueCodec converts structs and maps from comma separated key=value
+// pairs, e.g. "host=db1,port=5432,tls=true". Struct keys are field names as
+// they are used in flag names. Values containing commas can be quoted using Go
+// syntax. Input starting with "{" is decoded as JSON.
+type keyValueCodec struct {
+	converter *StructToFlagsConverter
+}
+
+// formatConverter returns the converter using the format or the default one.
+func formatConverter(converter *StructToFlagsConverter) *StructToFlagsConverter {
+	if converter == nil {
+		return DefaultStructToFlagsConverter
+	}
+	return converter
+}
+
+func init() {
+	RegisterFormat("kv", keyValueCodec{})
+}
+
+// withConverter is implemented by format codecs which depend on the converter
+// using them.
+type withConverter interface {
+	withConverter(converter *StructToFlagsConverter) Codec
+}
+
+func (thiz keyValueCodec) withConverter(converter *StructToFlagsConverter) Codec {
+	return keyValueCodec{converter: converter}
+}
+
+// Match returns true for structs and maps with string keys.
+func (thiz keyValueCodec) Match(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct || (t.Kind() == reflect.Map && t.Key().Kind() == reflect.String)
+}
+
+// Decode parses the pairs into a new value. Fields which are not given get
+// zero values, just like when decoding JSON.
+func (thiz keyValueCodec) Decode(s string, val reflect.Value) error {
+	res := reflect.New(val.Type())
+	if strings.HasPrefix(strings.TrimSpace(s), "{") {
+		if err := json.Unmarshal([]byte(s), res.Interface()); err != nil {
+			return err
+		}
+		val.Set(res.Elem())
+		return nil
+	}
+	res = res.Elem()
+	if res.Kind() == reflect.Map {
+		res.Set(reflect.MakeMap(res.Type()))
+	}
+	pairs, err := splitPairs(s)
+	if err != nil {
+		return err
+	}
+	for _, pair := range pairs {
+		idx := strings.Index(pair, "=")
+		if idx < 0 {
+			return fmt.Errorf("invalid pair %q, expected key=value", pair)
+		}
+		key, text := strings.TrimSpace(pair[:idx]), strings.TrimSpace(pair[idx+1:])
+		if strings.HasPrefix(text, `"`) {
+			if text, err = strconv.Unquote(text); err != nil {
+				return fmt.Errorf("invalid quoted value for %s: %v", key, err)
+			}
+		}
+		if res.Kind() == reflect.Map {
+			elem := reflect.New(res.Type().Elem()).Elem()
+			if err := decodeString(text, elem); err != nil {
+				return fmt.Errorf("invalid value for %s: %v", key, err)
+			}
+			res.SetMapIndex(reflect.ValueOf(key).Convert(res.Type().Key()), elem)
+			continue
+		}
+		field, ok := formatConverter(thiz.converter).fieldByKey(res, key)
+		if !ok {
+			return fmt.Errorf("unknown key %q", key)
+		}
+		if err := decodeString(text, field); err != nil {
+			return fmt.Errorf("invalid value for %s: %v", key, err)
+		}
+	}
+	val.Set(res)
+	return nil
+}
+
+// Encode returns pairs for non-empty struct fields in declaration order or map
+// entries sorted by key.
+func (thiz keyValueCodec) Encode(val reflect.Value) (string, error) {
+	var pairs []string
+	add := func(key string, value reflect.Value) {
+		text := encodeString(value)
+		if strings.ContainsAny(text, `,="`) || strings.TrimSpace(text) != text {
+			text = strconv.Quote(text)
+		}
+		pairs = append(pairs, key+"="+text)
+	}
+	if val.Kind() == reflect.Map {
+		keys := make([]string, 0, val.Len())
+		for _, key := range val.MapKeys() {
+			keys = append(keys, key.String())
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			add(key, val.MapIndex(reflect.ValueOf(key).Convert(val.Type().Key())))
+		}
+		return strings.Join(pairs, ","), nil
+	}
+	for i := 0; i < val.NumField(); i++ {
+		if key, ok := formatConverter(thiz.converter).fieldKey(val.Type().Field(i)); ok && !isEmpty(val.Field(i)) {
+			add(key, val.Field(i))
+		}
+	}
+	return strings.Join(pairs, ","), nil
+}
+
+// fieldKey returns the key used for the struct field in text formats.
+func (thiz *StructToFlagsConverter) fieldKey(field reflect.StructField) (string, bool) {
+	if field.PkgPath != "" {
+		return "", false
+	}
+	name, ok := thiz.fieldName(field)
+	if !ok {
+		return "", false
+	}
+	return thiz.NameConverterFunc(name), true
+}
+
+// fieldByKey returns the struct field for the key. Keys are matched ignoring
+// case if there is no exact match.
+func (thiz *StructToFlagsConverter) fieldByKey(val reflect.Value, key string) (reflect.Value, bool) {
+	match := -1
+	for i := 0; i < val.NumField(); i++ {
+		name, ok := thiz.fieldKey(val.Type().Field(i))
+		if !ok {
+			continue
+		}
+		if name == key {
+			return val.Field(i), true
+		}
+		if match < 0 && strings.EqualFold(name, key) {
+			match = i
+		}
+	}
+	if match < 0 {
+		return reflect.Value{}, false
+	}
+	return val.Field(match), true
+}
+
+// splitPairs splits s at commas which are not inside double quotes.
+func splitPairs(s string) ([]string, error) {
+	var pairs []string
+	var quoted, escaped bool
+	start := 0
+	for i, c := range s {
+		switch {
+		case escaped:
+			escaped = false
+		case c == '\\' && quoted:
+			escaped = true
+		case c == '"':
+			quoted = !quoted
+		case c == ',' && !quoted:
+			pairs = append(pairs, s[start:i])
+			start = i + 1
+		}
+	}
+	if quoted {
+		return nil, fmt.Errorf("unterminated quoted value in %q", s)
+	}
+	pairs = append(pairs, s[start:])
+	res := pairs[:0]
+	for _, pair := range pairs {
+		if strings.TrimSpace(pair) != "" {
+			res = append(res, pair)
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,62 @@
+package structflag_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/surajbarkale/structflag"
+)
+
+type endpoint struct {
+	Host    string
+	Port    int
+	TLS     bool `json:"tls"`
+	Comment string
+}
+
+func TestKeyValueFormat(t *testing.T) {
+	val := &struct {
+		Endpoint *endpoint      `format:"kv"`
+		Labels   map[string]int `format:"kv"`
+		Backup   endpoint       `format:"kv"`
+		Plain    endpoint
+	}{Backup: endpoint{Host: "b", Port: 1}}
+	values, err := structflag.DefaultStructToFlagsConverter.Convert(val)
+	require.NoError(t, err)
+	assert.Contains(t, values, "Plain-Host")
+	assert.Equal(t, "Host=b,Port=1", values["Backup"].Default())
+
+	require.NoError(t, values["Endpoint"].Set("Host=db1,Port=5432,tls=true"))
+	assert.Equal(t, &endpoint{Host: "db1", Port: 5432, TLS: true}, val.Endpoint)
+	require.NoError(t, values["Endpoint"].Set(`host=db2, comment="a, b=c"`))
+	assert.Equal(t, &endpoint{Host: "db2", Comment: "a, b=c"}, val.Endpoint)
+	assert.Equal(t, `Host=db2,Comment="a, b=c"`, values["Endpoint"].String())
+	require.NoError(t, values["Endpoint"].Set(`{"Host":"db3"}`))
+	assert.Equal(t, &endpoint{Host: "db3"}, val.Endpoint)
+
+	assert.EqualError(t, values["Backup"].Set("Host=x,User=y"), `invalid value "Host=x,User=y" for flag Backup: unknown key "User"`)
+	assert.Error(t, values["Backup"].Set("Port=x"))
+	assert.Error(t, values["Backup"].Set("Host"))
+	assert.Error(t, values["Backup"].Set(`Host="x`))
+	assert.Equal(t, endpoint{Host: "b", Port: 1}, val.Backup)
+
+	require.NoError(t, values["Labels"].Set("b=2,a=1"))
+	assert.Equal(t, map[string]int{"a": 1, "b": 2}, val.Labels)
+	assert.Equal(t, "a=1,b=2", values["Labels"].String())
+}
+
+func TestKeyValueFormatUsesNameConverter(t *testing.T) {
+	converter := structflag.NewStructToFlagsConverter()
+	converter.NameConverterFunc = strings.ToLower
+	val := &struct {
+		Endpoint endpoint `format:"kv"`
+	}{}
+	values, err := converter.Convert(val)
+	require.NoError(t, err)
+	require.NoError(t, values["endpoint"].Set("host=db,port=1"))
+	assert.Equal(t, endpoint{Host: "db", Port: 1}, val.Endpoint)
+	assert.Equal(t, "host=db,port=1", values["endpoint"].String())
+}
@@ -0,0 +1,51 @@
+/*
+Package langflag adds support for golang.org/x/text/language.Tag fields to
+structflag. Importing this package registers a codec which parses BCP 47 tags
+using language.Parse:
+
+	import _ "github.com/surajbarkale/structflag/langflag"
+
+Empty string is parsed as language.Und.
+*/
+package langflag
+
+import (
+	"reflect"
+
+	"golang.org/x/text/language"
+
+	"github.com/surajbarkale/structflag"
+)
+
+var tagType = reflect.TypeOf(language.Tag{})
+
+// Codec converts language.Tag values.
+type Codec struct{}
+
+func init() {
+	structflag.RegisterCodec(&Codec{})
+}
+
+// Match returns true for language.Tag.
+func (thiz *Codec) Match(t reflect.Type) bool {
+	return t == tagType
+}
+
+// Decode parses the tag, e.g. "en-US".
+func (thiz *Codec) Decode(s string, val reflect.Value) error {
+	if s == "" {
+		val.Set(reflect.ValueOf(language.Und))
+		return nil
+	}
+	tag, err := language.Parse(s)
+	if err != nil {
+		return err
+	}
+	val.Set(reflect.ValueOf(tag))
+	return nil
+}
+
+// Encode returns the canonical form of the tag.
+func (thiz *Codec) Encode(val reflect.Value) (string, error) {
+	return val.Interface().(language.Tag).String(), nil
+}
@@ -0,0 +1,37 @@
+package langflag_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+
+	"github.com/surajbarkale/structflag"
+	_ "github.com/surajbarkale/structflag/langflag"
+)
+
+func TestLanguageValues(t *testing.T) {
+	val := &struct {
+		Locale    language.Tag
+		Fallback  *language.Tag
+		Supported []language.Tag
+	}{Locale: language.English}
+	values, err := structflag.DefaultStructToFlagsConverter.Convert(val)
+	require.NoError(t, err)
+	assert.Len(t, values, 3)
+	assert.Equal(t, "en", values["Locale"].Default())
+
+	require.NoError(t, values["Locale"].Set("pt_br"))
+	assert.Equal(t, language.BrazilianPortuguese, val.Locale)
+	assert.Equal(t, "pt-BR", values["Locale"].String())
+	assert.Error(t, values["Locale"].Set("e"))
+	assert.Equal(t, language.BrazilianPortuguese, val.Locale)
+
+	require.NoError(t, values["Fallback"].Set("de"))
+	assert.Equal(t, language.German, *val.Fallback)
+	require.NoError(t, values["Supported"].Set(`["en-GB", "fr"]`))
+	assert.Equal(t, []language.Tag{language.BritishEnglish, language.French}, val.Supported)
+	require.NoError(t, values["Locale"].Set(""))
+	assert.Equal(t, language.Und, val.Locale)
+}
@@ -0,0 +1,81 @@
+package structflag
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// LatLon is a geographic coordinate in decimal degrees written as "lat,lon",
+// e.g. "52.1,13.4". The same text form can be used with other structs having
+// float Lat and Lon fields by adding `format:"latlon"` struct tag.
+type LatLon struct {
+	Lat float64
+	Lon float64
+}
+
+var latLonType = reflect.TypeOf(LatLon{})
+
+// latLonCodec converts "lat,lon" strings with range validation.
+type latLonCodec struct {
+	// any matches any struct with the coordinate fields, otherwise only LatLon
+	// is matched.
+	any bool
+}
+
+func init() {
+	RegisterCodec(latLonCodec{})
+	RegisterFormat("latlon", latLonCodec{any: true})
+}
+
+// Match returns true for LatLon or structs with coordinate fields.
+func (thiz latLonCodec) Match(t reflect.Type) bool {
+	if !thiz.any {
+		return t == latLonType
+	}
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	for _, name := range []string{"Lat", "Lon"} {
+		if f, ok := t.FieldByName(name); !ok || !isFloatKind(f.Type.Kind()) {
+			return false
+		}
+	}
+	return true
+}
+
+// Decode parses the coordinate. Latitude must be within [-90, 90] and
+// longitude within [-180, 180].
+func (thiz latLonCodec) Decode(s string, val reflect.Value) error {
+	parts := strings.Split(s, ",")
+	if len(parts) != 2 {
+		return fmt.Errorf("invalid coordinate %q, expected lat,lon", s)
+	}
+	lat, err := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	if err != nil || !isFinite(lat) || lat < -90 || lat > 90 {
+		return fmt.Errorf("invalid latitude in %q, expected number between -90 and 90", s)
+	}
+	lon, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+	if err != nil || !isFinite(lon) || lon < -180 || lon > 180 {
+		return fmt.Errorf("invalid longitude in %q, expected number between -180 and 180", s)
+	}
+	res := reflect.New(val.Type()).Elem()
+	res.Set(val)
+	res.FieldByName("Lat").SetFloat(lat)
+	res.FieldByName("Lon").SetFloat(lon)
+	val.Set(res)
+	return nil
+}
+
+// Encode returns "lat,lon".
+func (thiz latLonCodec) Encode(val reflect.Value) (string, error) {
+	return encodeString(val.FieldByName("Lat")) + "," + encodeString(val.FieldByName("Lon")), nil
+}
+
+// isFinite returns false for NaN and infinities, which pass range checks
+// because comparisons with NaN are always false.
+func isFinite(f float64) bool {
+	return !math.IsNaN(f) && !math.IsInf(f, 0)
+}
@@ -0,0 +1,47 @@
+package structflag_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/surajbarkale/structflag"
+)
+
+type place struct {
+	Name     string
+	Lat, Lon float32
+}
+
+func TestLatLonValues(t *testing.T) {
+	val := &struct {
+		Center structflag.LatLon
+		Origin *structflag.LatLon
+		Home   place `format:"latlon"`
+	}{Center: structflag.LatLon{Lat: 52.52, Lon: 13.405}}
+	values, err := structflag.DefaultStructToFlagsConverter.Convert(val)
+	require.NoError(t, err)
+	assert.Len(t, values, 3)
+	assert.Equal(t, "52.52,13.405", values["Center"].Default())
+
+	require.NoError(t, values["Center"].Set("-33.87, 151.21"))
+	assert.Equal(t, structflag.LatLon{Lat: -33.87, Lon: 151.21}, val.Center)
+	require.NoError(t, values["Origin"].Set("0,0"))
+	assert.Equal(t, &structflag.LatLon{}, val.Origin)
+
+	val.Home.Name = "home"
+	require.NoError(t, values["Home"].Set("40.5,-74.25"))
+	assert.Equal(t, place{Name: "home", Lat: 40.5, Lon: -74.25}, val.Home)
+	assert.Equal(t, "40.5,-74.25", values["Home"].String())
+
+	assert.EqualError(t, values["Center"].Set("91,0"), `invalid value "91,0" for flag Center: invalid latitude in "91,0", expected number between -90 and 90`)
+	assert.EqualError(t, values["Center"].Set("0,-181"), `invalid value "0,-181" for flag Center: invalid longitude in "0,-181", expected number between -180 and 180`)
+	assert.Error(t, values["Center"].Set("1"))
+	assert.Error(t, values["Center"].Set("1,2,3"))
+	assert.EqualError(t, values["Center"].Set("NaN,NaN"), `invalid value "NaN,NaN" for flag Center: invalid latitude in "NaN,NaN", expected number between -90 and 90`)
+	assert.EqualError(t, values["Center"].Set("0,NaN"), `invalid value "0,NaN" for flag Center: invalid longitude in "0,NaN", expected number between -180 and 180`)
+	assert.Error(t, values["Center"].Set("+Inf,0"))
+	assert.Error(t, values["Center"].Set("0,-Inf"))
+	assert.Equal(t, structflag.LatLon{Lat: -33.87, Lon: 151.21}, val.Center)
+}
@@ -0,0 +1,144 @@
+package structflag
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// UnmarshalLenientJSON parses JSON extended with a subset of JSON5 syntax and
+// stores the result in v. It accepts comments, trailing commas, unquoted object
+// keys and single quoted strings. Use it with StructToFlagsConverter.Unmarshal
+// to make handwritten values easier to type.
+func UnmarshalLenientJSON(data []byte, v interface{}) error {
+	normalized, err := normalizeLenientJSON(string(data))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal([]byte(normalized), v)
+}
+
+// normalizeLenientJSON converts lenient JSON into standard JSON.
+func normalizeLenientJSON(s string) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == '"' || c == '\'':
+			end, err := copyString(&b, s, i)
+			if err != nil {
+				return "", err
+			}
+			i = end
+		case c == '/':
+			end, err := skipComment(s, i)
+			if err != nil {
+				return "", err
+			}
+			i = end
+		case c == ',':
+			next, err := skipSpace(s, i+1)
+			if err != nil {
+				return "", err
+			}
+			// Drop trailing commas
+			if next >= len(s) || (s[next] != '}' && s[next] != ']') {
+				b.WriteByte(c)
+			}
+			i++
+		case isIdentStart(c):
+			end := i + 1
+			for end < len(s) && isIdentPart(s[end]) {
+				end++
+			}
+			next, err := skipSpace(s, end)
+			if err != nil {
+				return "", err
+			}
+			// Identifiers followed by a colon are object keys
+			if next < len(s) && s[next] == ':' {
+				b.WriteString(`"` + s[i:end] + `"`)
+			} else {
+				b.WriteString(s[i:end])
+			}
+			i = end
+		default:
+			b.WriteByte(c)
+			i++
+		}
+	}
+	return b.String(), nil
+}
+
+// copyString writes the string starting at i as a double quoted string and
+// returns the index after it.
+func copyString(b *strings.Builder, s string, i int) (int, error) {
+	quote := s[i]
+	b.WriteByte('"')
+	for j := i + 1; j < len(s); j++ {
+		switch c := s[j]; {
+		case c == '\\' && j+1 < len(s):
+			j++
+			// Single quotes do not need escaping in JSON
+			if s[j] == '\'' {
+				b.WriteByte('\'')
+			} else {
+				b.WriteByte(c)
+				b.WriteByte(s[j])
+			}
+		case c == quote:
+			b.WriteByte('"')
+			return j + 1, nil
+		case c == '"':
+			b.WriteString(`\"`)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return 0, fmt.Errorf("unterminated string at offset %d", i)
+}
+
+// skipComment returns the index after the comment starting at i.
+func skipComment(s string, i int) (int, error) {
+	switch {
+	case strings.HasPrefix(s[i:], "//"):
+		if end := strings.IndexByte(s[i:], '\n'); end >= 0 {
+			return i + end + 1, nil
+		}
+		return len(s), nil
+	case strings.HasPrefix(s[i:], "/*"):
+		if end := strings.Index(s[i+2:], "*/"); end >= 0 {
+			return i + 2 + end + 2, nil
+		}
+		return 0, fmt.Errorf("unterminated comment at offset %d", i)
+	}
+	return 0, fmt.Errorf("invalid character '/' at offset %d", i)
+}
+
+// skipSpace returns the index of the first character after i which is not a
+// white space or a part of a comment.
+func skipSpace(s string, i int) (int, error) {
+	for i < len(s) {
+		switch s[i] {
+		case ' ', '\t', '\n', '\r':
+			i++
+		case '/':
+			end, err := skipComment(s, i)
+			if err != nil {
+				return 0, err
+			}
+			i = end
+		default:
+			return i, nil
+		}
+	}
+	return i, nil
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || c == '$' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isIdentPart(c byte) bool {
+	return isIdentStart(c) || (c >= '0' && c <= '9')
+}
@@ -0,0 +1,48 @@
+package structflag_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/surajbarkale/structflag"
+)
+
+func TestUnmarshalLenientJSON(t *testing.T) {
+	var res interface{}
+	require.NoError(t, structflag.UnmarshalLenientJSON([]byte(`{
+		// Single line comment
+		name: 'it\'s "quoted"', /* block comment */
+		list: [1, 2, 3,],
+		$ref: null,
+		"url": "http://x//y",
+		nested: {ok: true,},
+	}`), &res))
+	assert.Equal(t, map[string]interface{}{
+		"name":   `it's "quoted"`,
+		"list":   []interface{}{float64(1), float64(2), float64(3)},
+		"$ref":   nil,
+		"url":    "http://x//y",
+		"nested": map[string]interface{}{"ok": true},
+	}, res)
+
+	for _, invalid := range []string{`{a: 'x}`, `{a: 1 /* x`, `{a: b}`, `[1 / 2]`} {
+		assert.Error(t, structflag.UnmarshalLenientJSON([]byte(invalid), &res), invalid)
+	}
+}
+
+func TestLenientJSONValues(t *testing.T) {
+	converter := structflag.NewStructToFlagsConverter()
+	converter.Unmarshal = structflag.UnmarshalLenientJSON
+	val := &struct {
+		Labels map[string]string
+		Ports  []int
+	}{}
+	values, err := converter.Convert(val)
+	require.NoError(t, err)
+	require.NoError(t, values["Labels"].Set("{app: 'web', tier: 'front',}"))
+	assert.Equal(t, map[string]string{"app": "web", "tier": "front"}, val.Labels)
+	require.NoError(t, values["Ports"].Set("[80, 443, // https\n]"))
+	assert.Equal(t, []int{80, 443}, val.Ports)
+}
@@ -0,0 +1,39 @@
+package structflag
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
+var locationType = reflect.TypeOf((*time.Location)(nil))
+
+// locationCodec loads *time.Location values from IANA time zone names.
+type locationCodec struct{}
+
+func init() {
+	RegisterCodec(locationCodec{})
+}
+
+// Match returns true for *time.Location.
+func (locationCodec) Match(t reflect.Type) bool {
+	return t == locationType
+}
+
+// Decode loads the time zone, e.g. "America/New_York", "UTC" or "Local".
+func (locationCodec) Decode(s string, val reflect.Value) error {
+	loc, err := time.LoadLocation(s)
+	if err != nil {
+		return fmt.Errorf("unknown time zone %q", s)
+	}
+	val.Set(reflect.ValueOf(loc))
+	return nil
+}
+
+// Encode returns the name of the time zone or empty string for nil.
+func (locationCodec) Encode(val reflect.Value) (string, error) {
+	if val.IsNil() {
+		return "", nil
+	}
+	return val.Interface().(*time.Location).String(), nil
+}
@@ -0,0 +1,29 @@
+package structflag_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/surajbarkale/structflag"
+)
+
+func TestLocationValues(t *testing.T) {
+	val := &struct {
+		Zone     *time.Location
+		Fallback *time.Location
+	}{Fallback: time.UTC}
+	values, err := structflag.DefaultStructToFlagsConverter.Convert(val)
+	require.NoError(t, err)
+	assert.Len(t, values, 2)
+	assert.Equal(t, "UTC", values["Fallback"].Default())
+	assert.Equal(t, "", values["Zone"].String())
+
+	require.NoError(t, values["Zone"].Set("America/New_York"))
+	assert.Equal(t, "America/New_York", val.Zone.String())
+	assert.Equal(t, "America/New_York", values["Zone"].String())
+	assert.EqualError(t, values["Zone"].Set("Mars/Olympus"), `invalid value "Mars/Olympus" for flag Zone: unknown time zone "Mars/Olympus"`)
+	assert.Equal(t, "America/New_York", val.Zone.String())
+}
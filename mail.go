@@ -0,0 +1,84 @@
+package structflag
+
+import (
+	"net/mail"
+	"reflect"
+	"strings"
+)
+
+var mailAddressType = reflect.TypeOf(mail.Address{})
+
+// mailCodec converts mail.Address values and slices of them from RFC 5322
+// strings, e.g. "Name <a@b.c>, d@e.f".
+type mailCodec struct{}
+
+func init() {
+	RegisterCodec(mailCodec{})
+}
+
+// Match returns true for mail.Address and slices of mail.Address or
+// *mail.Address.
+func (mailCodec) Match(t reflect.Type) bool {
+	if t.Kind() == reflect.Slice {
+		t = t.Elem()
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+	}
+	return t == mailAddressType
+}
+
+// Decode parses a single address or a comma separated address list. Empty
+// string is decoded as nil slice.
+func (mailCodec) Decode(s string, val reflect.Value) error {
+	if val.Kind() != reflect.Slice {
+		addr, err := mail.ParseAddress(s)
+		if err != nil {
+			return err
+		}
+		val.Set(reflect.ValueOf(*addr))
+		return nil
+	}
+	if strings.TrimSpace(s) == "" {
+		val.Set(reflect.Zero(val.Type()))
+		return nil
+	}
+	list, err := mail.ParseAddressList(s)
+	if err != nil {
+		return err
+	}
+	res := reflect.MakeSlice(val.Type(), 0, len(list))
+	for _, addr := range list {
+		elem := reflect.ValueOf(addr)
+		if val.Type().Elem() == mailAddressType {
+			elem = elem.Elem()
+		}
+		res = reflect.Append(res, elem)
+	}
+	val.Set(res)
+	return nil
+}
+
+// Encode returns the address or comma separated address list.
+func (mailCodec) Encode(val reflect.Value) (string, error) {
+	if val.Kind() != reflect.Slice {
+		addr := val.Interface().(mail.Address)
+		if addr.Address == "" {
+			return "", nil
+		}
+		return addr.String(), nil
+	}
+	items := make([]string, 0, val.Len())
+	for i := 0; i < val.Len(); i++ {
+		elem := val.Index(i)
+		if elem.Kind() == reflect.Ptr {
+			if elem.IsNil() {
+				continue
+			}
+			elem = elem.Elem()
+		}
+		addr := elem.Interface().(mail.Address)
+		items = append(items, addr.String())
+	}
+	return strings.Join(items, ", "), nil
+}
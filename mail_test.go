@@ -0,0 +1,41 @@
+package structflag_test
+
+import (
+	"net/mail"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/surajbarkale/structflag"
+)
+
+func TestMailValues(t *testing.T) {
+	val := &struct {
+		From    mail.Address
+		ReplyTo *mail.Address
+		To      []*mail.Address
+		Cc      []mail.Address
+	}{From: mail.Address{Name: "Alerts", Address: "alerts@example.com"}}
+	values, err := structflag.DefaultStructToFlagsConverter.Convert(val)
+	require.NoError(t, err)
+	assert.Len(t, values, 4)
+	assert.Equal(t, `"Alerts" <alerts@example.com>`, values["From"].Default())
+
+	require.NoError(t, values["ReplyTo"].Set("ops@example.com"))
+	assert.Equal(t, &mail.Address{Address: "ops@example.com"}, val.ReplyTo)
+	assert.Error(t, values["From"].Set("not an address"))
+
+	require.NoError(t, values["To"].Set("Jane Doe <jane@example.com>, bob@example.com"))
+	assert.Equal(t, []*mail.Address{
+		{Name: "Jane Doe", Address: "jane@example.com"},
+		{Address: "bob@example.com"},
+	}, val.To)
+	assert.Equal(t, `"Jane Doe" <jane@example.com>, <bob@example.com>`, values["To"].String())
+
+	require.NoError(t, values["Cc"].Set("a@b.c,d@e.f"))
+	assert.Equal(t, []mail.Address{{Address: "a@b.c"}, {Address: "d@e.f"}}, val.Cc)
+	require.NoError(t, values["Cc"].Set(""))
+	assert.Nil(t, val.Cc)
+	assert.Error(t, values["Cc"].Set("a@b.c, bad"))
+}
@@ -0,0 +1,93 @@
+package structflag
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// FlagSpec describes a single flag in a machine-readable form.
+type FlagSpec struct {
+	// Name is the flag name.
+	Name string `json:"name"`
+	// Aliases are additional flag names.
+	Aliases []string `json:"aliases,omitempty"`
+	// OldNames are the previous names of the flag from "wasNamed" struct tag.
+	OldNames []string `json:"oldNames,omitempty"`
+	// Type is the Go type of the field.
+	Type string `json:"type"`
+	// Description is the description of the flag without template expansion.
+	Description string `json:"description,omitempty"`
+	// Default is the default value of the flag. It is empty for secret values.
+	Default string `json:"default,omitempty"`
+	// Env is the value of the struct tag selected by EnvTag.
+	Env string `json:"env,omitempty"`
+	// Required is the value of the struct tag selected by RequiredTag.
+	Required bool `json:"required,omitempty"`
+	// Hidden is the value of the struct tag selected by HiddenTag.
+	Hidden bool `json:"hidden,omitempty"`
+	// Deprecated is the value of the struct tag selected by DeprecatedTag,
+	// usually a message telling what to use instead.
+	Deprecated string `json:"deprecated,omitempty"`
+	// Since is the value of the struct tag selected by SinceTag, the version
+	// which introduced the flag.
+	Since string `json:"since,omitempty"`
+	// Removed is the value of the struct tag selected by RemovedTag, the
+	// version which removed the flag.
+	Removed string `json:"removed,omitempty"`
+	// Choices are the comma separated items of the struct tag selected by
+	// ChoicesTag.
+	Choices []string `json:"choices,omitempty"`
+	// Secret is true if the value must not be shown to users.
+	Secret bool `json:"secret,omitempty"`
+	// Group is the group of the value.
+	Group string `json:"group,omitempty"`
+	// Example is the value of the struct tag selected by ExampleTag.
+	Example string `json:"example,omitempty"`
+}
+
+// Manifest lists all flags generated from the structure sorted by name, e.g. for
+// generating documentation or checking the command line interface in tests.
+// Metadata is read from the struct tags selected by the converter. The input is
+// not modified. You must pass a pointer to the value.
+func (thiz *StructToFlagsConverter) Manifest(input interface{}) ([]FlagSpec, error) {
+	// Convert initializes nil struct pointers so work on a copy
+	values, err := thiz.Convert(deepCopy(reflect.ValueOf(input)).Interface())
+	if err != nil {
+		return nil, err
+	}
+	specs := make([]FlagSpec, 0, len(values))
+	for name, value := range values {
+		tag := value.Field().Tag
+		spec := FlagSpec{
+			Name:        name,
+			Aliases:     value.Aliases(),
+			OldNames:    value.OldNames(),
+			Type:        value.Field().Type.String(),
+			Description: value.Description(),
+			Env:         tagValue(tag, thiz.EnvTag),
+			Deprecated:  tagValue(tag, thiz.DeprecatedTag),
+			Since:       tagValue(tag, thiz.SinceTag),
+			Removed:     tagValue(tag, thiz.RemovedTag),
+			Choices:     tagList(tag, thiz.ChoicesTag),
+			Secret:      value.IsSecret(),
+			Group:       value.Group(),
+			Example:     tagValue(tag, thiz.ExampleTag),
+		}
+		if !spec.Secret {
+			spec.Default = value.Default()
+		}
+		spec.Required, _ = strconv.ParseBool(tagValue(tag, thiz.RequiredTag))
+		spec.Hidden, _ = strconv.ParseBool(tagValue(tag, thiz.HiddenTag))
+		specs = append(specs, spec)
+	}
+	sort.Slice(specs, func(i, j int) bool {
+		return specs[i].Name < specs[j].Name
+	})
+	return specs, nil
+}
+
+// Manifest lists flags of input using DefaultStructToFlagsConverter.
+func Manifest(input interface{}) ([]FlagSpec, error) {
+	return DefaultStructToFlagsConverter.Manifest(input)
+}
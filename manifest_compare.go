@@ -0,0 +1,94 @@
+package structflag
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ManifestChangeKind is the kind of a difference between two manifests.
+type ManifestChangeKind string
+
+const (
+	// FlagRemoved means that a flag does not exist in the new manifest under
+	// its name, an alias or a previous name.
+	FlagRemoved ManifestChangeKind = "removed"
+	// FlagTypeChanged means that the Go type of a flag changed.
+	FlagTypeChanged ManifestChangeKind = "type"
+	// FlagDefaultChanged means that the default value of a flag changed.
+	FlagDefaultChanged ManifestChangeKind = "default"
+	// FlagRequired means that a flag became required or a new flag is required.
+	FlagRequired ManifestChangeKind = "required"
+)
+
+// ManifestChange describes a difference between two manifests which can break
+// existing command lines or configuration files.
+type ManifestChange struct {
+	// Kind is the kind of the difference.
+	Kind ManifestChangeKind `json:"kind"`
+	// Name is the name of the flag in the old manifest or the name of a new
+	// required flag.
+	Name string `json:"name"`
+	// Old is the type or the default value in the old manifest.
+	Old string `json:"old,omitempty"`
+	// New is the type or the default value in the new manifest.
+	New string `json:"new,omitempty"`
+}
+
+// String describes the change, e.g. "flag Port changed type from int to string".
+func (thiz ManifestChange) String() string {
+	switch thiz.Kind {
+	case FlagRemoved:
+		return fmt.Sprintf("flag %s was removed", thiz.Name)
+	case FlagTypeChanged:
+		return fmt.Sprintf("flag %s changed type from %s to %s", thiz.Name, thiz.Old, thiz.New)
+	case FlagDefaultChanged:
+		return fmt.Sprintf("flag %s changed default from %q to %q", thiz.Name, thiz.Old, thiz.New)
+	case FlagRequired:
+		return fmt.Sprintf("flag %s is required", thiz.Name)
+	}
+	return fmt.Sprintf("flag %s changed %s", thiz.Name, thiz.Kind)
+}
+
+// CompareManifests returns the changes from the previous manifest to the
+// current one sorted by flag name, e.g. to fail a CI job when the command line
+// interface of a new build is not compatible with the released one. Flags which
+// were renamed with "wasNamed" struct tag or kept as an alias are matched by the
+// new name. Defaults of secret flags are not compared.
+func CompareManifests(previous, current []FlagSpec) []ManifestChange {
+	lookup := map[string]FlagSpec{}
+	for _, spec := range current {
+		for _, name := range append(append([]string{spec.Name}, spec.Aliases...), spec.OldNames...) {
+			if _, ok := lookup[name]; !ok || name == spec.Name {
+				lookup[name] = spec
+			}
+		}
+	}
+	var changes []ManifestChange
+	matched := map[string]bool{}
+	for _, before := range previous {
+		after, ok := lookup[before.Name]
+		if !ok {
+			changes = append(changes, ManifestChange{Kind: FlagRemoved, Name: before.Name})
+			continue
+		}
+		matched[after.Name] = true
+		if before.Type != after.Type {
+			changes = append(changes, ManifestChange{Kind: FlagTypeChanged, Name: before.Name, Old: before.Type, New: after.Type})
+		}
+		if !before.Secret && !after.Secret && before.Default != after.Default {
+			changes = append(changes, ManifestChange{Kind: FlagDefaultChanged, Name: before.Name, Old: before.Default, New: after.Default})
+		}
+		if !before.Required && after.Required {
+			changes = append(changes, ManifestChange{Kind: FlagRequired, Name: before.Name})
+		}
+	}
+	for _, spec := range current {
+		if !matched[spec.Name] && spec.Required {
+			changes = append(changes, ManifestChange{Kind: FlagRequired, Name: spec.Name})
+		}
+	}
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].Name < changes[j].Name
+	})
+	return changes
+}
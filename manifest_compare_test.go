@@ -0,0 +1,50 @@
+package structflag_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/surajbarkale/structflag"
+)
+
+func TestCompareManifests(t *testing.T) {
+	previous := []structflag.FlagSpec{
+		{Name: "Addr", Type: "string", Default: ":80"},
+		{Name: "Count", Type: "int", Default: "2"},
+		{Name: "Debug", Type: "bool", Default: "false"},
+		{Name: "Host", Type: "string"},
+		{Name: "Timeout", Type: "int", Default: "10"},
+		{Name: "Token", Type: "string", Secret: true},
+		{Name: "v", Type: "bool", Default: "false"},
+	}
+	current := []structflag.FlagSpec{
+		{Name: "Count", Type: "int", Default: "2", Required: true},
+		{Name: "Listen", Type: "string", Default: ":80", OldNames: []string{"Addr"}},
+		{Name: "Mode", Type: "string", Required: true},
+		{Name: "Server", Type: "string", Aliases: []string{"Host"}},
+		{Name: "Timeout", Type: "time.Duration", Default: "10s"},
+		{Name: "Token", Type: "string", Secret: true},
+		{Name: "Verbose", Type: "bool", Default: "false", Aliases: []string{"v"}},
+	}
+	changes := structflag.CompareManifests(previous, current)
+	assert.Equal(t, []structflag.ManifestChange{
+		{Kind: structflag.FlagRequired, Name: "Count"},
+		{Kind: structflag.FlagRemoved, Name: "Debug"},
+		{Kind: structflag.FlagRequired, Name: "Mode"},
+		{Kind: structflag.FlagTypeChanged, Name: "Timeout", Old: "int", New: "time.Duration"},
+		{Kind: structflag.FlagDefaultChanged, Name: "Timeout", Old: "10", New: "10s"},
+	}, changes)
+	var messages []string
+	for _, change := range changes {
+		messages = append(messages, change.String())
+	}
+	assert.Equal(t, []string{
+		"flag Count is required",
+		"flag Debug was removed",
+		"flag Mode is required",
+		"flag Timeout changed type from int to time.Duration",
+		`flag Timeout changed default from "10" to "10s"`,
+	}, messages)
+	assert.Empty(t, structflag.CompareManifests(current, current))
+}
@@ -0,0 +1,60 @@
+package structflag_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/surajbarkale/structflag"
+)
+
+func TestManifest(t *testing.T) {
+	type server struct {
+		Addr string `description:"Listen address" env:"ADDR" required:"true" aliases:"a" example:"localhost:80"`
+	}
+	val := &struct {
+		Mode   string `choices:"fast, slow" deprecated:"use -Speed"`
+		Token  string `secret:"true" hidden:"true"`
+		Server *server
+		Count  int `since:"v1.4" removed:"v2.0"`
+	}{Mode: "fast", Token: "abc", Count: 2}
+	specs, err := structflag.Manifest(val)
+	require.NoError(t, err)
+	assert.Nil(t, val.Server)
+	assert.Equal(t, []structflag.FlagSpec{
+		{Name: "Count", Type: "int", Default: "2", Since: "v1.4", Removed: "v2.0"},
+		{Name: "Mode", Type: "string", Default: "fast", Deprecated: "use -Speed", Choices: []string{"fast", "slow"}},
+		{Name: "Server-Addr", Aliases: []string{"a"}, Type: "string", Description: "Listen address", Env: "ADDR", Required: true, Example: "localhost:80"},
+		{Name: "Token", Type: "string", Hidden: true, Secret: true},
+	}, specs)
+}
+
+func TestManifestTags(t *testing.T) {
+	converter := structflag.NewStructToFlagsConverter()
+	converter.EnvTag = "envvar"
+	converter.ChoicesTag = "oneOf"
+	converter.RequiredTag = "mandatory"
+	converter.HiddenTag = ""
+	converter.DeprecatedTag = "obsolete"
+	converter.SinceTag = "added"
+	converter.RemovedTag = "dropped"
+	specs, err := converter.Manifest(&struct {
+		Mode string `envvar:"MODE" oneOf:"a,b" mandatory:"true" hidden:"true" obsolete:"use -Speed" added:"v1" dropped:"v2" env:"X" required:"false"`
+	}{})
+	require.NoError(t, err)
+	assert.Equal(t, []structflag.FlagSpec{
+		{Name: "Mode", Type: "string", Env: "MODE", Required: true, Deprecated: "use -Speed", Since: "v1", Removed: "v2", Choices: []string{"a", "b"}},
+	}, specs)
+}
+
+func TestManifestJSON(t *testing.T) {
+	specs, err := structflag.Manifest(&struct {
+		Port int `description:"Port"`
+	}{Port: 80})
+	require.NoError(t, err)
+	encoded, err := json.Marshal(specs)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"name":"Port","type":"int","description":"Port","default":"80"}]`, string(encoded))
+}
@@ -0,0 +1,126 @@
+package structflag
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Merger copies the fields which are set in one struct into another struct of
+// the same type. A field is set if it is not the zero value, so pointer fields
+// can be used to tell an explicit zero value from an unset one. Nested structs
+// and pointers to structs are merged field by field. Unexported fields are not
+// changed.
+type Merger struct {
+	// OverwriteZero copies zero values too, so every exported field of the
+	// destination is replaced.
+	OverwriteZero bool
+	// AppendSlices appends slices to the existing ones instead of replacing
+	// them.
+	AppendSlices bool
+	// DeepMergeMaps merges maps key by key instead of replacing them. Struct,
+	// map and pointer items present in both maps are merged recursively.
+	DeepMergeMaps bool
+	// MergeTag is used to query struct tag to get the merge strategy of a field
+	// overriding the options. The strategy is "append" for slices, "deep" for
+	// maps and structs or "replace" to copy the whole value, e.g.
+	// `merge:"append"`.
+	MergeTag string
+}
+
+// DefaultMerger is the merger used by Merge.
+var DefaultMerger = NewMerger()
+
+// NewMerger returns a merger which skips zero values, replaces slices, merges
+// maps key by key and reads merge strategies from "merge" struct tag. The
+// returned instance can be customized by changing fields.
+func NewMerger() *Merger {
+	return &Merger{
+		DeepMergeMaps: true,
+		MergeTag:      "merge",
+	}
+}
+
+// Merge copies the fields which are set in src into dst using DefaultMerger.
+// You must pass pointers to values of the same type.
+func Merge(dst, src interface{}) error {
+	return DefaultMerger.Merge(dst, src)
+}
+
+// Merge copies the fields which are set in src into dst. You must pass pointers
+// to values of the same type. Values copied from src do not share memory with
+// src.
+func (thiz *Merger) Merge(dst, src interface{}) error {
+	d, s := reflect.ValueOf(dst), reflect.ValueOf(src)
+	if d.Kind() != reflect.Ptr || d.IsNil() {
+		return fmt.Errorf("merge destination must be a non-nil pointer, got %T", dst)
+	}
+	if d.Type() != s.Type() {
+		return fmt.Errorf("can not merge %T into %T", src, dst)
+	}
+	if !s.IsNil() {
+		thiz.merge(d.Elem(), s.Elem(), "")
+	}
+	return nil
+}
+
+// merge copies src into dst according to the options and the merge strategy
+// from the struct tag of the field, which is empty for values which are not
+// fields or do not have the tag.
+func (thiz *Merger) merge(dst, src reflect.Value, strategy string) {
+	if src.IsZero() && !thiz.OverwriteZero {
+		return
+	}
+	appendSlices := (thiz.AppendSlices || strategy == "append") && strategy != "replace"
+	deepMaps := (thiz.DeepMergeMaps || strategy == "deep") && strategy != "replace"
+	t := src.Type()
+	switch {
+	case isCodecType(t) || isAtomic(t) || isTextType(t) || strategy == "replace":
+	case t.Kind() == reflect.Struct:
+		for i := 0; i < src.NumField(); i++ {
+			if dst.Field(i).CanSet() {
+				var fieldStrategy string
+				if thiz.MergeTag != "" {
+					fieldStrategy = t.Field(i).Tag.Get(thiz.MergeTag)
+				}
+				thiz.merge(dst.Field(i), src.Field(i), fieldStrategy)
+			}
+		}
+		return
+	case t.Kind() == reflect.Ptr && isMergeable(t) && !src.IsNil() && !dst.IsNil():
+		thiz.merge(dst.Elem(), src.Elem(), strategy)
+		return
+	case t.Kind() == reflect.Slice && appendSlices && !src.IsNil():
+		dst.Set(reflect.AppendSlice(dst, deepCopy(src)))
+		return
+	case t.Kind() == reflect.Map && deepMaps && !src.IsNil():
+		if dst.IsNil() {
+			dst.Set(reflect.MakeMapWithSize(t, src.Len()))
+		}
+		for _, key := range src.MapKeys() {
+			// Keys present in src are set even if the item is the zero value
+			item := deepCopy(src.MapIndex(key))
+			if existing := dst.MapIndex(key); existing.IsValid() && isMergeable(t.Elem()) {
+				item = deepCopy(existing)
+				thiz.merge(item, src.MapIndex(key), strategy)
+			}
+			dst.SetMapIndex(key, item)
+		}
+		return
+	}
+	assign(dst, deepCopy(src))
+}
+
+// isMergeable returns true for types which are merged recursively instead of
+// being replaced.
+func isMergeable(t reflect.Type) bool {
+	if isCodecType(t) || isAtomic(t) || isTextType(t) {
+		return false
+	}
+	switch t.Kind() {
+	case reflect.Struct, reflect.Map:
+		return true
+	case reflect.Ptr:
+		return t.Elem().Kind() == reflect.Struct && !isCodecType(t.Elem()) && !isTextType(t.Elem())
+	}
+	return false
+}
@@ -0,0 +1,144 @@
+package structflag_test
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/surajbarkale/structflag"
+)
+
+type mergeServer struct {
+	Host    string
+	Port    int
+	Timeout *time.Duration
+}
+
+type mergeConfig struct {
+	Name    string
+	Debug   *bool
+	Tags    []string
+	Limits  map[string]int
+	Servers map[string]mergeServer
+	Main    mergeServer
+	Backup  *mergeServer
+	Proxy   *url.URL
+	hidden  string
+}
+
+func TestMerge(t *testing.T) {
+	no, second := false, time.Second
+	dst := &mergeConfig{
+		Name:    "base",
+		Tags:    []string{"a"},
+		Limits:  map[string]int{"cpu": 1, "mem": 2},
+		Servers: map[string]mergeServer{"a": {Host: "a", Port: 1}},
+		Main:    mergeServer{Host: "main", Port: 80},
+		Backup:  &mergeServer{Host: "backup"},
+		Proxy:   &url.URL{Scheme: "http", Host: "old"},
+		hidden:  "dst",
+	}
+	src := &mergeConfig{
+		Debug:   &no,
+		Tags:    []string{"b"},
+		Limits:  map[string]int{"mem": 0, "disk": 3},
+		Servers: map[string]mergeServer{"a": {Port: 2}, "b": {Host: "b"}},
+		Main:    mergeServer{Port: 8080, Timeout: &second},
+		Backup:  &mergeServer{Port: 81},
+		Proxy:   &url.URL{Scheme: "https", Host: "new"},
+		hidden:  "src",
+	}
+	require.NoError(t, structflag.Merge(dst, src))
+	assert.Equal(t, &mergeConfig{
+		Name:    "base",
+		Debug:   &no,
+		Tags:    []string{"b"},
+		Limits:  map[string]int{"cpu": 1, "mem": 0, "disk": 3},
+		Servers: map[string]mergeServer{"a": {Host: "a", Port: 2}, "b": {Host: "b"}},
+		Main:    mergeServer{Host: "main", Port: 8080, Timeout: &second},
+		Backup:  &mergeServer{Host: "backup", Port: 81},
+		Proxy:   &url.URL{Scheme: "https", Host: "new"},
+		hidden:  "dst",
+	}, dst)
+
+	src.Tags[0] = "changed"
+	*src.Main.Timeout = time.Minute
+	assert.Equal(t, []string{"b"}, dst.Tags)
+	assert.Equal(t, time.Second, *dst.Main.Timeout)
+}
+
+func TestMergerOptions(t *testing.T) {
+	merger := structflag.NewMerger()
+	merger.AppendSlices = true
+	merger.DeepMergeMaps = false
+	dst := &mergeConfig{Name: "base", Tags: []string{"a"}, Limits: map[string]int{"cpu": 1}}
+	require.NoError(t, merger.Merge(dst, &mergeConfig{Tags: []string{"b"}, Limits: map[string]int{"mem": 2}}))
+	assert.Equal(t, &mergeConfig{Name: "base", Tags: []string{"a", "b"}, Limits: map[string]int{"mem": 2}}, dst)
+
+	merger = structflag.NewMerger()
+	merger.OverwriteZero = true
+	require.NoError(t, merger.Merge(dst, &mergeConfig{Tags: []string{"c"}}))
+	assert.Equal(t, &mergeConfig{Tags: []string{"c"}}, dst)
+}
+
+func TestMergeErrors(t *testing.T) {
+	assert.EqualError(t, structflag.Merge(mergeConfig{}, mergeConfig{}), "merge destination must be a non-nil pointer, got structflag_test.mergeConfig")
+	assert.EqualError(t, structflag.Merge(&mergeConfig{}, &mergeServer{}), "can not merge *structflag_test.mergeServer into *structflag_test.mergeConfig")
+	assert.NoError(t, structflag.Merge(&mergeConfig{}, (*mergeConfig)(nil)))
+}
+
+func TestMergeTags(t *testing.T) {
+	type tagged struct {
+		Hosts  []string          `merge:"append"`
+		Labels map[string]string `merge:"replace"`
+		Extra  map[string]map[string]int
+		Deep   map[string]map[string]int `merge:"deep"`
+		Main   mergeServer               `merge:"replace"`
+	}
+	merger := structflag.NewMerger()
+	merger.DeepMergeMaps = false
+	dst := &tagged{
+		Hosts:  []string{"a"},
+		Labels: map[string]string{"x": "1"},
+		Extra:  map[string]map[string]int{"a": {"x": 1}},
+		Deep:   map[string]map[string]int{"a": {"x": 1}},
+		Main:   mergeServer{Host: "main", Port: 80},
+	}
+	require.NoError(t, merger.Merge(dst, &tagged{
+		Hosts:  []string{"b"},
+		Labels: map[string]string{"y": "2"},
+		Extra:  map[string]map[string]int{"a": {"y": 2}},
+		Deep:   map[string]map[string]int{"a": {"y": 2}},
+		Main:   mergeServer{Port: 8080},
+	}))
+	assert.Equal(t, &tagged{
+		Hosts:  []string{"a", "b"},
+		Labels: map[string]string{"y": "2"},
+		Extra:  map[string]map[string]int{"a": {"y": 2}},
+		Deep:   map[string]map[string]int{"a": {"x": 1, "y": 2}},
+		Main:   mergeServer{Port: 8080},
+	}, dst)
+}
+
+func TestMergeTextStructs(t *testing.T) {
+	type config struct {
+		Start time.Time
+		End   *time.Time
+		Link  url.URL
+	}
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+	link, err := url.Parse("https://example.com/a")
+	require.NoError(t, err)
+	dst := &config{End: &time.Time{}}
+	require.NoError(t, structflag.Merge(dst, &config{Start: start, End: &end, Link: *link}))
+	assert.Equal(t, start, dst.Start)
+	assert.Equal(t, end, *dst.End)
+	assert.Equal(t, "https://example.com/a", dst.Link.String())
+
+	require.NoError(t, structflag.Merge(dst, &config{}))
+	assert.Equal(t, start, dst.Start)
+}
@@ -0,0 +1,150 @@
+package structflag
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Messages is a catalog of the text written by Parser and HelpPrinter in usage
+// output, warnings and command line errors, so that programs can show them in
+// other languages. The messages are fmt format strings with the arguments
+// listed in the comments, use explicit argument indexes like %[2]s to change
+// their order. Use NewMessages to create a catalog with English messages and
+// change the fields to translate them.
+type Messages struct {
+	// Usage is the first line of usage output. Arguments: program name.
+	Usage string
+	// Group is the header of the section listing flags in a group. Arguments:
+	// group name.
+	Group string
+	// Default describes the default value. Arguments: default value.
+	Default string
+	// Truncated replaces long JSON defaults. Arguments: "{...}" or "[...]",
+	// length of the default.
+	Truncated string
+	// Example describes the example value. Arguments: example value.
+	Example string
+	// Deprecated marks deprecated flags without a message.
+	Deprecated string
+	// DeprecatedMessage marks deprecated flags. Arguments: deprecation message.
+	DeprecatedMessage string
+	// Required marks required flags in HelpPrinter output.
+	Required string
+	// HelpFlag is the description of the help flag.
+	HelpFlag string
+	// HelpFullFlag is the description of the flag showing help with complete
+	// defaults.
+	HelpFullFlag string
+	// VersionFlag is the description of the version flag.
+	VersionFlag string
+	// ProfileFlag is the description of the profile flag.
+	ProfileFlag string
+	// ConfigFlag is the description of the configuration file flag.
+	ConfigFlag string
+	// Warning prefixes warnings. Arguments: warning.
+	Warning string
+	// RenamedFlag warns about use of a previous flag name. Arguments: previous
+	// name, current name.
+	RenamedFlag string
+	// RenamedEnv warns about use of a previous environment variable name.
+	// Arguments: previous name, current name.
+	RenamedEnv string
+	// Removed reports use of a removed flag. Arguments: flag name.
+	Removed string
+	// RemovedIn reports use of a flag removed in a version. Arguments: flag
+	// name, version.
+	RemovedIn string
+	// UnknownFlag reports a flag which is not defined. Arguments: flag with
+	// dashes.
+	UnknownFlag string
+	// UnknownFlagInGroup reports a letter in a group of single letter flags
+	// which is not defined. Arguments: letter, group.
+	UnknownFlagInGroup string
+	// MissingArgument reports a flag without a value. Arguments: flag with
+	// dashes.
+	MissingArgument string
+	// InvalidValue reports a value which can not be set. Arguments: input,
+	// flag, error.
+	InvalidValue string
+	// DidYouMean suggests similar flag names after an error. Arguments: quoted
+	// names joined with Or.
+	DidYouMean string
+	// Or joins the last two suggested names.
+	Or string
+	// Requires reports a flag used without a flag it requires. Arguments: flag
+	// name, required flag name.
+	Requires string
+	// RequiresUnknown reports a requires tag listing an undefined flag.
+	// Arguments: flag name, required flag name.
+	RequiresUnknown string
+	// Conflicts reports flags which can not be used together. Arguments: flag
+	// name, conflicting flag name.
+	Conflicts string
+	// ConflictsUnknown reports a conflicts tag listing an undefined flag.
+	// Arguments: flag name, conflicting flag name.
+	ConflictsUnknown string
+}
+
+// NewMessages returns a catalog with English messages. The returned instance
+// can be customized by changing fields.
+func NewMessages() *Messages {
+	return &Messages{
+		Usage:              "Usage of %s:",
+		Group:              "%s flags:",
+		Default:            "(default %s)",
+		Truncated:          "%s (%d characters)",
+		Example:            "(example: %s)",
+		Deprecated:         "(deprecated)",
+		DeprecatedMessage:  "(deprecated: %s)",
+		Required:           "(required)",
+		HelpFlag:           "Show this help and exit",
+		HelpFullFlag:       "Show this help with complete default values and exit",
+		VersionFlag:        "Show version and exit",
+		ProfileFlag:        "Profile providing default values",
+		ConfigFlag:         "Configuration file",
+		Warning:            "warning: %s",
+		RenamedFlag:        "flag -%s is deprecated, use -%s",
+		RenamedEnv:         "environment variable %s is deprecated, use %s",
+		Removed:            "flag -%s was removed",
+		RemovedIn:          "flag -%s was removed in %s",
+		UnknownFlag:        "flag provided but not defined: %s",
+		UnknownFlagInGroup: "flag provided but not defined: -%s in -%s",
+		MissingArgument:    "flag needs an argument: %s",
+		InvalidValue:       "invalid value %q for flag %s: %s",
+		DidYouMean:         ", did you mean %s?",
+		Or:                 " or ",
+		Requires:           "flag -%s requires flag -%s",
+		RequiresUnknown:    "flag -%s requires unknown flag -%s",
+		Conflicts:          "flag -%s conflicts with flag -%s",
+		ConflictsUnknown:   "flag -%s conflicts with unknown flag -%s",
+	}
+}
+
+// DefaultMessages is the catalog used by package level functions and by
+// parsers and help printers without their own catalog.
+var DefaultMessages = NewMessages()
+
+// messagesOrDefault returns messages or DefaultMessages if it is nil.
+func messagesOrDefault(messages *Messages) *Messages {
+	if messages == nil {
+		return DefaultMessages
+	}
+	return messages
+}
+
+// didYouMean formats the suggestions as a suffix of an error message, e.g.
+// `, did you mean "Port"?`. It returns empty string if there are none.
+func (thiz *Messages) didYouMean(matches []string) string {
+	if len(matches) == 0 {
+		return ""
+	}
+	quoted := make([]string, len(matches))
+	for i, match := range matches {
+		quoted[i] = fmt.Sprintf("%q", match)
+	}
+	names := quoted[0]
+	if len(quoted) > 1 {
+		names = strings.Join(quoted[:len(quoted)-1], ", ") + thiz.Or + quoted[len(quoted)-1]
+	}
+	return fmt.Sprintf(thiz.DidYouMean, names)
+}
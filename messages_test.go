@@ -0,0 +1,89 @@
+package structflag_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/surajbarkale/structflag"
+)
+
+func germanMessages() *structflag.Messages {
+	messages := structflag.NewMessages()
+	messages.Usage = "Aufruf von %s:"
+	messages.Default = "(Standard: %s)"
+	messages.Required = "(erforderlich)"
+	messages.DeprecatedMessage = "(veraltet: %s)"
+	messages.HelpFlag = "Diese Hilfe anzeigen"
+	messages.Warning = "Warnung: %s"
+	messages.RenamedFlag = "Option -%s ist veraltet, verwenden Sie -%s"
+	messages.UnknownFlag = "unbekannte Option: %s"
+	messages.MissingArgument = "Option braucht ein Argument: %s"
+	messages.InvalidValue = "ungültiger Wert %[1]q für Option %[2]s: %[3]s"
+	messages.DidYouMean = ", meinten Sie %s?"
+	messages.Or = " oder "
+	messages.Requires = "Option -%s benötigt Option -%s"
+	return messages
+}
+
+type messagesOptions struct {
+	Port  int    `description:"Port" required:"true"`
+	Host  string `description:"Host" wasNamed:"Server" deprecated:"use -Addr"`
+	Hosts string
+	Tls   bool `requires:"Cert"`
+	Cert  string
+}
+
+func TestMessagesUsage(t *testing.T) {
+	var out bytes.Buffer
+	parser := newTestParser(&out)
+	parser.Messages = germanMessages()
+	require.NoError(t, parser.PrintUsage(&out, &messagesOptions{Port: 80}))
+	assert.Equal(t, `Aufruf von test:
+  -Cert string
+  -Host string
+    	Host
+  -Hosts string
+  -Port int
+    	Port (Standard: 80)
+  -Tls
+  -help
+    	Diese Hilfe anzeigen
+`, out.String())
+
+	out.Reset()
+	printer := structflag.NewHelpPrinter()
+	printer.Width = 80
+	printer.RequiredTag = "required"
+	printer.Messages = parser.Messages
+	parser.Usage = printer.PrintDefaults
+	require.NoError(t, parser.PrintUsage(&out, &messagesOptions{Port: 80}))
+	assert.Equal(t, `  -Cert string
+  -Host string   Host (veraltet: use -Addr)
+  -Hosts string
+  -Port int      Port (Standard: 80) (erforderlich)
+  -Tls
+  -help, -h      Diese Hilfe anzeigen
+`, out.String())
+}
+
+func TestMessagesErrors(t *testing.T) {
+	var out bytes.Buffer
+	parser := newTestParser(&out)
+	parser.Usage = func(w io.Writer, values map[string]structflag.Value) {}
+	parser.Messages = germanMessages()
+	parse := func(args ...string) error {
+		_, err := parser.Parse(&messagesOptions{}, args)
+		return err
+	}
+	assert.EqualError(t, parse("--Hostz"), `unbekannte Option: --Hostz, meinten Sie "--Host" oder "--Hosts"?`)
+	assert.EqualError(t, parse("--Port"), "Option braucht ein Argument: --Port")
+	assert.EqualError(t, parse("--Port", "x"), `ungültiger Wert "x" für Option --Port: strconv.ParseInt: parsing "x": invalid syntax, expected integer`)
+	assert.EqualError(t, parse("--Tls"), "Option -Tls benötigt Option -Cert")
+
+	require.NoError(t, parse("--Server", "a"))
+	assert.Equal(t, "Warnung: Option -Server ist veraltet, verwenden Sie -Host\n", out.String())
+}
@@ -0,0 +1,27 @@
+package structflag
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// NameCollisionFunc resolves a flag name which is already used by another field.
+// The function receives the conflicting name and a function reporting whether a
+// name is already in use. It returns the name to use for the field or an error.
+type NameCollisionFunc func(name string, used func(string) bool) (string, error)
+
+// ErrorOnNameCollision rejects duplicate flag names.
+func ErrorOnNameCollision(name string, used func(string) bool) (string, error) {
+	return "", fmt.Errorf("flag name %q is used by more than one field", name)
+}
+
+// SuffixOnNameCollision makes duplicate flag names unique by appending the
+// smallest number starting from 2 which results in an unused name.
+func SuffixOnNameCollision(name string, used func(string) bool) (string, error) {
+	for i := 2; ; i++ {
+		res := name + strconv.Itoa(i)
+		if !used(res) {
+			return res, nil
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package structflag_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/surajbarkale/structflag"
+)
+
+type collision struct {
+	Name   string
+	NAME   string
+	NAME2  string
+	Nested struct {
+		Value int
+	}
+	NestedValue int
+}
+
+func TestNameCollisionIsError(t *testing.T) {
+	c := structflag.NewStructToFlagsConverter()
+	c.NameConverterFunc = strings.ToUpper
+	_, err := c.Convert(&collision{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `"NAME"`)
+
+	c = structflag.NewStructToFlagsConverter()
+	c.WordSeparator = ""
+	_, err = c.Convert(&collision{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `"NestedValue"`)
+}
+
+func TestNameCollisionSuffix(t *testing.T) {
+	val := &collision{}
+	c := structflag.NewStructToFlagsConverter()
+	c.NameConverterFunc = strings.ToUpper
+	c.OnNameCollision = structflag.SuffixOnNameCollision
+	sv, err := c.Convert(val)
+	require.NoError(t, err)
+	require.NoError(t, sv["NAME"].Set("a"))
+	require.NoError(t, sv["NAME2"].Set("b"))
+	require.NoError(t, sv["NAME22"].Set("c"))
+	assert.Equal(t, "a", val.Name)
+	assert.Equal(t, "b", val.NAME)
+	assert.Equal(t, "c", val.NAME2)
+}
+
+func TestNameCollisionCallback(t *testing.T) {
+	c := structflag.NewStructToFlagsConverter()
+	c.WordSeparator = ""
+	c.OnNameCollision = func(name string, used func(string) bool) (string, error) {
+		assert.True(t, used(name))
+		return fmt.Sprintf("%s-dup", name), nil
+	}
+	sv, err := c.Convert(&collision{})
+	require.NoError(t, err)
+	assert.Contains(t, sv, "NestedValue")
+	assert.Contains(t, sv, "NestedValue-dup")
+
+	c.OnNameCollision = func(name string, used func(string) bool) (string, error) {
+		return "Name", nil
+	}
+	_, err = c.Convert(&collision{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,157 @@
+package structflag
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"reflect"
+	"strings"
+)
+
+var (
+	ipType    = reflect.TypeOf(net.IP(nil))
+	ipNetType = reflect.TypeOf(net.IPNet{})
+	macType   = reflect.TypeOf(net.HardwareAddr(nil))
+)
+
+// ipCodec converts net.IP values from their text form.
+type ipCodec struct{}
+
+// ipNetCodec converts net.IPNet values from CIDR notation.
+type ipNetCodec struct{}
+
+// macCodec converts net.HardwareAddr values.
+type macCodec struct{}
+
+// netListCodec converts slices of IP addresses and networks from comma
+// separated lists or JSON arrays of strings.
+type netListCodec struct{}
+
+func init() {
+	RegisterCodec(ipCodec{})
+	RegisterCodec(ipNetCodec{})
+	RegisterCodec(macCodec{})
+	RegisterCodec(netListCodec{})
+}
+
+// Match returns true for net.IP.
+func (ipCodec) Match(t reflect.Type) bool {
+	return t == ipType
+}
+
+// Decode parses IPv4 or IPv6 address. Empty string is decoded as nil.
+func (ipCodec) Decode(s string, val reflect.Value) error {
+	if s == "" {
+		val.Set(reflect.Zero(ipType))
+		return nil
+	}
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return fmt.Errorf("invalid IP address %q", s)
+	}
+	val.Set(reflect.ValueOf(ip))
+	return nil
+}
+
+// Encode returns the address or empty string for nil.
+func (ipCodec) Encode(val reflect.Value) (string, error) {
+	ip := val.Interface().(net.IP)
+	if ip == nil {
+		return "", nil
+	}
+	return ip.String(), nil
+}
+
+// Match returns true for net.IPNet.
+func (ipNetCodec) Match(t reflect.Type) bool {
+	return t == ipNetType
+}
+
+// Decode parses network in CIDR notation, e.g. "10.0.0.0/8". Host bits of the
+// address are cleared.
+func (ipNetCodec) Decode(s string, val reflect.Value) error {
+	_, ipNet, err := net.ParseCIDR(s)
+	if err != nil {
+		return err
+	}
+	val.Set(reflect.ValueOf(*ipNet))
+	return nil
+}
+
+// Encode returns the network in CIDR notation.
+func (ipNetCodec) Encode(val reflect.Value) (string, error) {
+	ipNet := val.Interface().(net.IPNet)
+	if ipNet.IP == nil {
+		return "", nil
+	}
+	return ipNet.String(), nil
+}
+
+// Match returns true for net.HardwareAddr.
+func (macCodec) Match(t reflect.Type) bool {
+	return t == macType
+}
+
+// Decode parses the address in colon, dash or dot separated form, e.g.
+// "00:00:5e:00:53:01", "00-00-5E-00-53-01" or "0000.5e00.5301". Empty string
+// is decoded as nil.
+func (macCodec) Decode(s string, val reflect.Value) error {
+	if s == "" {
+		val.Set(reflect.Zero(macType))
+		return nil
+	}
+	mac, err := net.ParseMAC(s)
+	if err != nil {
+		return err
+	}
+	val.Set(reflect.ValueOf(mac))
+	return nil
+}
+
+// Encode returns the address in colon separated form.
+func (macCodec) Encode(val reflect.Value) (string, error) {
+	return val.Interface().(net.HardwareAddr).String(), nil
+}
+
+// Match returns true for slices of net.IP, net.IPNet and *net.IPNet.
+func (netListCodec) Match(t reflect.Type) bool {
+	if t.Kind() != reflect.Slice {
+		return false
+	}
+	elem := t.Elem()
+	return elem == ipType || elem == ipNetType || (elem.Kind() == reflect.Ptr && elem.Elem() == ipNetType)
+}
+
+// Decode parses the items. Empty string is decoded as nil slice.
+func (netListCodec) Decode(s string, val reflect.Value) error {
+	var items []string
+	if strings.HasPrefix(strings.TrimSpace(s), "[") {
+		if err := json.Unmarshal([]byte(s), &items); err != nil {
+			return err
+		}
+	} else {
+		items = splitList(s)
+	}
+	res := reflect.MakeSlice(val.Type(), 0, len(items))
+	for _, item := range items {
+		elem := reflect.New(val.Type().Elem()).Elem()
+		if err := decodeString(item, elem); err != nil {
+			return err
+		}
+		res = reflect.Append(res, elem)
+	}
+	if len(items) == 0 {
+		res = reflect.Zero(val.Type())
+	}
+	val.Set(res)
+	return nil
+}
+
+// Encode returns comma separated items.
+func (netListCodec) Encode(val reflect.Value) (string, error) {
+	items := make([]string, val.Len())
+	for i := range items {
+		items[i] = encodeString(val.Index(i))
+	}
+	return strings.Join(items, ","), nil
+}
@@ -0,0 +1,70 @@
+package structflag_test
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/surajbarkale/structflag"
+)
+
+func TestNetValues(t *testing.T) {
+	val := &struct {
+		Bind    net.IP
+		Gateway *net.IP
+		Subnet  net.IPNet
+		Allow   []net.IPNet
+		Deny    []*net.IPNet
+		Peers   []net.IP
+	}{Bind: net.IPv4(127, 0, 0, 1)}
+	values, err := structflag.DefaultStructToFlagsConverter.Convert(val)
+	require.NoError(t, err)
+	assert.Equal(t, "127.0.0.1", values["Bind"].Default())
+	assert.Equal(t, "", values["Allow"].Default())
+
+	require.NoError(t, values["Bind"].Set("::1"))
+	assert.Equal(t, net.IPv6loopback, val.Bind)
+	assert.EqualError(t, values["Bind"].Set("1.2.3"), `invalid value "1.2.3" for flag Bind: invalid IP address "1.2.3"`)
+	require.NoError(t, values["Gateway"].Set("10.0.0.1"))
+	assert.Equal(t, "10.0.0.1", val.Gateway.String())
+
+	require.NoError(t, values["Subnet"].Set("192.168.1.7/24"))
+	assert.Equal(t, "192.168.1.0/24", values["Subnet"].String())
+	assert.Error(t, values["Subnet"].Set("192.168.1.7"))
+
+	require.NoError(t, values["Allow"].Set("10.0.0.0/8, fd00::/8"))
+	require.Len(t, val.Allow, 2)
+	assert.Equal(t, "fd00::/8", val.Allow[1].String())
+	assert.Equal(t, "10.0.0.0/8,fd00::/8", values["Allow"].String())
+	assert.Error(t, values["Allow"].Set("10.0.0.0/8,bad"))
+	require.NoError(t, values["Allow"].Set(""))
+	assert.Nil(t, val.Allow)
+
+	require.NoError(t, values["Deny"].Set(`["172.16.0.0/12"]`))
+	require.Len(t, val.Deny, 1)
+	assert.Equal(t, "172.16.0.0/12", val.Deny[0].String())
+
+	require.NoError(t, values["Peers"].Set("1.1.1.1,8.8.8.8"))
+	assert.Equal(t, "1.1.1.1,8.8.8.8", values["Peers"].String())
+}
+
+func TestHardwareAddrValues(t *testing.T) {
+	val := &struct {
+		MAC      net.HardwareAddr
+		Fallback *net.HardwareAddr
+	}{}
+	values, err := structflag.DefaultStructToFlagsConverter.Convert(val)
+	require.NoError(t, err)
+	assert.Equal(t, "", values["MAC"].String())
+
+	require.NoError(t, values["MAC"].Set("00-00-5E-00-53-01"))
+	assert.Equal(t, net.HardwareAddr{0, 0, 0x5e, 0, 0x53, 1}, val.MAC)
+	assert.Equal(t, "00:00:5e:00:53:01", values["MAC"].String())
+	require.NoError(t, values["MAC"].Set("0000.5e00.5302"))
+	assert.Equal(t, "00:00:5e:00:53:02", values["MAC"].String())
+	assert.Error(t, values["MAC"].Set("00:00:5e"))
+	require.NoError(t, values["Fallback"].Set("02:00:00:00:00:01"))
+	assert.Equal(t, "02:00:00:00:00:01", val.Fallback.String())
+}
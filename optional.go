@@ -0,0 +1,85 @@
+package structflag
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// isOptional returns true if the field is marked with OptionalTag.
+func (thiz *StructToFlagsConverter) isOptional(field reflect.StructField) bool {
+	if thiz.OptionalTag == "" {
+		return false
+	}
+	optional, _ := strconv.ParseBool(field.Tag.Get(thiz.OptionalTag))
+	return optional
+}
+
+// attachFunc returns a function storing ptr in field if it is still nil. The
+// parent struct is attached first.
+func attachFunc(field, ptr reflect.Value, parent func()) func() {
+	return func() {
+		if parent != nil {
+			parent()
+		}
+		if field.IsNil() {
+			field.Set(ptr)
+		}
+	}
+}
+
+// IsSet returns true if the field at path has a value. The path is a flag name
+// generated by Convert or the path of a nested struct, e.g. "Server-TLS",
+// without the Prefix. Pointer, map, slice and interface fields are set if they
+// are not nil, so a nil *bool is unset while a pointer to false is set. Other
+// fields are set if they are not the zero value. False is returned if the path
+// does not exist or goes through a nil pointer. The input is not modified.
+func (thiz *StructToFlagsConverter) IsSet(input interface{}, path string) bool {
+	field, ok := thiz.findField("", reflect.ValueOf(input), path)
+	if !ok {
+		return false
+	}
+	if isNullable(field.Kind()) {
+		return !field.IsNil()
+	}
+	return !isEmpty(field)
+}
+
+// findField returns the field at path without initializing nil pointers.
+func (thiz *StructToFlagsConverter) findField(prefix string, input reflect.Value, path string) (reflect.Value, bool) {
+	for input.Kind() == reflect.Ptr || input.Kind() == reflect.Interface {
+		if input.IsNil() {
+			return reflect.Value{}, false
+		}
+		input = input.Elem()
+	}
+	if input.Kind() != reflect.Struct {
+		return reflect.Value{}, false
+	}
+	inputType := input.Type()
+	for i := 0; i < input.NumField(); i++ {
+		field := input.Field(i)
+		if !field.CanSet() {
+			continue
+		}
+		fieldName, ok := thiz.fieldName(inputType.Field(i))
+		if !ok {
+			continue
+		}
+		fieldPath := prefix + thiz.NameConverterFunc(fieldName)
+		if fieldPath == path {
+			return field, true
+		}
+		if isStructField(field) && strings.HasPrefix(path, fieldPath+thiz.WordSeparator) {
+			if res, ok := thiz.findField(fieldPath+thiz.WordSeparator, field, path); ok {
+				return res, true
+			}
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// IsSet checks the field at path using DefaultStructToFlagsConverter.
+func IsSet(input interface{}, path string) bool {
+	return DefaultStructToFlagsConverter.IsSet(input, path)
+}
@@ -0,0 +1,70 @@
+package structflag_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/surajbarkale/structflag"
+)
+
+type tlsOptions struct {
+	Cert   string
+	Client *struct {
+		CA string
+	} `optional:"true"`
+}
+
+type optionalOptions struct {
+	Verbose *bool
+	Count   int
+	TLS     *tlsOptions `optional:"true"`
+	Nested  *tlsOptions
+}
+
+func TestOptionalStructStaysNil(t *testing.T) {
+	val := &optionalOptions{}
+	values, err := structflag.DefaultStructToFlagsConverter.Convert(val)
+	require.NoError(t, err)
+	assert.Contains(t, values, "TLS-Cert")
+	assert.Contains(t, values, "TLS-Client-CA")
+	assert.Nil(t, val.TLS)
+	assert.NotNil(t, val.Nested)
+	assert.False(t, structflag.IsSet(val, "TLS"))
+	assert.True(t, structflag.IsSet(val, "Nested"))
+
+	require.NoError(t, values["TLS-Client-CA"].Set("ca.pem"))
+	require.NotNil(t, val.TLS)
+	require.NotNil(t, val.TLS.Client)
+	assert.Equal(t, "ca.pem", val.TLS.Client.CA)
+	assert.True(t, structflag.IsSet(val, "TLS-Client"))
+	assert.False(t, structflag.IsSet(val, "TLS-Cert"))
+
+	require.NoError(t, values["TLS-Cert"].Set("cert.pem"))
+	assert.Equal(t, "cert.pem", val.TLS.Cert)
+	assert.Equal(t, "ca.pem", val.TLS.Client.CA)
+}
+
+func TestOptionalStructIsNotReplaced(t *testing.T) {
+	val := &optionalOptions{TLS: &tlsOptions{Cert: "a"}}
+	values, err := structflag.DefaultStructToFlagsConverter.Convert(val)
+	require.NoError(t, err)
+	tls := val.TLS
+	require.NoError(t, values["TLS-Cert"].Set("b"))
+	assert.True(t, tls == val.TLS)
+	assert.Equal(t, "b", val.TLS.Cert)
+}
+
+func TestIsSetScalars(t *testing.T) {
+	val := &optionalOptions{}
+	values, err := structflag.DefaultStructToFlagsConverter.Convert(val)
+	require.NoError(t, err)
+	assert.False(t, structflag.IsSet(val, "Verbose"))
+	assert.False(t, structflag.IsSet(val, "Count"))
+	assert.False(t, structflag.IsSet(val, "Missing"))
+	require.NoError(t, values["Verbose"].Set("false"))
+	require.NoError(t, values["Count"].Set("1"))
+	assert.True(t, structflag.IsSet(val, "Verbose"))
+	assert.True(t, structflag.IsSet(val, "Count"))
+}
@@ -0,0 +1,81 @@
+/*
+Package otelflag converts flag values into OpenTelemetry attributes, so that
+traces carry the effective configuration of the service emitting them:
+
+	values, err := structflag.DefaultStructToFlagsConverter.Convert(&config)
+	if err != nil {
+		return err
+	}
+	res, err := resource.Merge(resource.Default(), otelflag.Resource(values, "config.", "Region", "Server-Port"))
+	...
+	span.SetAttributes(otelflag.Attributes(values, "config.", "LogLevel")...)
+
+Secret values are never converted.
+*/
+package otelflag
+
+import (
+	"reflect"
+	"sort"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+
+	"github.com/surajbarkale/structflag"
+)
+
+// Attributes returns the values with given names as attributes with keys
+// consisting of prefix followed by the flag name. All values are converted if
+// no names are given. Secret values and names which do not match any value are
+// skipped. Booleans, integers, floats and strings keep their types and other
+// values are converted to strings.
+func Attributes(values map[string]structflag.Value, prefix string, names ...string) []attribute.KeyValue {
+	if len(names) == 0 {
+		for name := range values {
+			names = append(names, name)
+		}
+	}
+	sorted := append([]string{}, names...)
+	sort.Strings(sorted)
+	var res []attribute.KeyValue
+	for _, name := range sorted {
+		value, ok := values[name]
+		if !ok || value.IsSecret() {
+			continue
+		}
+		res = append(res, attributeOf(prefix+name, value))
+	}
+	return res
+}
+
+// Resource returns a resource with the attributes of the values selected in
+// the same way as in Attributes.
+func Resource(values map[string]structflag.Value, prefix string, names ...string) *resource.Resource {
+	return resource.NewSchemaless(Attributes(values, prefix, names...)...)
+}
+
+// attributeOf converts the value into an attribute with given key.
+func attributeOf(key string, value structflag.Value) attribute.KeyValue {
+	val := reflect.ValueOf(value.Get())
+	for val.Kind() == reflect.Ptr && !val.IsNil() {
+		val = val.Elem()
+	}
+	k := attribute.Key(key)
+	if !val.IsValid() || val.Type().PkgPath() != "" {
+		// Named types like time.Duration have their own string format
+		return k.String(value.String())
+	}
+	switch val.Kind() {
+	case reflect.Bool:
+		return k.Bool(val.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return k.Int64(val.Int())
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return k.Int64(int64(val.Uint()))
+	case reflect.Float32, reflect.Float64:
+		return k.Float64(val.Float())
+	case reflect.String:
+		return k.String(val.String())
+	}
+	return k.String(value.String())
+}
@@ -0,0 +1,50 @@
+package otelflag_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/surajbarkale/structflag"
+	"github.com/surajbarkale/structflag/otelflag"
+)
+
+type serviceConfig struct {
+	Region   string
+	Debug    bool
+	Workers  int
+	Ratio    float64
+	Timeout  time.Duration
+	Tags     []string
+	Password string `secret:"true"`
+	Server   struct {
+		Port *int
+	}
+}
+
+func TestAttributes(t *testing.T) {
+	port := 8080
+	val := &serviceConfig{Region: "eu", Debug: true, Workers: 4, Ratio: 0.5, Timeout: time.Minute, Tags: []string{"a"}, Password: "x"}
+	val.Server.Port = &port
+	values, err := structflag.DefaultStructToFlagsConverter.Convert(val)
+	require.NoError(t, err)
+
+	assert.Equal(t, []attribute.KeyValue{
+		attribute.Bool("config.Debug", true),
+		attribute.Float64("config.Ratio", 0.5),
+		attribute.String("config.Region", "eu"),
+		attribute.Int64("config.Server-Port", 8080),
+		attribute.String("config.Tags", `["a"]`),
+		attribute.String("config.Timeout", "1m0s"),
+		attribute.Int64("config.Workers", 4),
+	}, otelflag.Attributes(values, "config."))
+	assert.Equal(t, []attribute.KeyValue{
+		attribute.String("Region", "eu"),
+	}, otelflag.Attributes(values, "", "Region", "Password", "Missing"))
+
+	res := otelflag.Resource(values, "config.", "Workers")
+	assert.Equal(t, []attribute.KeyValue{attribute.Int64("config.Workers", 4)}, res.Attributes())
+}
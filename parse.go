@@ -0,0 +1,465 @@
+package structflag
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+)
+
+// ErrVersion is returned by Parser.Parse when the version flag is given and the
+// parser does not exit.
+var ErrVersion = errors.New("structflag: version requested")
+
+// Parser binds fields of a struct to command line flags and parses arguments
+// into them. The zero value is not usable, use NewParser to create a parser.
+type Parser struct {
+	// Converter is used to generate flag values from the struct.
+	Converter *StructToFlagsConverter
+	// Name is the program name shown in usage output.
+	Name string
+	// Output receives usage, version and error messages.
+	Output io.Writer
+	// ErrorHandling defines how parsing errors, help and version requests are
+	// handled. It has the same meaning as for flag.FlagSet.
+	ErrorHandling flag.ErrorHandling
+	// HelpFlag is the name of the flag printing usage. Empty string disables it.
+	HelpFlag string
+	// HelpFullFlag is the name of the flag printing usage with FullUsage, e.g.
+	// "help-full". Empty string disables it.
+	HelpFullFlag string
+	// Version is printed when the version flag is given.
+	Version string
+	// VersionFlag is the name of the flag printing version. The flag is only
+	// added if Version is not empty.
+	VersionFlag string
+	// ArgsEnv is the name of an environment variable containing arguments which
+	// are added before the command line arguments, e.g. "MYAPP_OPTS". The value
+	// is split into arguments using shell quoting rules.
+	ArgsEnv string
+	// LookupEnv returns the value of an environment variable.
+	LookupEnv func(key string) (string, bool)
+	// ResponseFiles enables replacing arguments of the form @file with the
+	// arguments read from the file. Each line of the file is a single argument,
+	// empty lines and lines starting with # are ignored.
+	ResponseFiles bool
+	// FS is used to read response files instead of the operating system if it
+	// is not nil. Configuration files are read using ConfigLoader.FS.
+	FS fs.FS
+	// ProfileFlag is the name of the flag selecting a profile. Profiles provide
+	// alternative defaults for values which are not set from any other source.
+	// Empty string disables the flag.
+	ProfileFlag string
+	// Profile is the profile used when the profile flag is not given.
+	Profile string
+	// ProfileTagPrefix is prepended to the profile name to get the struct tag
+	// containing default value for the profile, e.g. `default_prod:"..."`.
+	ProfileTagPrefix string
+	// Profiles contains default values for each profile keyed by flag name.
+	// They take precedence over the values from struct tags.
+	Profiles map[string]map[string]string
+	// DerivedDefaults contains functions computing defaults keyed by flag name,
+	// e.g. to derive a metrics address from the listen address. The functions
+	// are called after all other sources are applied, only for values which were
+	// not set by any source. They receive the values bound to the fields and do
+	// not see the results of other derived defaults.
+	DerivedDefaults map[string]func(values map[string]Value) (string, error)
+	// ConfigLoader loads configuration files if it is not nil. Values from the
+	// file are applied to the values which were not set by flags.
+	ConfigLoader *ConfigLoader
+	// ConfigFlag is the name of the flag giving the path of the configuration
+	// file. Empty string disables the flag.
+	ConfigFlag string
+	// ConfigNames are the file names searched using FindConfigFile with Name as
+	// the application name when the config flag is not given. Missing files are
+	// ignored. Empty list disables the search.
+	ConfigNames []string
+	// Env sets values from environment variables if it is not nil. It is
+	// applied before configuration files to the values which were not set by
+	// flags. Previous names of variables are reported to Output unless the
+	// loader has OnDeprecated.
+	Env *EnvLoader
+	// DownwardAPI sets values from Kubernetes pod metadata if it is not nil. It
+	// is applied before profiles to the values which were not set by flags.
+	DownwardAPI *DownwardAPI
+	// Resolvers replace string values containing URIs with the values fetched
+	// by the resolver of the URI scheme after all other sources are applied,
+	// e.g. {"vault": vaultResolver} for "vault://secret/db#password".
+	Resolvers map[string]Resolver
+	// Expand replaces text/template actions in string values with the values of
+	// other flags using Expand after all other sources are applied, e.g.
+	// "{{.DataDir}}/cache".
+	Expand bool
+	// RequiresTag is used to query struct tag to get comma separated list of
+	// flags which must have a value if the field has a value, e.g.
+	// `requires:"TLSKey"`.
+	RequiresTag string
+	// ConflictsTag is used to query struct tag to get comma separated list of
+	// flags which must not have a value if the field has a value, e.g.
+	// `conflicts:"Insecure"`.
+	ConflictsTag string
+	// Groups limits the fields bound to flags to the values in the given groups,
+	// e.g. to hide an experimental group unless requested. Empty string selects
+	// values without a group. All values are bound if it is nil.
+	Groups []string
+	// RemovedTag is used to query struct tag to get the version in which a flag
+	// was removed, e.g. `removed:"v2.0"`. The field is kept so that old command
+	// lines can still be parsed.
+	RemovedTag string
+	// RemovedPolicy defines how removed flags given on the command line are
+	// handled.
+	RemovedPolicy RemovedPolicy
+	// UnknownField is the flag name of a map[string]string field which receives
+	// flags not matching any value instead of causing an error. The keys are
+	// flag names without dashes. The field itself is not available as a flag.
+	UnknownField string
+	// PassUnknown returns flags not matching any value, in their original form,
+	// together with the positional arguments instead of causing an error.
+	PassUnknown bool
+	// Canonicalize normalizes flag names given on the command line which do not
+	// match any flag exactly, e.g. CanonicalName to accept --nested.int_ptr for
+	// -Nested-IntPtr. Names matching several flags are rejected as unknown.
+	Canonicalize func(name string) string
+	// SourcePriority lists sources from the lowest to the highest priority. A
+	// source changes values set by sources with lower priority, e.g. env can
+	// override flags in containerized deployments when SourceEnv comes after
+	// SourceFlag. Sources which are not listed only set values which were not
+	// set and their values are not changed by other sources.
+	// DefaultSourcePriority is used if it is nil.
+	SourcePriority []Source
+	// Messages translates usage output, warnings and command line errors.
+	// DefaultMessages is used if it is nil. Set the same catalog in HelpPrinter
+	// when it is used as Usage.
+	Messages *Messages
+	// Usage writes help for the given values. The values include help and
+	// version flags.
+	Usage func(w io.Writer, values map[string]Value)
+	// FullUsage writes help for HelpFullFlag, showing long defaults which are
+	// shortened by Usage. Usage is used if it is nil.
+	FullUsage func(w io.Writer, values map[string]Value)
+	// PrintVersion writes the version.
+	PrintVersion func(w io.Writer, version string)
+	// OnFlagsUsed is called with the sorted names of the flags given on the
+	// command line after they are parsed without errors, e.g. to measure which
+	// flags are used before deprecating them. Aliases and previous names are
+	// reported by the flag name. Values are not passed.
+	OnFlagsUsed func(names []string)
+	// Exit terminates the program with given status code when ErrorHandling
+	// is flag.ExitOnError.
+	Exit func(code int)
+
+	values map[string]Value
+}
+
+// NewParser returns a parser with "help" and "version" flags which uses
+// DefaultStructToFlagsConverter, writes to os.Stderr, returns errors to the
+// caller, reads profile defaults from struct tags starting with "default_" and
+// checks dependencies between flags using "requires" and "conflicts" tags and
+// warns about flags with "removed" tag. The returned instance can be customized
+// by changing fields.
+func NewParser() *Parser {
+	thiz := &Parser{
+		Converter:        DefaultStructToFlagsConverter,
+		Name:             filepath.Base(os.Args[0]),
+		Output:           os.Stderr,
+		ErrorHandling:    flag.ContinueOnError,
+		HelpFlag:         "help",
+		VersionFlag:      "version",
+		ProfileTagPrefix: "default_",
+		RequiresTag:      "requires",
+		ConflictsTag:     "conflicts",
+		RemovedTag:       "removed",
+		PrintVersion: func(w io.Writer, version string) {
+			fmt.Fprintln(w, version)
+		},
+		LookupEnv: os.LookupEnv,
+		Exit:      os.Exit,
+	}
+	thiz.Usage = func(w io.Writer, values map[string]Value) {
+		messages := messagesOrDefault(thiz.Messages)
+		fmt.Fprintf(w, messages.Usage+"\n", thiz.Name)
+		printDefaults(w, values, messages, false)
+	}
+	thiz.FullUsage = func(w io.Writer, values map[string]Value) {
+		messages := messagesOrDefault(thiz.Messages)
+		fmt.Fprintf(w, messages.Usage+"\n", thiz.Name)
+		printDefaults(w, values, messages, true)
+	}
+	return thiz
+}
+
+// Parse converts target into flags and parses args into it. You must pass a
+// pointer to the value. Flags are parsed in GNU style: they can be given with one
+// or two dashes, values can follow the flag name after "=" or as the next
+// argument and single letter boolean flags can be combined. Positional
+// arguments can be mixed with flags and all arguments after "--" are positional.
+// The positional arguments are returned.
+func (thiz *Parser) Parse(target interface{}, args []string) ([]string, error) {
+	return thiz.ParseContext(context.Background(), target, args)
+}
+
+// ParseContext is like Parse but passes ctx to the sources of values, so that
+// Resolvers respect its deadline and cancellation.
+func (thiz *Parser) ParseContext(ctx context.Context, target interface{}, args []string) ([]string, error) {
+	converted, err := thiz.Converter.Convert(target)
+	if err != nil {
+		return nil, thiz.handleError(err, nil)
+	}
+	if thiz.Groups != nil {
+		converted = FilterGroups(converted, thiz.Groups...)
+	}
+	thiz.values = converted
+	messages := messagesOrDefault(thiz.Messages)
+	// Help, version and unknown flags are handled separately from the fields
+	values := make(map[string]Value, len(converted)+2)
+	for name, value := range converted {
+		values[name] = value
+	}
+	var unknownValue Value
+	if thiz.UnknownField != "" {
+		unknownValue = values[thiz.UnknownField]
+		if unknownValue == nil || unknownValue.Kind() != reflect.Map {
+			return nil, thiz.handleError(fmt.Errorf("unknown flags field %s is not a map", thiz.UnknownField), nil)
+		}
+		delete(values, thiz.UnknownField)
+	}
+	var help, helpFull, version bool
+	if thiz.HelpFlag != "" {
+		var aliases []string
+		// Also accept -h unless it is used by another flag
+		if !hasFlag(values, "h") {
+			aliases = append(aliases, "h")
+		}
+		if err := thiz.addFlag(values, thiz.HelpFlag, &help, messages.HelpFlag, aliases...); err != nil {
+			return nil, thiz.handleError(err, nil)
+		}
+	}
+	if thiz.HelpFullFlag != "" {
+		if err := thiz.addFlag(values, thiz.HelpFullFlag, &helpFull, messages.HelpFullFlag); err != nil {
+			return nil, thiz.handleError(err, nil)
+		}
+	}
+	if thiz.Version != "" && thiz.VersionFlag != "" {
+		if err := thiz.addFlag(values, thiz.VersionFlag, &version, messages.VersionFlag); err != nil {
+			return nil, thiz.handleError(err, nil)
+		}
+	}
+	profile := thiz.Profile
+	if thiz.ProfileFlag != "" {
+		if err := thiz.addFlag(values, thiz.ProfileFlag, &profile, messages.ProfileFlag); err != nil {
+			return nil, thiz.handleError(err, nil)
+		}
+	}
+	var configFile string
+	if thiz.ConfigLoader != nil && thiz.ConfigFlag != "" {
+		if err := thiz.addFlag(values, thiz.ConfigFlag, &configFile, messages.ConfigFlag); err != nil {
+			return nil, thiz.handleError(err, nil)
+		}
+	}
+	parser, err := newArgsParser(values)
+	if err != nil {
+		return nil, thiz.handleError(err, nil)
+	}
+	if thiz.ArgsEnv != "" {
+		if env, ok := thiz.LookupEnv(thiz.ArgsEnv); ok {
+			envArgs, err := splitShellWords(env)
+			if err != nil {
+				return nil, thiz.handleError(fmt.Errorf("can not parse %s: %v", thiz.ArgsEnv, err), values)
+			}
+			args = append(envArgs, args...)
+		}
+	}
+	if thiz.ResponseFiles {
+		if args, err = expandResponseFiles(thiz.FS, args, 0); err != nil {
+			return nil, thiz.handleError(err, values)
+		}
+	}
+	parser.passUnknown = thiz.PassUnknown
+	parser.canonicalize = thiz.Canonicalize
+	parser.messages = messages
+	parser.onRenamed = func(old, name string) {
+		fmt.Fprintf(thiz.Output, messages.Warning+"\n", fmt.Sprintf(messages.RenamedFlag, old, name))
+	}
+	if unknownValue != nil {
+		parser.unknown = map[string]string{}
+	}
+	if thiz.OnFlagsUsed != nil {
+		parser.used = map[Value]bool{}
+	}
+	positional, err := parser.parse(args)
+	if err != nil {
+		return nil, thiz.handleError(err, values)
+	}
+	if thiz.OnFlagsUsed != nil {
+		names := []string{}
+		for name, value := range values {
+			if parser.used[value] {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+		thiz.OnFlagsUsed(names)
+	}
+	if len(parser.unknown) > 0 {
+		encoded, _ := json.Marshal(parser.unknown)
+		if err := unknownValue.Set(string(encoded)); err != nil {
+			return nil, thiz.handleError(fmt.Errorf("can not store unknown flags in %s: %s", thiz.UnknownField, errorDetail(err)), values)
+		}
+	}
+	switch {
+	case helpFull && thiz.FullUsage != nil:
+		thiz.FullUsage(thiz.Output, values)
+		return nil, thiz.handleExit(flag.ErrHelp)
+	case help || helpFull:
+		thiz.Usage(thiz.Output, values)
+		return nil, thiz.handleExit(flag.ErrHelp)
+	case version:
+		thiz.PrintVersion(thiz.Output, thiz.Version)
+		return nil, thiz.handleExit(ErrVersion)
+	}
+	if err := thiz.checkRemoved(converted); err != nil {
+		return nil, thiz.handleError(err, values)
+	}
+	if thiz.Env != nil {
+		env := *thiz.Env
+		if env.OnDeprecated == nil {
+			env.OnDeprecated = func(old, name string) {
+				fmt.Fprintf(thiz.Output, messages.Warning+"\n", fmt.Sprintf(messages.RenamedEnv, old, name))
+			}
+		}
+		if err := env.apply(converted, thiz.canSet(SourceEnv)); err != nil {
+			return nil, thiz.handleError(err, values)
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, thiz.handleError(err, values)
+	}
+	if err := thiz.loadConfig(converted, configFile); err != nil {
+		return nil, thiz.handleError(err, values)
+	}
+	if thiz.DownwardAPI != nil {
+		if err := thiz.DownwardAPI.apply(converted, thiz.canSet(SourceDownwardAPI)); err != nil {
+			return nil, thiz.handleError(err, values)
+		}
+	}
+	if profile != "" {
+		if err := thiz.applyProfile(converted, profile); err != nil {
+			return nil, thiz.handleError(err, values)
+		}
+	}
+	if err := thiz.applyDerivedDefaults(converted); err != nil {
+		return nil, thiz.handleError(err, values)
+	}
+	if len(thiz.Resolvers) > 0 {
+		if err := Resolve(ctx, converted, thiz.Resolvers); err != nil {
+			return nil, thiz.handleError(err, values)
+		}
+	}
+	if thiz.Expand {
+		if err := Expand(converted, thiz.Converter.WordSeparator); err != nil {
+			return nil, thiz.handleError(err, values)
+		}
+	}
+	if err := thiz.checkDependencies(converted); err != nil {
+		return nil, thiz.handleError(err, values)
+	}
+	return positional, nil
+}
+
+// Values returns the values bound to the fields of the target passed to the last
+// Parse call. It does not include help and version flags. The values can be
+// used to inspect or update the target after parsing, e.g. with ConfigHandler.
+func (thiz *Parser) Values() map[string]Value {
+	return thiz.values
+}
+
+// PrintUsage writes the help printed for the help flag to w without parsing
+// any arguments, e.g. to generate documentation or compare it with a golden
+// file. The output only depends on the parser and the struct, so it is the same
+// in every run if Usage does not detect the terminal. The target is not
+// changed. You must pass a pointer to the value.
+func (thiz *Parser) PrintUsage(w io.Writer, target interface{}) error {
+	if thiz.HelpFlag == "" {
+		return errors.New("parser has no help flag")
+	}
+	parser := *thiz
+	parser.Output = w
+	parser.ErrorHandling = flag.ContinueOnError
+	parser.ArgsEnv = ""
+	parser.ResponseFiles = false
+	parser.OnFlagsUsed = nil
+	if _, err := parser.Parse(Snapshot(target), []string{"--" + thiz.HelpFlag}); !errors.Is(err, flag.ErrHelp) {
+		return err
+	}
+	return nil
+}
+
+// addFlag adds a flag which is not bound to a field. The target must be a
+// pointer.
+func (thiz *Parser) addFlag(values map[string]Value, name string, target interface{}, description string, aliases ...string) error {
+	if hasFlag(values, name) {
+		return fmt.Errorf("flag name %q is used by both a field and the parser", name)
+	}
+	value := NewReflectedValue(reflect.ValueOf(target).Elem(), description).(*reflectedValue)
+	value.aliases = aliases
+	values[name] = value
+	return nil
+}
+
+// hasFlag returns true if name is used as a flag name or alias.
+func hasFlag(values map[string]Value, name string) bool {
+	for n, v := range values {
+		if n == name {
+			return true
+		}
+		for _, alias := range append(v.Aliases(), v.OldNames()...) {
+			if alias == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// handleError reports err according to ErrorHandling. Usage is printed if
+// values are available.
+func (thiz *Parser) handleError(err error, values map[string]Value) error {
+	if thiz.ErrorHandling == flag.ContinueOnError {
+		return err
+	}
+	fmt.Fprintln(thiz.Output, err)
+	if values != nil {
+		thiz.Usage(thiz.Output, values)
+	}
+	if thiz.ErrorHandling == flag.PanicOnError {
+		panic(err)
+	}
+	thiz.Exit(2)
+	return err
+}
+
+// handleExit terminates the program after help or version output if the
+// parser exits on errors.
+func (thiz *Parser) handleExit(err error) error {
+	if thiz.ErrorHandling == flag.ExitOnError {
+		thiz.Exit(0)
+	}
+	return err
+}
+
+// Parse parses command line arguments from os.Args into target using a parser
+// returned by NewParser. It exits the program on errors, just like flag.Parse.
+// The arguments remaining after flags are returned.
+func Parse(target interface{}) []string {
+	parser := NewParser()
+	parser.ErrorHandling = flag.ExitOnError
+	args, _ := parser.Parse(target, os.Args[1:])
+	return args
+}
@@ -0,0 +1,205 @@
+package structflag_test
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/surajbarkale/structflag"
+)
+
+type options struct {
+	Debug bool   `description:"Enable debug mode"`
+	Name  string `description:"Name to use"`
+	Count int
+}
+
+func newTestParser(out io.Writer) *structflag.Parser {
+	p := structflag.NewParser()
+	p.Name = "test"
+	p.Output = out
+	return p
+}
+
+func TestParseArgs(t *testing.T) {
+	val := &options{}
+	args, err := newTestParser(&bytes.Buffer{}).Parse(val, []string{"-Debug", "-Name", "abc", "-Count=5", "file"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"file"}, args)
+	assert.Equal(t, options{true, "abc", 5}, *val)
+}
+
+func TestParseError(t *testing.T) {
+	var out bytes.Buffer
+	_, err := newTestParser(&out).Parse(&options{}, []string{"-Count", "x"})
+	assert.Error(t, err)
+	assert.Empty(t, out.String())
+	_, err = newTestParser(&out).Parse(&options{}, []string{"-Unknown"})
+	assert.Error(t, err)
+}
+
+func TestParseHelp(t *testing.T) {
+	for _, arg := range []string{"-help", "-h", "--help"} {
+		t.Run(arg, func(t *testing.T) {
+			var out bytes.Buffer
+			_, err := newTestParser(&out).Parse(&options{}, []string{arg})
+			assert.Equal(t, flag.ErrHelp, err)
+			exp := `Usage of test:
+  -Count int
+  -Debug
+    	Enable debug mode
+  -Name string
+    	Name to use
+  -help
+    	Show this help and exit
+`
+			assert.Equal(t, exp, out.String())
+		})
+	}
+}
+
+func TestPrintUsage(t *testing.T) {
+	var out bytes.Buffer
+	parser := newTestParser(&bytes.Buffer{})
+	parser.ArgsEnv = "ARGS"
+	parser.LookupEnv = func(string) (string, bool) { return "--Count=x", true }
+	val := &options{Name: "a"}
+	require.NoError(t, parser.PrintUsage(&out, val))
+	assert.Equal(t, "Usage of test:\n  -Count int\n  -Debug\n    \tEnable debug mode\n  -Name string\n    \tName to use (default a)\n  -help\n    \tShow this help and exit\n", out.String())
+	assert.Equal(t, options{Name: "a"}, *val)
+
+	parser.HelpFlag = ""
+	assert.EqualError(t, parser.PrintUsage(&out, val), "parser has no help flag")
+}
+
+func TestParseOnFlagsUsed(t *testing.T) {
+	var used [][]string
+	parser := newTestParser(&bytes.Buffer{})
+	parser.OnFlagsUsed = func(names []string) {
+		used = append(used, names)
+	}
+	_, err := parser.Parse(&struct {
+		Debug bool `aliases:"d"`
+		Name  string
+		Count int
+	}{}, []string{"-d", "--Name=a", "file", "--Name", "b"})
+	require.NoError(t, err)
+	_, err = parser.Parse(&options{}, []string{"file"})
+	require.NoError(t, err)
+	_, err = parser.Parse(&options{}, []string{"--Count", "x"})
+	assert.Error(t, err)
+	assert.Equal(t, [][]string{{"Debug", "Name"}, {}}, used)
+}
+
+func TestOnFlagsUsedNotCalledByPrintUsageAndValidate(t *testing.T) {
+	called := false
+	parser := newTestParser(&bytes.Buffer{})
+	parser.OnFlagsUsed = func(names []string) {
+		called = true
+	}
+	require.NoError(t, parser.PrintUsage(&bytes.Buffer{}, &options{}))
+	require.NoError(t, parser.Validate(&options{}, []string{"--Debug"}))
+	assert.False(t, called)
+}
+
+func TestParseVersion(t *testing.T) {
+	var out bytes.Buffer
+	p := newTestParser(&out)
+	p.Version = "v1.2.3"
+	_, err := p.Parse(&options{}, []string{"-version"})
+	assert.Equal(t, structflag.ErrVersion, err)
+	assert.Equal(t, "v1.2.3\n", out.String())
+
+	out.Reset()
+	p.PrintVersion = func(w io.Writer, version string) {
+		io.WriteString(w, "test version "+version)
+	}
+	_, err = p.Parse(&options{}, []string{"-version"})
+	assert.Equal(t, structflag.ErrVersion, err)
+	assert.Equal(t, "test version v1.2.3", out.String())
+}
+
+func TestParseExitOnError(t *testing.T) {
+	var out bytes.Buffer
+	var code []int
+	p := newTestParser(&out)
+	p.ErrorHandling = flag.ExitOnError
+	p.Exit = func(c int) { code = append(code, c) }
+	p.Usage = func(w io.Writer, values map[string]structflag.Value) {
+		io.WriteString(w, "usage\n")
+	}
+	p.Parse(&options{}, []string{"-help"})
+	p.Parse(&options{}, []string{"-Count", "x"})
+	assert.Equal(t, []int{0, 2}, code)
+	assert.Contains(t, out.String(), "usage\n")
+	assert.Contains(t, out.String(), "invalid value")
+}
+
+func TestParseDisabledHelp(t *testing.T) {
+	val := &struct{ Help bool }{}
+	p := newTestParser(&bytes.Buffer{})
+	p.HelpFlag = ""
+	p.Converter = structflag.NewStructToFlagsConverter()
+	p.Converter.ReservedNames = nil
+	_, err := p.Parse(val, []string{"-Help"})
+	require.NoError(t, err)
+	assert.True(t, val.Help)
+}
+
+func TestParseUnknownIntoField(t *testing.T) {
+	val := &struct {
+		Debug bool
+		Extra map[string]string
+	}{}
+	p := newTestParser(&bytes.Buffer{})
+	p.UnknownField = "Extra"
+	args, err := p.Parse(val, []string{"--foo=1", "-Debug", "--bar", "x", "pos", "--baz", "--qux", "-q"})
+	require.NoError(t, err)
+	assert.True(t, val.Debug)
+	assert.Equal(t, []string{"pos"}, args)
+	assert.Equal(t, map[string]string{"foo": "1", "bar": "x", "baz": "true", "qux": "true", "q": "true"}, val.Extra)
+
+	_, err = p.Parse(val, []string{"--Extra", "{}"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"Extra": "{}"}, val.Extra)
+
+	p.UnknownField = "Debug"
+	_, err = p.Parse(val, nil)
+	assert.Error(t, err)
+}
+
+func TestParsePassUnknown(t *testing.T) {
+	val := &options{}
+	p := newTestParser(&bytes.Buffer{})
+	p.PassUnknown = true
+	args, err := p.Parse(val, []string{"a", "--foo=1", "--Count", "3", "--bar", "x", "-z", "--", "--Name"})
+	require.NoError(t, err)
+	assert.Equal(t, 3, val.Count)
+	assert.Equal(t, []string{"a", "--foo=1", "--bar", "x", "-z", "--Name"}, args)
+}
+
+func TestParserValues(t *testing.T) {
+	val := &options{}
+	p := newTestParser(&bytes.Buffer{})
+	_, err := p.Parse(val, []string{"--Count", "4"})
+	require.NoError(t, err)
+	sv := p.Values()
+	assert.Len(t, sv, 3)
+	assert.Equal(t, structflag.SourceFlag, sv["Count"].Source())
+	assert.Equal(t, structflag.SourceDefault, sv["Name"].Source())
+}
+
+func TestParseOldNames(t *testing.T) {
+	val := &struct {
+		ListenAddr string `wasNamed:"listen"`
+	}{}
+	var out bytes.Buffer
+	_, err := newTestParser(&out).Parse(val, []string{"--listen", ":80"})
+	require.NoError(t, err)
+	assert.Equal(t, ":80", val.ListenAddr)
+	assert.Equal(t, "warning: flag -listen is deprecated, use -ListenAddr\n", out.String())
+}
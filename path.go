@@ -0,0 +1,78 @@
+package structflag
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+// pathCodec expands a leading "~" to the home directory and environment
+// variables in string fields with `format:"path"` struct tag. Relative paths
+// read from configuration files can be resolved against the directory of the
+// file, see ConfigLoader.ResolvePaths.
+type pathCodec struct{}
+
+func init() {
+	RegisterFormat("path", pathCodec{})
+}
+
+// Match returns true for string types.
+func (thiz pathCodec) Match(t reflect.Type) bool {
+	return t.Kind() == reflect.String
+}
+
+// Decode stores the expanded path.
+func (thiz pathCodec) Decode(s string, val reflect.Value) error {
+	path, err := expandPath(s)
+	if err != nil {
+		return err
+	}
+	val.SetString(path)
+	return nil
+}
+
+// Encode returns the path as is.
+func (thiz pathCodec) Encode(val reflect.Value) (string, error) {
+	return val.String(), nil
+}
+
+// expandPath replaces "~" or "~/" at the beginning of path with the home
+// directory and expands environment variables.
+func expandPath(path string) (string, error) {
+	path = os.ExpandEnv(path)
+	if path == "~" || strings.HasPrefix(path, "~/") || strings.HasPrefix(path, "~"+string(filepath.Separator)) {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		path = home + path[1:]
+	}
+	return path, nil
+}
+
+// isPathValue returns true for values using the path format.
+func isPathValue(value Value) bool {
+	v, ok := value.(*reflectedValue)
+	if !ok {
+		return false
+	}
+	switch c := v.codec.(type) {
+	case pathCodec:
+		return true
+	case pointerCodec:
+		_, ok := c.Codec.(pathCodec)
+		return ok
+	}
+	return false
+}
+
+// resolvePath returns the expanded path made absolute relative to dir. Empty
+// path is returned as is.
+func resolvePath(path, dir string) (string, error) {
+	path, err := expandPath(path)
+	if err != nil || path == "" || filepath.IsAbs(path) {
+		return path, err
+	}
+	return filepath.Join(dir, path), nil
+}
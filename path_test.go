@@ -0,0 +1,62 @@
+package structflag_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/surajbarkale/structflag"
+)
+
+type pathOptions struct {
+	Data  string  `format:"path"`
+	Cache *string `format:"path"`
+	Name  string
+}
+
+func TestPathFormat(t *testing.T) {
+	home, err := os.UserHomeDir()
+	require.NoError(t, err)
+	t.Setenv("APP_DIR", "/srv/app")
+	val := &pathOptions{}
+	values, err := structflag.DefaultStructToFlagsConverter.Convert(val)
+	require.NoError(t, err)
+	require.NoError(t, values["Data"].Set("~/data"))
+	assert.Equal(t, filepath.Join(home, "data"), val.Data)
+	require.NoError(t, values["Data"].Set("~"))
+	assert.Equal(t, home, val.Data)
+	require.NoError(t, values["Data"].Set("~user/data"))
+	assert.Equal(t, "~user/data", val.Data)
+	require.NoError(t, values["Cache"].Set("$APP_DIR/cache"))
+	assert.Equal(t, "/srv/app/cache", *val.Cache)
+	require.NoError(t, values["Name"].Set("~/$APP_DIR"))
+	assert.Equal(t, "~/$APP_DIR", val.Name)
+	assert.Equal(t, "/srv/app/cache", values["Cache"].String())
+}
+
+func TestPathFormatConfigFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "structflag")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := writeTempFile(t, dir, "config.json", `{"Data": "data", "Cache": "/tmp/cache", "Name": "name"}`)
+	val := &pathOptions{}
+	values, err := structflag.DefaultStructToFlagsConverter.Convert(val)
+	require.NoError(t, err)
+	loader := structflag.NewConfigLoader()
+	require.NoError(t, loader.LoadFile(values, path))
+	assert.Equal(t, filepath.Join(dir, "data"), val.Data)
+	assert.Equal(t, "/tmp/cache", *val.Cache)
+	assert.Equal(t, "name", val.Name)
+
+	loader.ResolvePaths = false
+	require.NoError(t, loader.LoadFile(values, path))
+	assert.Equal(t, "data", val.Data)
+
+	loader.ResolvePaths = true
+	require.NoError(t, loader.Load(values, []byte(`{"Data": "other"}`)))
+	assert.Equal(t, "other", val.Data)
+}
@@ -0,0 +1,45 @@
+package structflag
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Preview returns a deep copy of target with the values keyed by flag name
+// applied, e.g. to show the effect of a change before it is made. The values
+// are validated in the same way as by Set and an error is returned for unknown
+// flags. The target is not modified and OnSet is not called. You must pass a
+// pointer to the value.
+func (thiz *StructToFlagsConverter) Preview(values map[string]string, target interface{}) (interface{}, error) {
+	if v := reflect.ValueOf(target); v.Kind() != reflect.Ptr || v.IsNil() {
+		return nil, fmt.Errorf("preview target must be a non-nil pointer, got %T", target)
+	}
+	preview := Snapshot(target)
+	// Changes of the copy are not applied, so they are not reported to OnSet
+	converted, err := thiz.withoutOnSet().Convert(preview)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value, ok := converted[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown flag %s%s", name, didYouMean(suggest(name, flagNames(converted))))
+		}
+		if err := value.Set(values[name]); err != nil {
+			return nil, err
+		}
+	}
+	return preview, nil
+}
+
+// Preview applies the values to a copy of target using
+// DefaultStructToFlagsConverter.
+func Preview(values map[string]string, target interface{}) (interface{}, error) {
+	return DefaultStructToFlagsConverter.Preview(values, target)
+}
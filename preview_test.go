@@ -0,0 +1,49 @@
+package structflag_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/surajbarkale/structflag"
+)
+
+type previewOptions struct {
+	Host   string
+	Port   int
+	Limits *struct {
+		Rate int
+	}
+	Tags []string
+}
+
+func TestPreview(t *testing.T) {
+	val := &previewOptions{Host: "localhost", Port: 80, Tags: []string{"a"}}
+	preview, err := structflag.Preview(map[string]string{"Port": "8080", "Limits-Rate": "5", "Tags": `["b"]`}, val)
+	require.NoError(t, err)
+	expected := &previewOptions{Host: "localhost", Port: 8080, Tags: []string{"b"}}
+	expected.Limits = &struct{ Rate int }{5}
+	assert.Equal(t, expected, preview)
+	assert.Equal(t, &previewOptions{Host: "localhost", Port: 80, Tags: []string{"a"}}, val)
+
+	_, err = structflag.Preview(map[string]string{"Port": "x"}, val)
+	assert.EqualError(t, err, `invalid value "x" for flag Port: strconv.ParseInt: parsing "x": invalid syntax, expected integer`)
+	_, err = structflag.Preview(map[string]string{"Prot": "1"}, val)
+	assert.EqualError(t, err, `unknown flag Prot, did you mean "Port"?`)
+	_, err = structflag.Preview(nil, *val)
+	assert.Error(t, err)
+}
+
+func TestPreviewDoesNotCallOnSet(t *testing.T) {
+	converter := structflag.NewStructToFlagsConverter()
+	var logged []string
+	converter.OnSet = func(path, oldValue, newValue string, source structflag.Source) {
+		logged = append(logged, path)
+	}
+	val := &previewOptions{Port: 1}
+	_, err := converter.Preview(map[string]string{"Port": "9"}, val)
+	require.NoError(t, err)
+	assert.Empty(t, logged)
+	assert.Equal(t, 1, val.Port)
+}
@@ -0,0 +1,64 @@
+package structflag
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SourceProfile is the source of values set from profile defaults.
+const SourceProfile Source = "profile"
+
+// applyProfile sets the defaults of the profile for the values which were not
+// set from any other source. An error is returned for unknown profiles.
+func (thiz *Parser) applyProfile(values map[string]Value, profile string) error {
+	tag := thiz.ProfileTagPrefix + profile
+	_, known := thiz.Profiles[profile]
+	for name, value := range values {
+		s, ok := thiz.Profiles[profile][name]
+		if !ok && thiz.ProfileTagPrefix != "" {
+			s, ok = value.Field().Tag.Lookup(tag)
+		}
+		if !ok {
+			continue
+		}
+		known = true
+		if !thiz.canSet(SourceProfile)(value) {
+			continue
+		}
+		if err := replaceFrom(value, s, SourceProfile); err != nil {
+			return fmt.Errorf("invalid value %q for flag %s in profile %s: %s", s, name, profile, errorDetail(err))
+		}
+	}
+	if !known {
+		return fmt.Errorf("unknown profile %q, available profiles: %s", profile, strings.Join(thiz.profileNames(values), ", "))
+	}
+	return nil
+}
+
+// profileNames returns the names of all profiles defined in Profiles or struct
+// tags of the values.
+func (thiz *Parser) profileNames(values map[string]Value) []string {
+	seen := map[string]bool{}
+	var names []string
+	add := func(name string) {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	for name := range thiz.Profiles {
+		add(name)
+	}
+	if thiz.ProfileTagPrefix != "" {
+		for _, value := range values {
+			for _, key := range tagKeys(value.Field().Tag) {
+				if strings.HasPrefix(key, thiz.ProfileTagPrefix) {
+					add(strings.TrimPrefix(key, thiz.ProfileTagPrefix))
+				}
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,73 @@
+package structflag_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/surajbarkale/structflag"
+)
+
+type profiled struct {
+	Addr    string `default_prod:":443" default_dev:"localhost:8080"`
+	Debug   bool   `default_dev:"true"`
+	Workers int    `description:"with \"quotes\"" default_staging:"4"`
+}
+
+func newProfileParser() *structflag.Parser {
+	p := newTestParser(&bytes.Buffer{})
+	p.ProfileFlag = "profile"
+	return p
+}
+
+func TestParseProfileFromTags(t *testing.T) {
+	val := &profiled{Addr: ":80"}
+	_, err := newProfileParser().Parse(val, []string{"--profile", "dev"})
+	require.NoError(t, err)
+	assert.Equal(t, profiled{"localhost:8080", true, 0}, *val)
+
+	val = &profiled{Addr: ":80"}
+	p := newProfileParser()
+	_, err = p.Parse(val, []string{"--profile=prod", "--Addr", ":8443"})
+	require.NoError(t, err)
+	assert.Equal(t, profiled{":8443", false, 0}, *val)
+	assert.Equal(t, structflag.SourceFlag, p.Values()["Addr"].Source())
+
+	val = &profiled{Addr: ":80"}
+	_, err = newProfileParser().Parse(val, nil)
+	require.NoError(t, err)
+	assert.Equal(t, profiled{":80", false, 0}, *val)
+}
+
+func TestParseProfileFromMap(t *testing.T) {
+	val := &profiled{}
+	p := newProfileParser()
+	p.Profile = "prod"
+	p.Profiles = map[string]map[string]string{
+		"prod": {"Workers": "16"},
+		"ci":   {"Debug": "true"},
+	}
+	_, err := p.Parse(val, nil)
+	require.NoError(t, err)
+	assert.Equal(t, profiled{":443", false, 16}, *val)
+	assert.Equal(t, structflag.SourceProfile, p.Values()["Workers"].Source())
+
+	val = &profiled{}
+	_, err = p.Parse(val, []string{"--profile", "ci"})
+	require.NoError(t, err)
+	assert.Equal(t, profiled{"", true, 0}, *val)
+}
+
+func TestParseUnknownProfile(t *testing.T) {
+	p := newProfileParser()
+	p.Profiles = map[string]map[string]string{"ci": {}}
+	_, err := p.Parse(&profiled{}, []string{"--profile", "prdo"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "ci, dev, prod, staging")
+
+	p.Profiles = map[string]map[string]string{"broken": {"Workers": "x"}}
+	_, err = p.Parse(&profiled{}, []string{"--profile", "broken"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,80 @@
+/*
+Package promflag exports configuration metadata as Prometheus metrics, so that
+configuration drift across a fleet can be observed in monitoring:
+
+	values, err := structflag.DefaultStructToFlagsConverter.Convert(&config)
+	if err != nil {
+		return err
+	}
+	prometheus.MustRegister(promflag.NewCollector("myapp", values))
+
+Values are not exported, only hashes of them, so secrets are not exposed.
+*/
+package promflag
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/surajbarkale/structflag"
+)
+
+// Collector exports an info metric for each flag with "flag", "source" and
+// "hash" labels and a single info metric with the "hash" of all values. The
+// values are read on every scrape, so changes at runtime are visible.
+type Collector struct {
+	values   map[string]structflag.Value
+	flagDesc *prometheus.Desc
+	infoDesc *prometheus.Desc
+}
+
+// NewCollector returns a collector for the values exporting
+// <namespace>_config_flag_info and <namespace>_config_info metrics.
+func NewCollector(namespace string, values map[string]structflag.Value) *Collector {
+	return &Collector{
+		values: values,
+		flagDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "config", "flag_info"),
+			"Configuration flag with the hash of its value and its source.",
+			[]string{"flag", "source", "hash"}, nil,
+		),
+		infoDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "config", "info"),
+			"Configuration with the hash of all flag values.",
+			[]string{"hash"}, nil,
+		),
+	}
+}
+
+// Describe sends the descriptors of the metrics.
+func (thiz *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- thiz.flagDesc
+	ch <- thiz.infoDesc
+}
+
+// Collect sends the current metrics.
+func (thiz *Collector) Collect(ch chan<- prometheus.Metric) {
+	names := make([]string, 0, len(thiz.values))
+	for name := range thiz.values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	all := sha256.New()
+	for _, name := range names {
+		value := thiz.values[name]
+		s := value.String()
+		all.Write([]byte(name + "\x00" + s + "\x00"))
+		ch <- prometheus.MustNewConstMetric(thiz.flagDesc, prometheus.GaugeValue, 1, name, string(value.Source()), Hash(s))
+	}
+	ch <- prometheus.MustNewConstMetric(thiz.infoDesc, prometheus.GaugeValue, 1, hex.EncodeToString(all.Sum(nil))[:16])
+}
+
+// Hash returns the hash of a value used in the labels, the first 16 hex digits
+// of its SHA-256 hash.
+func Hash(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])[:16]
+}
@@ -0,0 +1,55 @@
+package promflag_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/surajbarkale/structflag"
+	"github.com/surajbarkale/structflag/promflag"
+)
+
+func TestCollector(t *testing.T) {
+	val := &struct {
+		Host     string
+		Password string `secret:"true"`
+	}{Host: "localhost"}
+	values, err := structflag.DefaultStructToFlagsConverter.Convert(val)
+	require.NoError(t, err)
+	require.NoError(t, values["Password"].Set("hunter2"))
+	collector := promflag.NewCollector("app", values)
+
+	expected := `
+# HELP app_config_flag_info Configuration flag with the hash of its value and its source.
+# TYPE app_config_flag_info gauge
+app_config_flag_info{flag="Host",hash="` + promflag.Hash("localhost") + `",source="default"} 1
+app_config_flag_info{flag="Password",hash="` + promflag.Hash("hunter2") + `",source="flag"} 1
+`
+	require.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(expected), "app_config_flag_info"))
+	assert.Equal(t, 3, testutil.CollectAndCount(collector))
+	assert.Len(t, promflag.Hash("x"), 16)
+	assert.NotContains(t, promflag.Hash("hunter2"), "hunter2")
+
+	before := configHash(t, collector)
+	require.NoError(t, values["Host"].Set("example.com"))
+	assert.NotEqual(t, before, configHash(t, collector))
+}
+
+// configHash returns the hash label of app_config_info metric.
+func configHash(t *testing.T, collector prometheus.Collector) string {
+	registry := prometheus.NewPedanticRegistry()
+	require.NoError(t, registry.Register(collector))
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	for _, family := range families {
+		if family.GetName() == "app_config_info" {
+			return family.GetMetric()[0].GetLabel()[0].GetValue()
+		}
+	}
+	t.Fatal("app_config_info not found")
+	return ""
+}
@@ -0,0 +1,16 @@
+module github.com/surajbarkale/structflag/protoflag
+
+go 1.18
+
+require (
+	github.com/stretchr/testify v1.3.0
+	github.com/surajbarkale/structflag v0.0.0
+	google.golang.org/protobuf v1.28.1
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+)
+
+replace github.com/surajbarkale/structflag => ../
@@ -0,0 +1,68 @@
+/*
+Package protoflag adds support for protocol buffer message fields to structflag.
+Importing this package registers a codec which converts values implementing
+proto.Message using protojson:
+
+	import _ "github.com/surajbarkale/structflag/protoflag"
+
+Message fields are treated as single flag values. They must be specified using
+the canonical protobuf JSON mapping and are printed in the same format.
+*/
+package protoflag
+
+import (
+	"reflect"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/surajbarkale/structflag"
+)
+
+var messageType = reflect.TypeOf((*proto.Message)(nil)).Elem()
+
+// Codec converts proto.Message values using protojson.
+type Codec struct {
+	// UnmarshalOptions are used when decoding flag values.
+	UnmarshalOptions protojson.UnmarshalOptions
+	// MarshalOptions are used when encoding flag values.
+	MarshalOptions protojson.MarshalOptions
+}
+
+func init() {
+	structflag.RegisterCodec(&Codec{})
+}
+
+// Match returns true for pointer types implementing proto.Message.
+func (thiz *Codec) Match(t reflect.Type) bool {
+	return t.Kind() == reflect.Ptr && t.Implements(messageType)
+}
+
+// Decode parses protobuf JSON into the message. A new message is allocated
+// if val is nil.
+func (thiz *Codec) Decode(s string, val reflect.Value) error {
+	res := reflect.New(val.Type().Elem())
+	if err := thiz.UnmarshalOptions.Unmarshal([]byte(s), res.Interface().(proto.Message)); err != nil {
+		return err
+	}
+	if val.IsNil() {
+		val.Set(res)
+	} else {
+		proto.Reset(val.Interface().(proto.Message))
+		proto.Merge(val.Interface().(proto.Message), res.Interface().(proto.Message))
+	}
+	return nil
+}
+
+// Encode returns protobuf JSON for the message. Nil messages are returned as
+// empty string.
+func (thiz *Codec) Encode(val reflect.Value) (string, error) {
+	if val.IsNil() {
+		return "", nil
+	}
+	bytes, err := thiz.MarshalOptions.Marshal(val.Interface().(proto.Message))
+	if err != nil {
+		return "", err
+	}
+	return string(bytes), nil
+}
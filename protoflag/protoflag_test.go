@@ -0,0 +1,58 @@
+package protoflag_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/surajbarkale/structflag"
+	_ "github.com/surajbarkale/structflag/protoflag"
+)
+
+type settings struct {
+	Timeout *durationpb.Duration
+	Extra   *structpb.Struct
+	Name    string
+}
+
+func TestMessageFieldsAreValues(t *testing.T) {
+	val := &settings{}
+	sv, err := structflag.NewStructToFlagsConverter().Convert(val)
+	require.NoError(t, err)
+	assert.Len(t, sv, 3)
+	require.Contains(t, sv, "Timeout")
+	require.Contains(t, sv, "Extra")
+	assert.Nil(t, val.Timeout)
+	assert.Equal(t, "", sv["Timeout"].String())
+}
+
+func TestSetMessageFromJSON(t *testing.T) {
+	val := &settings{}
+	sv, err := structflag.NewStructToFlagsConverter().Convert(val)
+	require.NoError(t, err)
+	require.NoError(t, sv["Timeout"].Set(`"1.5s"`))
+	require.NotNil(t, val.Timeout)
+	assert.Equal(t, 1500*time.Millisecond, val.Timeout.AsDuration())
+	assert.Equal(t, `"1.500s"`, sv["Timeout"].String())
+
+	require.NoError(t, sv["Extra"].Set(`{"a": [1, "x"]}`))
+	exp, err := structpb.NewStruct(map[string]interface{}{"a": []interface{}{1, "x"}})
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(exp, val.Extra))
+	assert.Error(t, sv["Extra"].Set(`[1]`))
+}
+
+func TestSetShouldNotReplaceMessage(t *testing.T) {
+	timeout := durationpb.New(time.Second)
+	val := &settings{Timeout: timeout}
+	sv, err := structflag.NewStructToFlagsConverter().Convert(val)
+	require.NoError(t, err)
+	require.NoError(t, sv["Timeout"].Set(`"3s"`))
+	assert.True(t, timeout == val.Timeout)
+	assert.Equal(t, 3*time.Second, val.Timeout.AsDuration())
+}
@@ -0,0 +1,163 @@
+package structflag
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// queryCodec converts structs and maps from URL query strings, e.g.
+// "a=1&b.c=2&list=x&list=y". Dots in keys select nested struct fields and map
+// entries, repeated keys fill slices. Struct keys are field names as they are
+// used in flag names.
+type queryCodec struct {
+	converter *StructToFlagsConverter
+}
+
+func init() {
+	RegisterFormat("query", queryCodec{})
+}
+
+func (thiz queryCodec) withConverter(converter *StructToFlagsConverter) Codec {
+	return queryCodec{converter: converter}
+}
+
+// Match returns true for structs and maps with string keys.
+func (thiz queryCodec) Match(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct || (t.Kind() == reflect.Map && t.Key().Kind() == reflect.String)
+}
+
+// Decode parses the query into a new value. A leading "?" is ignored.
+func (thiz queryCodec) Decode(s string, val reflect.Value) error {
+	query, err := url.ParseQuery(strings.TrimPrefix(s, "?"))
+	if err != nil {
+		return err
+	}
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	res := reflect.New(val.Type()).Elem()
+	for _, key := range keys {
+		if err := thiz.set(res, strings.Split(key, "."), query[key]); err != nil {
+			return fmt.Errorf("invalid value for %s: %v", key, err)
+		}
+	}
+	val.Set(res)
+	return nil
+}
+
+// set stores values at path relative to val.
+func (thiz queryCodec) set(val reflect.Value, path []string, values []string) error {
+	if len(path) == 0 {
+		return setQueryValues(val, values)
+	}
+	switch val.Kind() {
+	case reflect.Ptr:
+		if val.IsNil() {
+			val.Set(reflect.New(val.Type().Elem()))
+		}
+		return thiz.set(val.Elem(), path, values)
+	case reflect.Struct:
+		if isCodecType(val.Type()) {
+			break
+		}
+		field, ok := formatConverter(thiz.converter).fieldByKey(val, path[0])
+		if !ok {
+			return fmt.Errorf("unknown key %q", path[0])
+		}
+		return thiz.set(field, path[1:], values)
+	case reflect.Map:
+		if val.Type().Key().Kind() != reflect.String {
+			break
+		}
+		if val.IsNil() {
+			val.Set(reflect.MakeMap(val.Type()))
+		}
+		elemType := val.Type().Elem()
+		// Keys of maps with scalar values can contain dots
+		if len(path) > 1 && !isQueryContainer(elemType) {
+			path = []string{strings.Join(path, ".")}
+		}
+		key := reflect.ValueOf(path[0]).Convert(val.Type().Key())
+		elem := reflect.New(elemType).Elem()
+		if existing := val.MapIndex(key); existing.IsValid() {
+			elem.Set(existing)
+		}
+		if err := thiz.set(elem, path[1:], values); err != nil {
+			return err
+		}
+		val.SetMapIndex(key, elem)
+		return nil
+	}
+	return fmt.Errorf("key %q can not be used with %s", path[0], val.Type())
+}
+
+// isQueryContainer returns true for types which have their own keys.
+func isQueryContainer(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return (t.Kind() == reflect.Struct && !isCodecType(t)) || (t.Kind() == reflect.Map && t.Key().Kind() == reflect.String)
+}
+
+// setQueryValues stores values into val. Slices get all values, other kinds
+// get the last one.
+func setQueryValues(val reflect.Value, values []string) error {
+	if val.Kind() == reflect.Slice && !isCodecType(val.Type()) {
+		res := reflect.MakeSlice(val.Type(), 0, len(values))
+		for _, s := range values {
+			elem := reflect.New(val.Type().Elem()).Elem()
+			if err := decodeString(s, elem); err != nil {
+				return err
+			}
+			res = reflect.Append(res, elem)
+		}
+		val.Set(res)
+		return nil
+	}
+	return decodeString(values[len(values)-1], val)
+}
+
+// Encode returns the query with keys sorted. Empty struct fields are omitted.
+func (thiz queryCodec) Encode(val reflect.Value) (string, error) {
+	query := url.Values{}
+	thiz.encode(query, "", val)
+	return query.Encode(), nil
+}
+
+func (thiz queryCodec) encode(query url.Values, key string, val reflect.Value) {
+	for val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			return
+		}
+		val = val.Elem()
+	}
+	child := func(name string) string {
+		if key == "" {
+			return name
+		}
+		return key + "." + name
+	}
+	switch {
+	case isQueryContainer(val.Type()) && val.Kind() == reflect.Struct:
+		for i := 0; i < val.NumField(); i++ {
+			if name, ok := formatConverter(thiz.converter).fieldKey(val.Type().Field(i)); ok && !isEmpty(val.Field(i)) {
+				thiz.encode(query, child(name), val.Field(i))
+			}
+		}
+	case isQueryContainer(val.Type()):
+		for _, name := range val.MapKeys() {
+			thiz.encode(query, child(name.String()), val.MapIndex(name))
+		}
+	case val.Kind() == reflect.Slice && !isCodecType(val.Type()):
+		for i := 0; i < val.Len(); i++ {
+			query.Add(key, encodeString(val.Index(i)))
+		}
+	default:
+		query.Add(key, encodeString(val))
+	}
+}
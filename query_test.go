@@ -0,0 +1,50 @@
+package structflag_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/surajbarkale/structflag"
+)
+
+type hook struct {
+	Name    string
+	Retries int
+	Tags    []string
+	Target  struct {
+		URL     string
+		Timeout float64
+	}
+	Headers map[string]string
+}
+
+func TestQueryFormat(t *testing.T) {
+	val := &struct {
+		Hook   hook                `format:"query"`
+		Params map[string][]string `format:"query"`
+		Limits *map[string]int     `format:"query"`
+	}{}
+	values, err := structflag.DefaultStructToFlagsConverter.Convert(val)
+	require.NoError(t, err)
+	assert.Len(t, values, 3)
+
+	require.NoError(t, values["Hook"].Set("name=deploy&retries=3&tags=a&tags=b%20c&target.url=http%3A%2F%2Fx&Target.Timeout=1.5&headers.X-Token=abc&headers.a.b=1"))
+	exp := hook{Name: "deploy", Retries: 3, Tags: []string{"a", "b c"}, Headers: map[string]string{"X-Token": "abc", "a.b": "1"}}
+	exp.Target.URL = "http://x"
+	exp.Target.Timeout = 1.5
+	assert.Equal(t, exp, val.Hook)
+	assert.Equal(t, "Headers.X-Token=abc&Headers.a.b=1&Name=deploy&Retries=3&Tags=a&Tags=b+c&Target.Timeout=1.5&Target.URL=http%3A%2F%2Fx", values["Hook"].String())
+
+	assert.EqualError(t, values["Hook"].Set("user=x"), `invalid value "user=x" for flag Hook: invalid value for user: unknown key "user"`)
+	assert.Error(t, values["Hook"].Set("retries=x"))
+	assert.Error(t, values["Hook"].Set("name.first=x"))
+	assert.Equal(t, exp, val.Hook)
+
+	require.NoError(t, values["Params"].Set("?q=go&q=flags&page=2"))
+	assert.Equal(t, map[string][]string{"q": {"go", "flags"}, "page": {"2"}}, val.Params)
+	require.NoError(t, values["Limits"].Set("cpu=2&memory=512"))
+	assert.Equal(t, map[string]int{"cpu": 2, "memory": 512}, *val.Limits)
+	assert.Equal(t, "cpu=2&memory=512", values["Limits"].String())
+}
@@ -1,30 +1,152 @@
 package structflag
 
 import (
+	"encoding"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"reflect"
 	"strconv"
+	"time"
+)
+
+var (
+	rawMessageType      = reflect.TypeOf(json.RawMessage(nil))
+	durationType        = reflect.TypeOf(time.Duration(0))
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
 )
 
 // Value adds ability to get description for flag.Value
 type Value interface {
 	flag.Getter
 	Description() string
+	// Default returns string representation of the initial value. It is empty
+	// if the initial value was the zero value or an empty slice or map.
+	Default() string
+	// Reset restores the value that was present when this Value was created.
+	Reset()
+	// Kind returns the kind of the value with pointers dereferenced.
+	Kind() reflect.Kind
+	// IsSlice returns true for slice values.
+	IsSlice() bool
+	// IsBool returns true for boolean values.
+	IsBool() bool
+	// ElemType returns the element type of slice, array and map values. It
+	// returns nil for other kinds.
+	ElemType() reflect.Type
+	// Field returns the struct field this value was created from. It returns
+	// zero value if the value was not created from a struct field.
+	Field() reflect.StructField
+	// Aliases returns additional flag names for this value.
+	Aliases() []string
+	// OldNames returns deprecated flag names which were used for this value
+	// before it was renamed.
+	OldNames() []string
+	// Group returns the group of the value or empty string if it has none.
+	Group() string
+	// IsSecret returns true if the value must not be shown to users.
+	IsSecret() bool
+	// Source returns where the current value came from.
+	Source() Source
+}
+
+// ResetAll restores initial values for all given values.
+func ResetAll(values map[string]Value) {
+	for _, v := range values {
+		v.Reset()
+	}
 }
 
 type reflectedValue struct {
-	target      reflect.Value
-	description string
+	target       reflect.Value
+	path         string
+	initial      reflect.Value
+	defValue     string
+	description  string
+	field        reflect.StructField
+	aliases      []string
+	oldNames     []string
+	group        string
+	secret       bool
+	choices      string
+	env          string
+	example      string
+	dynamic      bool
+	nullLiteral  string
+	copyOnGet    bool
+	repeatPolicy RepeatPolicy
+	source       Source
+	sealed       bool
+	attach       func()
+	onSet        func(path, oldValue, newValue string, source Source)
+	check        func(reflect.Value) error
+	codec        Codec
+	unmarshal    func(data []byte, v interface{}) error
 }
 
+// RepeatPolicy defines how repeated Set calls are handled for values that are
+// not slices.
+type RepeatPolicy int
+
+const (
+	// LastSetWins keeps the value from the last Set call.
+	LastSetWins RepeatPolicy = iota
+	// FirstSetWins keeps the value from the first Set call and ignores the
+	// following calls.
+	FirstSetWins
+	// RejectRepeatedSet returns an error wrapping ErrRepeatedSet from the second
+	// and following Set calls.
+	RejectRepeatedSet
+)
+
+// ErrRepeatedSet is wrapped by errors returned when a value with
+// RejectRepeatedSet policy is set again.
+var ErrRepeatedSet = errors.New("value can only be set once")
+
 // NewReflectedValue creates a new flag value that converts string into the given
 // reflected value. Bool, Int, UInt and Float values are converted using functions
 // from strconv package. For String values, input can be either a bare string or a
 // valid JSON string. Arrays, maps and structures must be specified using JSON syntax.
+// json.RawMessage values store the validated JSON text as is and empty interface
+// values are decoded generically from JSON, falling back to the bare string.
 func NewReflectedValue(target reflect.Value, description string) Value {
-	return &reflectedValue{target, description}
+	return &reflectedValue{
+		target:      target,
+		initial:     deepCopy(target),
+		defValue:    defaultString(target),
+		description: description,
+		source:      SourceDefault,
+	}
+}
+
+func defaultString(val reflect.Value) string {
+	if isEmpty(val) {
+		return ""
+	}
+	return encodeString(val)
+}
+
+// defaultString returns string representation of the target, or empty string
+// if it is empty.
+func (thiz *reflectedValue) defaultString() string {
+	if isEmpty(thiz.target) {
+		return ""
+	}
+	return thiz.encode(thiz.target)
+}
+
+// isEmpty returns true for zero values and empty slices and maps.
+func isEmpty(val reflect.Value) bool {
+	if !val.IsValid() || reflect.DeepEqual(val.Interface(), reflect.Zero(val.Type()).Interface()) {
+		return true
+	}
+	switch val.Kind() {
+	case reflect.Map, reflect.Slice:
+		return val.Len() == 0
+	}
+	return false
 }
 
 // Description returns stored description for this value.
@@ -32,33 +154,287 @@ func (thiz *reflectedValue) Description() string {
 	return thiz.description
 }
 
+// Default returns string representation of the initial value.
+func (thiz *reflectedValue) Default() string {
+	return thiz.defValue
+}
+
+// Reset restores the value that was present when this Value was created. The
+// value is considered not set afterwards.
+func (thiz *reflectedValue) Reset() {
+	assign(thiz.target, deepCopy(thiz.initial))
+	thiz.source = SourceDefault
+}
+
 // IsBoolFlag returns true if the required value is boolean. This is added for
 // compatibility with kingpin library.
 func (thiz *reflectedValue) IsBoolFlag() bool {
-	return reflect.Indirect(thiz.target).Kind() == reflect.Bool
+	return thiz.IsBool()
+}
+
+// Kind returns the kind of the value with pointers dereferenced.
+func (thiz *reflectedValue) Kind() reflect.Kind {
+	return thiz.baseType().Kind()
+}
+
+// IsSlice returns true for slice values.
+func (thiz *reflectedValue) IsSlice() bool {
+	return thiz.Kind() == reflect.Slice
+}
+
+// IsBool returns true for boolean values.
+func (thiz *reflectedValue) IsBool() bool {
+	return thiz.Kind() == reflect.Bool
+}
+
+// ElemType returns the element type of slice, array and map values.
+func (thiz *reflectedValue) ElemType() reflect.Type {
+	switch t := thiz.baseType(); t.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return t.Elem()
+	}
+	return nil
+}
+
+// Field returns the struct field this value was created from.
+func (thiz *reflectedValue) Field() reflect.StructField {
+	return thiz.field
+}
+
+// Aliases returns additional flag names for this value.
+func (thiz *reflectedValue) Aliases() []string {
+	return thiz.aliases
+}
+
+// OldNames returns deprecated flag names for this value.
+func (thiz *reflectedValue) OldNames() []string {
+	return thiz.oldNames
+}
+
+// Group returns the group of the value.
+func (thiz *reflectedValue) Group() string {
+	return thiz.group
+}
+
+// IsSecret returns true if the value must not be shown to users.
+func (thiz *reflectedValue) IsSecret() bool {
+	return thiz.secret
+}
+
+// Source returns where the current value came from.
+func (thiz *reflectedValue) Source() Source {
+	return thiz.source
+}
+
+func (thiz *reflectedValue) baseType() reflect.Type {
+	t := thiz.target.Type()
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	// Atomic values behave like their content
+	if isAtomic(t) {
+		t = atomicType(t)
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+	}
+	return t
 }
 
 // String returns the value as string. Primitive values are returned
 // as naked values. Complex values are returned as JSON strings.
 func (thiz *reflectedValue) String() string {
-	return encodeString(thiz.target)
+	return thiz.encode(thiz.target)
+}
+
+// encode converts val using the codec of the value if it has one.
+func (thiz *reflectedValue) encode(val reflect.Value) string {
+	if thiz.codec == nil {
+		return encodeString(val)
+	}
+	res, err := thiz.codec.Encode(val)
+	if err != nil {
+		panic(fmt.Errorf("can not convert %s value to string %v", val.Type().String(), err))
+	}
+	return res
+}
+
+// decode parses s into the target using the codec of the value if it has one.
+// Complex values are decoded using the unmarshal function if it is set.
+func (thiz *reflectedValue) decode(s string) error {
+	switch {
+	case thiz.codec != nil:
+		return thiz.codec.Decode(s, thiz.target)
+	case thiz.unmarshal != nil && isComplex(thiz.target.Type()):
+		res := reflect.New(thiz.target.Type())
+		if err := thiz.unmarshal([]byte(s), res.Interface()); err != nil {
+			return err
+		}
+		assign(thiz.target, res.Elem())
+		return nil
+	}
+	return decodeString(s, thiz.target)
+}
+
+// isComplex returns true for struct, map, slice and array types which are
+// decoded from JSON by default.
+func isComplex(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		if isCodecType(t) {
+			return false
+		}
+		t = t.Elem()
+	}
+	if isCodecType(t) || t == rawMessageType {
+		return false
+	}
+	switch t.Kind() {
+	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+		return true
+	}
+	return false
 }
 
-// Get returns the underlying value
+// Get returns the underlying value. If the value was created with CopyOnGet
+// option, then a deep copy is returned so that the caller can not modify the
+// target through pointers, slices or maps. The content of atomic values is
+// returned instead of the atomic value itself.
 func (thiz *reflectedValue) Get() interface{} {
+	if isAtomic(thiz.target.Type()) {
+		return atomicLoad(thiz.target).Interface()
+	}
+	if thiz.copyOnGet {
+		return deepCopy(thiz.target).Interface()
+	}
 	return thiz.target.Interface()
 }
 
 // Set updates the value by parsing source string. Complex objects are
-// parsed as JSON values.
+// parsed as JSON values. If the source matches the null literal, then pointer,
+// map, slice and interface values are set to nil.
 func (thiz *reflectedValue) Set(source string) error {
-	return decodeString(source, thiz.target)
+	return thiz.setFrom(source, SourceFlag)
+}
+
+func (thiz *reflectedValue) setFrom(s string, source Source) error {
+	if thiz.source != SourceDefault && !thiz.IsSlice() && !thiz.sealed {
+		switch thiz.repeatPolicy {
+		case FirstSetWins:
+			return nil
+		case RejectRepeatedSet:
+			return &ValueError{Path: thiz.path, Input: s, Err: ErrRepeatedSet}
+		}
+	}
+	return thiz.replaceFrom(s, source)
+}
+
+// replaceFrom sets the value without applying the repeat policy.
+func (thiz *reflectedValue) replaceFrom(s string, source Source) error {
+	if thiz.sealed {
+		return &ValueError{Path: thiz.path, Input: s, Err: ErrSealed}
+	}
+	var restore func()
+	if thiz.check != nil {
+		restore = thiz.save()
+	}
+	var old string
+	if thiz.onSet != nil {
+		old = thiz.masked()
+	}
+	if thiz.nullLiteral != "" && s == thiz.nullLiteral && isNullable(thiz.target.Kind()) {
+		thiz.target.Set(reflect.Zero(thiz.target.Type()))
+	} else if err := thiz.decode(s); err != nil {
+		return &ValueError{Path: thiz.path, Input: s, Expected: thiz.expected(), Err: err}
+	}
+	if thiz.check != nil {
+		if err := thiz.check(thiz.target); err != nil {
+			restore()
+			return &ValueError{Path: thiz.path, Input: s, Err: err}
+		}
+	}
+	thiz.source = source
+	if thiz.attach != nil {
+		thiz.attach()
+	}
+	if thiz.onSet != nil {
+		thiz.onSet(thiz.path, old, thiz.masked(), source)
+	}
+	return nil
+}
+
+// masked returns the current value as string with secrets hidden.
+func (thiz *reflectedValue) masked() string {
+	s := thiz.String()
+	if thiz.secret && s != "" {
+		return "******"
+	}
+	return s
+}
+
+// expected describes the syntax accepted by the value for error messages.
+func (thiz *reflectedValue) expected() string {
+	if thiz.codec != nil || (thiz.unmarshal != nil && isComplex(thiz.target.Type())) {
+		return ""
+	}
+	return expectedSyntax(thiz.target.Type())
+}
+
+func (thiz *reflectedValue) seal() {
+	thiz.sealed = true
+}
+
+func (thiz *reflectedValue) save() func() {
+	saved, source := deepCopy(thiz.target), thiz.source
+	return func() {
+		assign(thiz.target, saved)
+		thiz.source = source
+	}
+}
+
+// isTextType returns true for types which are converted using their
+// encoding.TextMarshaler and encoding.TextUnmarshaler implementations. Pointers
+// and interfaces are handled by converting the values they point to.
+func isTextType(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr || t.Kind() == reflect.Interface {
+		return false
+	}
+	return t.Implements(textMarshalerType) || reflect.PtrTo(t).Implements(textMarshalerType)
+}
+
+func isNullable(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface:
+		return true
+	}
+	return false
 }
 
 func encodeString(val reflect.Value) string {
 	if !val.IsValid() {
 		return ""
 	}
+	if c := findCodec(val.Type()); c != nil {
+		res, err := c.Encode(val)
+		if err != nil {
+			panic(fmt.Errorf("can not convert %s value to string %v", val.Type().String(), err))
+		}
+		return res
+	}
+	if val.Type() == rawMessageType {
+		return string(val.Bytes())
+	}
+	if val.Type() == durationType {
+		return val.Interface().(time.Duration).String()
+	}
+	if isTextType(val.Type()) {
+		if m, ok := addressable(val).Addr().Interface().(encoding.TextMarshaler); ok {
+			text, err := m.MarshalText()
+			if err != nil {
+				panic(fmt.Errorf("can not convert %s value to string %v", val.Type().String(), err))
+			}
+			return string(text)
+		}
+	}
 	switch val.Kind() {
 	case reflect.Ptr, reflect.UnsafePointer:
 		if val.IsNil() {
@@ -85,6 +461,37 @@ func encodeString(val reflect.Value) string {
 }
 
 func decodeString(s string, val reflect.Value) error {
+	if c := findCodec(val.Type()); c != nil {
+		return c.Decode(s, val)
+	}
+	if val.Type() == rawMessageType {
+		if !json.Valid([]byte(s)) {
+			return fmt.Errorf("invalid JSON value %q", s)
+		}
+		val.SetBytes([]byte(s))
+		return nil
+	}
+	if val.Type() == durationType {
+		res, err := time.ParseDuration(s)
+		if err != nil {
+			// Plain numbers are nanoseconds
+			var n int64
+			if n, err = strconv.ParseInt(s, 10, 64); err != nil {
+				return fmt.Errorf("invalid duration %q", s)
+			}
+			res = time.Duration(n)
+		}
+		val.SetInt(int64(res))
+		return nil
+	}
+	if isTextType(val.Type()) && reflect.PtrTo(val.Type()).Implements(textUnmarshalerType) {
+		res := reflect.New(val.Type())
+		if err := res.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s)); err != nil {
+			return err
+		}
+		val.Set(res.Elem())
+		return nil
+	}
 	switch val.Kind() {
 	case reflect.Bool:
 		res, err := strconv.ParseBool(s)
@@ -137,6 +544,20 @@ func decodeString(s string, val reflect.Value) error {
 		} else {
 			val.Elem().Set(res.Elem())
 		}
+	case reflect.Interface:
+		if val.NumMethod() != 0 {
+			return fmt.Errorf("can not decode %s value", val.Type().String())
+		}
+		var res interface{}
+		// Bare strings are accepted when the input is not valid JSON
+		if json.Unmarshal([]byte(s), &res) != nil {
+			res = s
+		}
+		if res == nil {
+			val.Set(reflect.Zero(val.Type()))
+		} else {
+			val.Set(reflect.ValueOf(res))
+		}
 	default:
 		res := reflect.New(val.Type())
 		err := json.Unmarshal([]byte(s), res.Interface())
@@ -1,10 +1,14 @@
 package structflag_test
 
 import (
+	"bytes"
+	"encoding/json"
 	"flag"
+	"fmt"
 	"reflect"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -371,3 +375,142 @@ func TestSetFloat64ValueWithStrconv(t *testing.T) {
 		})
 	}
 }
+
+func TestSetRawMessage(t *testing.T) {
+	src := `{"b": [1, 2], "a": null}`
+	var val json.RawMessage
+	require.NoError(t, reflectValue(&val).Set(src))
+	assert.Equal(t, src, string(val))
+	assert.Equal(t, src, reflectValue(&val).String())
+	assert.Error(t, reflectValue(&val).Set("{invalid"))
+	assert.Equal(t, src, string(val))
+}
+
+func TestSetInterfaceValue(t *testing.T) {
+	var val interface{}
+	require.NoError(t, reflectValue(&val).Set(`{"a": [1, "x"]}`))
+	assert.Equal(t, map[string]interface{}{"a": []interface{}{1.0, "x"}}, val)
+	require.NoError(t, reflectValue(&val).Set("bare string"))
+	assert.Equal(t, "bare string", val)
+	require.NoError(t, reflectValue(&val).Set("null"))
+	assert.Nil(t, val)
+}
+
+func TestSetGenericMapValue(t *testing.T) {
+	var val map[string]interface{}
+	require.NoError(t, reflectValue(&val).Set(`{"a": 1, "b": {"c": true}}`))
+	assert.Equal(t, map[string]interface{}{"a": 1.0, "b": map[string]interface{}{"c": true}}, val)
+	assert.Equal(t, `{"a":1,"b":{"c":true}}`, reflectValue(&val).String())
+}
+
+func TestValueMetadata(t *testing.T) {
+	val := struct {
+		Flag   *bool `description:"flag"`
+		List   []string
+		Counts map[string]int
+		Number int
+	}{}
+	sv, err := structflag.NewStructToFlagsConverter().Convert(&val)
+	require.NoError(t, err)
+	tests := []struct {
+		name     string
+		kind     reflect.Kind
+		isSlice  bool
+		isBool   bool
+		elemType reflect.Type
+	}{
+		{"Flag", reflect.Bool, false, true, nil},
+		{"List", reflect.Slice, true, false, reflect.TypeOf("")},
+		{"Counts", reflect.Map, false, false, reflect.TypeOf(0)},
+		{"Number", reflect.Int, false, false, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := sv[tt.name]
+			assert.Equal(t, tt.kind, v.Kind())
+			assert.Equal(t, tt.isSlice, v.IsSlice())
+			assert.Equal(t, tt.isBool, v.IsBool())
+			assert.Equal(t, tt.elemType, v.ElemType())
+			assert.Equal(t, tt.name, v.Field().Name)
+		})
+	}
+	assert.Equal(t, "flag", sv["Flag"].Field().Tag.Get("description"))
+	assert.Equal(t, "", structflag.NewReflectedValue(reflect.ValueOf(&val).Elem(), "").Field().Name)
+}
+
+type level int
+
+func (thiz level) MarshalText() ([]byte, error) {
+	return []byte([]string{"debug", "info"}[thiz]), nil
+}
+
+func (thiz *level) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "debug":
+		*thiz = 0
+	case "info":
+		*thiz = 1
+	default:
+		return fmt.Errorf("unknown level %q", text)
+	}
+	return nil
+}
+
+func TestDurationValue(t *testing.T) {
+	val := 5 * time.Second
+	value := reflectValue(&val)
+	assert.Equal(t, "5s", value.String())
+	assert.Equal(t, "5s", value.(structflag.Value).Default())
+	require.NoError(t, value.Set("1m30s"))
+	assert.Equal(t, 90*time.Second, val)
+	require.NoError(t, value.Set("1000"))
+	assert.Equal(t, time.Microsecond, val)
+	assert.EqualError(t, value.Set("5 seconds"), `invalid duration "5 seconds", expected duration such as 1m30s`)
+
+	var ptr *time.Duration
+	require.NoError(t, reflectValue(&ptr).Set("2h"))
+	assert.Equal(t, 2*time.Hour, *ptr)
+	assert.Equal(t, "2h0m0s", reflectValue(&ptr).String())
+}
+
+func TestTextMarshalerValue(t *testing.T) {
+	val := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	value := reflectValue(&val)
+	assert.Equal(t, "2023-01-01T00:00:00Z", value.String())
+	require.NoError(t, value.Set("2024-02-03T04:05:06Z"))
+	assert.Equal(t, time.Date(2024, 2, 3, 4, 5, 6, 0, time.UTC), val)
+	assert.Error(t, value.Set("yesterday"))
+
+	lvl := level(1)
+	assert.Equal(t, "info", reflectValue(&lvl).String())
+	require.NoError(t, reflectValue(&lvl).Set("debug"))
+	assert.Equal(t, level(0), lvl)
+	assert.EqualError(t, reflectValue(&lvl).Set("1"), `unknown level "1"`)
+}
+
+func TestConvertTextMarshalerFields(t *testing.T) {
+	val := &struct {
+		Start   time.Time
+		End     *time.Time
+		Timeout time.Duration
+	}{Start: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)}
+	values, err := structflag.DefaultStructToFlagsConverter.Convert(val)
+	require.NoError(t, err)
+	require.Len(t, values, 3)
+	assert.Equal(t, "2023-01-01T00:00:00Z", values["Start"].Default())
+	require.NoError(t, values["Start"].Set("2024-02-03T04:05:06Z"))
+	require.NoError(t, values["End"].Set("2024-02-04T00:00:00Z"))
+	assert.Equal(t, time.Date(2024, 2, 3, 4, 5, 6, 0, time.UTC), val.Start)
+	assert.Equal(t, time.Date(2024, 2, 4, 0, 0, 0, 0, time.UTC), *val.End)
+}
+
+func TestDurationUsage(t *testing.T) {
+	val := &struct {
+		Timeout time.Duration `description:"Request timeout"`
+	}{Timeout: 5 * time.Second}
+	values, err := structflag.DefaultStructToFlagsConverter.Convert(val)
+	require.NoError(t, err)
+	var b bytes.Buffer
+	structflag.PrintDefaults(&b, values)
+	assert.Equal(t, "  -Timeout duration\n    \tRequest timeout (default 5s)\n", b.String())
+}
@@ -0,0 +1,38 @@
+package structflag
+
+import (
+	"reflect"
+	"regexp"
+)
+
+var regexpType = reflect.TypeOf((*regexp.Regexp)(nil))
+
+// regexpCodec compiles *regexp.Regexp values from patterns.
+type regexpCodec struct{}
+
+func init() {
+	RegisterCodec(regexpCodec{})
+}
+
+// Match returns true for *regexp.Regexp.
+func (regexpCodec) Match(t reflect.Type) bool {
+	return t == regexpType
+}
+
+// Decode compiles the pattern.
+func (regexpCodec) Decode(s string, val reflect.Value) error {
+	re, err := regexp.Compile(s)
+	if err != nil {
+		return err
+	}
+	val.Set(reflect.ValueOf(re))
+	return nil
+}
+
+// Encode returns the source pattern or empty string for nil.
+func (regexpCodec) Encode(val reflect.Value) (string, error) {
+	if val.IsNil() {
+		return "", nil
+	}
+	return val.Interface().(*regexp.Regexp).String(), nil
+}
@@ -0,0 +1,34 @@
+package structflag_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/surajbarkale/structflag"
+)
+
+func TestRegexpValues(t *testing.T) {
+	val := &struct {
+		Include *regexp.Regexp
+		Exclude *regexp.Regexp
+	}{Include: regexp.MustCompile(`\.go$`)}
+	values, err := structflag.DefaultStructToFlagsConverter.Convert(val)
+	require.NoError(t, err)
+	assert.Len(t, values, 2)
+	assert.Equal(t, `\.go$`, values["Include"].Default())
+	assert.Equal(t, "", values["Exclude"].String())
+
+	require.NoError(t, values["Exclude"].Set("_test"))
+	assert.True(t, val.Exclude.MatchString("a_test.go"))
+	assert.Equal(t, "_test", values["Exclude"].String())
+	assert.Error(t, values["Exclude"].Set("(unclosed"))
+	assert.Equal(t, "_test", val.Exclude.String())
+
+	require.NoError(t, values["Include"].Set("null"))
+	assert.Nil(t, val.Include)
+	values["Include"].Reset()
+	assert.Equal(t, `\.go$`, val.Include.String())
+}
@@ -0,0 +1,48 @@
+package structflag
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+)
+
+// Register defines the values in the flag set using their names and aliases.
+// Unlike flag.Var, an error is returned instead of panic if a name is already
+// defined in the flag set. Previous names of renamed values are defined with a
+// usage marking them as deprecated. If groups are given, then only the values
+// in these groups are defined, see FilterGroups.
+func Register(fs *flag.FlagSet, values map[string]Value, groups ...string) error {
+	if len(groups) > 0 {
+		values = FilterGroups(values, groups...)
+	}
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value := values[name]
+		if err := defineFlag(fs, value, name, value.Description()); err != nil {
+			return err
+		}
+		for _, alias := range value.Aliases() {
+			if err := defineFlag(fs, value, alias, fmt.Sprintf("Alias for -%s", name)); err != nil {
+				return err
+			}
+		}
+		for _, old := range value.OldNames() {
+			if err := defineFlag(fs, value, old, fmt.Sprintf("Deprecated, use -%s", name)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func defineFlag(fs *flag.FlagSet, value Value, name, usage string) error {
+	if fs.Lookup(name) != nil {
+		return fmt.Errorf("flag %q is already defined", name)
+	}
+	fs.Var(value, name, usage)
+	return nil
+}
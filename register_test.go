@@ -0,0 +1,64 @@
+package structflag_test
+
+import (
+	"flag"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/surajbarkale/structflag"
+)
+
+type aliased struct {
+	ListenAddr string `aliases:"addr, listen"`
+	Debug      bool   `aliases:"d"`
+}
+
+func TestRegisterAliases(t *testing.T) {
+	val := &aliased{}
+	sv, err := structflag.NewStructToFlagsConverter().Convert(val)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"addr", "listen"}, sv["ListenAddr"].Aliases())
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	require.NoError(t, structflag.Register(fs, sv))
+	require.NoError(t, fs.Parse([]string{"-d", "-listen", ":80"}))
+	assert.Equal(t, aliased{":80", true}, *val)
+	require.NoError(t, fs.Parse([]string{"-addr", ":90"}))
+	assert.Equal(t, ":90", val.ListenAddr)
+	assert.Equal(t, "Alias for -ListenAddr", fs.Lookup("addr").Usage)
+}
+
+func TestRegisterDuplicate(t *testing.T) {
+	val := &struct {
+		Addr   string
+		Listen string `aliases:"Addr"`
+	}{}
+	sv, err := structflag.NewStructToFlagsConverter().Convert(val)
+	require.NoError(t, err)
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	assert.Error(t, structflag.Register(fs, sv))
+}
+
+func TestParseAliases(t *testing.T) {
+	val := &aliased{}
+	p := structflag.NewParser()
+	_, err := p.Parse(val, []string{"-addr", ":80"})
+	require.NoError(t, err)
+	assert.Equal(t, ":80", val.ListenAddr)
+}
+
+func TestRegisterOldNames(t *testing.T) {
+	val := &struct {
+		ListenAddr string `wasNamed:"listen,addr"`
+	}{}
+	values, err := structflag.DefaultStructToFlagsConverter.Convert(val)
+	require.NoError(t, err)
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	require.NoError(t, structflag.Register(fs, values))
+	assert.Equal(t, "Deprecated, use -ListenAddr", fs.Lookup("listen").Usage)
+	require.NoError(t, fs.Parse([]string{"-addr", ":80"}))
+	assert.Equal(t, ":80", val.ListenAddr)
+}
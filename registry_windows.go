@@ -0,0 +1,156 @@
+//go:build windows
+
+package structflag
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"unicode/utf16"
+)
+
+// SourceRegistry is the source of values set from Windows registry.
+const SourceRegistry Source = "registry"
+
+var registryEnvPattern = regexp.MustCompile(`%[^%]+%`)
+
+// Registry sets values from a Windows registry key. Flag names are split by
+// Separator into subkeys and the value name, e.g. Server-Port flag is read from
+// Port value of Server subkey. String values are used as is, expandable strings
+// have environment variables expanded, numbers are formatted as decimal
+// integers and multi-strings are converted to JSON arrays. Keys and values which
+// do not exist are ignored.
+type Registry struct {
+	// Root is the predefined key containing Path, e.g. syscall.HKEY_CURRENT_USER.
+	Root syscall.Handle
+	// Path is the key mapped to the top level fields, e.g.
+	// `SOFTWARE\Company\Service`.
+	Path string
+	// Separator splits flag names into subkeys. It must match the WordSeparator
+	// of the converter creating the values.
+	Separator string
+}
+
+// NewRegistry returns a registry source reading the key at path under
+// HKEY_LOCAL_MACHINE and using "-" as separator. The returned instance can be
+// customized by changing fields.
+func NewRegistry(path string) *Registry {
+	return &Registry{
+		Root:      syscall.HKEY_LOCAL_MACHINE,
+		Path:      path,
+		Separator: "-",
+	}
+}
+
+// Apply sets the values which were not set by any other source.
+func (thiz *Registry) Apply(values map[string]Value) error {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value := values[name]
+		if value.Source() != SourceDefault {
+			continue
+		}
+		parts := strings.Split(name, thiz.Separator)
+		key := strings.Join(append([]string{thiz.Path}, parts[:len(parts)-1]...), `\`)
+		s, ok, err := thiz.read(key, parts[len(parts)-1])
+		if err != nil {
+			return fmt.Errorf("can not read registry value for flag %s: %v", name, err)
+		}
+		if !ok {
+			continue
+		}
+		if err := setFrom(value, s, SourceRegistry); err != nil {
+			return fmt.Errorf("invalid registry value %q for flag %s: %s", s, name, errorDetail(err))
+		}
+	}
+	return nil
+}
+
+// read returns the registry value converted to string and whether it exists.
+func (thiz *Registry) read(path, name string) (string, bool, error) {
+	subkey, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return "", false, err
+	}
+	var key syscall.Handle
+	if err := syscall.RegOpenKeyEx(thiz.Root, subkey, 0, syscall.KEY_READ, &key); err == syscall.ERROR_FILE_NOT_FOUND {
+		return "", false, nil
+	} else if err != nil {
+		return "", false, fmt.Errorf("can not open key %s: %v", path, err)
+	}
+	defer syscall.RegCloseKey(key)
+	valueName, err := syscall.UTF16PtrFromString(name)
+	if err != nil {
+		return "", false, err
+	}
+	var typ, size uint32
+	if err := syscall.RegQueryValueEx(key, valueName, nil, &typ, nil, &size); err == syscall.ERROR_FILE_NOT_FOUND {
+		return "", false, nil
+	} else if err != nil {
+		return "", false, err
+	}
+	buf := make([]byte, size)
+	if size > 0 {
+		if err := syscall.RegQueryValueEx(key, valueName, nil, &typ, &buf[0], &size); err != nil {
+			return "", false, err
+		}
+		buf = buf[:size]
+	}
+	s, err := registryString(typ, buf)
+	return s, err == nil, err
+}
+
+// registryString converts registry data of given type into a flag value.
+func registryString(typ uint32, buf []byte) (string, error) {
+	switch typ {
+	case syscall.REG_SZ:
+		return utf16String(buf), nil
+	case syscall.REG_EXPAND_SZ:
+		return registryEnvPattern.ReplaceAllStringFunc(utf16String(buf), func(s string) string {
+			if env, ok := os.LookupEnv(s[1 : len(s)-1]); ok {
+				return env
+			}
+			return s
+		}), nil
+	case syscall.REG_DWORD:
+		if len(buf) != 4 {
+			return "", fmt.Errorf("invalid DWORD value")
+		}
+		return strconv.FormatUint(uint64(binary.LittleEndian.Uint32(buf)), 10), nil
+	case syscall.REG_QWORD:
+		if len(buf) != 8 {
+			return "", fmt.Errorf("invalid QWORD value")
+		}
+		return strconv.FormatUint(binary.LittleEndian.Uint64(buf), 10), nil
+	case syscall.REG_MULTI_SZ:
+		items := []string{}
+		for _, item := range strings.Split(utf16String(buf), "\x00") {
+			if item != "" {
+				items = append(items, item)
+			}
+		}
+		encoded, err := json.Marshal(items)
+		return string(encoded), err
+	}
+	return "", fmt.Errorf("unsupported value type %d", typ)
+}
+
+// utf16String decodes little endian UTF-16 registry data. Trailing NUL
+// characters are removed.
+func utf16String(buf []byte) string {
+	u := make([]uint16, len(buf)/2)
+	for i := range u {
+		u[i] = binary.LittleEndian.Uint16(buf[2*i:])
+	}
+	return strings.TrimRight(string(utf16.Decode(u)), "\x00")
+}
@@ -0,0 +1,34 @@
+//go:build windows
+
+package structflag_test
+
+import (
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/surajbarkale/structflag"
+)
+
+func TestRegistry(t *testing.T) {
+	val := &struct {
+		ProductName               string
+		CurrentMajorVersionNumber int
+		Missing                   string
+		Other                     struct {
+			Missing string
+		}
+	}{Missing: "default"}
+	values, err := structflag.DefaultStructToFlagsConverter.Convert(val)
+	require.NoError(t, err)
+	source := structflag.NewRegistry(`SOFTWARE\Microsoft\Windows NT\CurrentVersion`)
+	source.Root = syscall.HKEY_LOCAL_MACHINE
+	require.NoError(t, source.Apply(values))
+	assert.NotEmpty(t, val.ProductName)
+	assert.NotZero(t, val.CurrentMajorVersionNumber)
+	assert.Equal(t, structflag.SourceRegistry, values["ProductName"].Source())
+	assert.Equal(t, "default", val.Missing)
+	assert.Equal(t, structflag.SourceDefault, values["Other-Missing"].Source())
+}
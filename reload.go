@@ -0,0 +1,96 @@
+package structflag
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Reloader updates a configuration structure from configuration files at
+// runtime, e.g. when a file changes or on SIGHUP. Only the fields marked as
+// dynamic by DynamicTag of the converter which changed are set, so settings
+// only used at startup are never touched and dependent subsystems are not
+// notified about values which stayed the same. Keys removed from the files do
+// not reset the values.
+type Reloader struct {
+	// Converter generates the values of the target.
+	Converter *StructToFlagsConverter
+	// Loader reads the files.
+	Loader *ConfigLoader
+	// Files are loaded in order like in ConfigLoader.LoadLayered.
+	Files []string
+	// OnChange is called with the changes applied by a reload, keyed by flag
+	// name. It is not called if nothing changed.
+	OnChange func(changes map[string]Change)
+	// OnSkip is called for changes of values which can not be updated at
+	// runtime. They are ignored otherwise.
+	OnSkip func(name string, change Change)
+}
+
+// NewReloader returns a reloader which reads the files using a new
+// ConfigLoader and uses DefaultStructToFlagsConverter. The returned instance
+// can be customized by changing fields.
+func NewReloader(files ...string) *Reloader {
+	return &Reloader{
+		Converter: DefaultStructToFlagsConverter,
+		Loader:    NewConfigLoader(),
+		Files:     files,
+	}
+}
+
+// Reload reads the files and sets the dynamic values of target which differ
+// from the files. The target is not changed if the files can not be read or any
+// value can not be set. The applied changes are returned. You must pass a
+// pointer to the value.
+func (thiz *Reloader) Reload(target interface{}) (map[string]Change, error) {
+	if v := reflect.ValueOf(target); v.Kind() != reflect.Ptr || v.IsNil() {
+		return nil, fmt.Errorf("reload target must be a non-nil pointer, got %T", target)
+	}
+	candidate := Snapshot(target)
+	// Only the changes applied to target are reported to OnSet
+	candidateValues, err := thiz.Converter.withoutOnSet().Convert(candidate)
+	if err != nil {
+		return nil, err
+	}
+	if err := thiz.Loader.loadFiles(candidateValues, thiz.Files, nil); err != nil {
+		return nil, err
+	}
+	diff, err := thiz.Converter.Diff(target, candidate)
+	if err != nil {
+		return nil, err
+	}
+	values, err := thiz.Converter.Convert(target)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(diff))
+	for name := range diff {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	changes := map[string]Change{}
+	var restore []func()
+	for _, name := range names {
+		value, change := values[name], diff[name]
+		if !isDynamic(value) {
+			if thiz.OnSkip != nil {
+				thiz.OnSkip(name, change)
+			}
+			continue
+		}
+		if r, ok := value.(restorer); ok {
+			restore = append(restore, r.save())
+		}
+		if err := setFrom(value, change.New, SourceFile); err != nil {
+			for i := len(restore) - 1; i >= 0; i-- {
+				restore[i]()
+			}
+			return nil, err
+		}
+		changes[name] = change
+	}
+	if len(changes) > 0 && thiz.OnChange != nil {
+		thiz.OnChange(changes)
+	}
+	return changes, nil
+}
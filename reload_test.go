@@ -0,0 +1,85 @@
+package structflag_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/surajbarkale/structflag"
+)
+
+type reloadOptions struct {
+	Listen   string
+	LogLevel string `dynamic:"true"`
+	Limits   struct {
+		Rate  int `dynamic:"true"`
+		Burst int `dynamic:"true"`
+	}
+}
+
+func TestReload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "structflag")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	file := writeTempFile(t, dir, "config.json", `{"Listen": ":9090", "LogLevel": "debug", "Limits": {"Rate": 10, "Burst": 5}}`)
+
+	val := &reloadOptions{Listen: ":8080", LogLevel: "info"}
+	val.Limits.Rate, val.Limits.Burst = 20, 5
+	reloader := structflag.NewReloader(file)
+	var notified map[string]structflag.Change
+	var skipped []string
+	reloader.OnChange = func(changes map[string]structflag.Change) {
+		notified = changes
+	}
+	reloader.OnSkip = func(name string, change structflag.Change) {
+		skipped = append(skipped, name)
+	}
+	changes, err := reloader.Reload(val)
+	require.NoError(t, err)
+	expected := map[string]structflag.Change{
+		"LogLevel":    {Old: "info", New: "debug"},
+		"Limits-Rate": {Old: "20", New: "10"},
+	}
+	assert.Equal(t, expected, changes)
+	assert.Equal(t, expected, notified)
+	assert.Equal(t, []string{"Listen"}, skipped)
+	assert.Equal(t, ":8080", val.Listen)
+	assert.Equal(t, "debug", val.LogLevel)
+	assert.Equal(t, 10, val.Limits.Rate)
+
+	notified = nil
+	changes, err = reloader.Reload(val)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+	assert.Nil(t, notified)
+
+	writeTempFile(t, dir, "config.json", `{"LogLevel": "warn", "Limits": {"Rate": "x"}}`)
+	_, err = reloader.Reload(val)
+	assert.Error(t, err)
+	assert.Equal(t, "debug", val.LogLevel)
+
+	_, err = reloader.Reload(*val)
+	assert.Error(t, err)
+}
+
+func TestReloadOnSet(t *testing.T) {
+	dir, err := ioutil.TempDir("", "structflag")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	file := writeTempFile(t, dir, "config.json", `{"Listen": ":9090", "LogLevel": "debug", "Limits": {"Rate": 5}}`)
+
+	reloader := structflag.NewReloader(file)
+	reloader.Converter = structflag.NewStructToFlagsConverter()
+	var logged []string
+	reloader.Converter.OnSet = func(path, oldValue, newValue string, source structflag.Source) {
+		logged = append(logged, path+" "+oldValue+" -> "+newValue+" "+string(source))
+	}
+	val := &reloadOptions{LogLevel: "info"}
+	val.Limits.Rate = 1
+	_, err = reloader.Reload(val)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Limits-Rate 1 -> 5 file", "LogLevel info -> debug file"}, logged)
+}
@@ -0,0 +1,49 @@
+package structflag
+
+import (
+	"fmt"
+	"sort"
+)
+
+// RemovedPolicy defines how the parser handles flags which were removed.
+type RemovedPolicy int
+
+const (
+	// WarnRemoved writes a warning to the parser output when a removed flag is
+	// used.
+	WarnRemoved RemovedPolicy = iota
+	// RejectRemoved returns an error when a removed flag is used.
+	RejectRemoved
+	// IgnoreRemoved accepts removed flags silently.
+	IgnoreRemoved
+)
+
+// checkRemoved applies RemovedPolicy to the values with removed tag which were
+// set from the command line.
+func (thiz *Parser) checkRemoved(values map[string]Value) error {
+	if thiz.RemovedTag == "" || thiz.RemovedPolicy == IgnoreRemoved {
+		return nil
+	}
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	messages := messagesOrDefault(thiz.Messages)
+	for _, name := range names {
+		value := values[name]
+		version, ok := value.Field().Tag.Lookup(thiz.RemovedTag)
+		if !ok || value.Source() != SourceFlag {
+			continue
+		}
+		msg := fmt.Sprintf(messages.Removed, name)
+		if version != "" {
+			msg = fmt.Sprintf(messages.RemovedIn, name, version)
+		}
+		if thiz.RemovedPolicy == RejectRemoved {
+			return fmt.Errorf("%s", msg)
+		}
+		fmt.Fprintf(thiz.Output, messages.Warning+"\n", msg)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package structflag_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/surajbarkale/structflag"
+)
+
+type removedOptions struct {
+	Listen string
+	Legacy bool `removed:"v2.0"`
+	Old    int  `removed:""`
+}
+
+func TestRemovedFlags(t *testing.T) {
+	var out bytes.Buffer
+	val := &removedOptions{}
+	_, err := newTestParser(&out).Parse(val, []string{"--Listen", ":80"})
+	require.NoError(t, err)
+	assert.Empty(t, out.String())
+
+	_, err = newTestParser(&out).Parse(val, []string{"--Legacy", "--Old", "1"})
+	require.NoError(t, err)
+	assert.True(t, val.Legacy)
+	assert.Equal(t, "warning: flag -Legacy was removed in v2.0\nwarning: flag -Old was removed\n", out.String())
+
+	out.Reset()
+	parser := newTestParser(&out)
+	parser.RemovedPolicy = structflag.RejectRemoved
+	_, err = parser.Parse(&removedOptions{}, []string{"--Legacy"})
+	assert.EqualError(t, err, "flag -Legacy was removed in v2.0")
+
+	parser = newTestParser(&out)
+	parser.RemovedPolicy = structflag.IgnoreRemoved
+	_, err = parser.Parse(&removedOptions{}, []string{"--Legacy"})
+	require.NoError(t, err)
+	assert.Empty(t, out.String())
+}
@@ -0,0 +1,141 @@
+package structflag
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Resolver fetches values referenced by URIs from external sources like Vault,
+// SSM or a remote key value store, e.g. "vault://secret/db#password". Slow
+// sources must respect cancellation and the deadline of the context.
+type Resolver interface {
+	Resolve(ctx context.Context, uri string) (string, error)
+}
+
+// ResolverFunc adapts a function to Resolver.
+type ResolverFunc func(ctx context.Context, uri string) (string, error)
+
+// Resolve calls the function.
+func (thiz ResolverFunc) Resolve(ctx context.Context, uri string) (string, error) {
+	return thiz(ctx, uri)
+}
+
+// BatchResolver is implemented by resolvers which can fetch several values in
+// one round trip. Resolve uses it for all values with the scheme of the
+// resolver.
+type BatchResolver interface {
+	Resolver
+	// ResolveBatch returns the values of the URIs keyed by URI. With an error
+	// wrapping ErrResolveFailed, the returned values are still used and the
+	// missing ones fall back to defaults.
+	ResolveBatch(ctx context.Context, uris []string) (map[string]string, error)
+}
+
+// Resolve replaces string values containing URIs with a scheme in resolvers by
+// the values returned by the resolver of the scheme. The resolved values keep
+// their sources. Values are resolved in order of flag names, with values of
+// batch resolvers resolved together after the others, and resolution stops at
+// the first error or when ctx is done. Values for which the resolver returns an
+// error wrapping ErrResolveFailed are reset to their defaults.
+func Resolve(ctx context.Context, values map[string]Value, resolvers map[string]Resolver) error {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	batches := map[string][]string{}
+	var schemes []string
+	for _, name := range names {
+		value := values[name]
+		uri := value.String()
+		scheme := schemeOf(value, uri, resolvers)
+		if scheme == "" {
+			continue
+		}
+		if _, ok := resolvers[scheme].(BatchResolver); ok {
+			if batches[scheme] == nil {
+				schemes = append(schemes, scheme)
+			}
+			batches[scheme] = append(batches[scheme], name)
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		s, err := resolvers[scheme].Resolve(ctx, uri)
+		if err := setResolved(value, name, uri, s, err, resolvers); err != nil {
+			return err
+		}
+	}
+	for _, scheme := range schemes {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		var uris []string
+		seen := map[string]bool{}
+		for _, name := range batches[scheme] {
+			if uri := values[name].String(); !seen[uri] {
+				seen[uri] = true
+				uris = append(uris, uri)
+			}
+		}
+		resolved, err := resolvers[scheme].(BatchResolver).ResolveBatch(ctx, uris)
+		for _, name := range batches[scheme] {
+			uri := values[name].String()
+			s, ok := resolved[uri]
+			resolveErr := err
+			switch {
+			case ok && errors.Is(err, ErrResolveFailed):
+				resolveErr = nil
+			case err == nil && !ok:
+				return fmt.Errorf("can not resolve %s for flag %s: no value returned", uri, name)
+			}
+			if err := setResolved(values[name], name, uri, s, resolveErr, resolvers); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// setResolved sets the value resolved from the URI or handles the error of the
+// resolver.
+func setResolved(value Value, name, uri, s string, err error, resolvers map[string]Resolver) error {
+	if errors.Is(err, ErrResolveFailed) {
+		failOpen(value, resolvers)
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("can not resolve %s for flag %s: %w", uri, name, err)
+	}
+	if err := replaceFrom(value, s, value.Source()); err != nil {
+		return fmt.Errorf("invalid value resolved from %s for flag %s: %s", uri, name, errorDetail(err))
+	}
+	return nil
+}
+
+// failOpen restores the default of a value which could not be resolved. The
+// value is set to an empty string if the default needs to be resolved too.
+func failOpen(value Value, resolvers map[string]Resolver) {
+	source := value.Source()
+	value.Reset()
+	if schemeOf(value, value.String(), resolvers) != "" {
+		replaceFrom(value, "", source)
+	}
+}
+
+// schemeOf returns the scheme of the URI in a string value if it has a
+// resolver or empty string if the value is not resolved.
+func schemeOf(value Value, uri string, resolvers map[string]Resolver) string {
+	if value.Kind() != reflect.String {
+		return ""
+	}
+	i := strings.Index(uri, "://")
+	if i <= 0 || resolvers[uri[:i]] == nil {
+		return ""
+	}
+	return uri[:i]
+}
@@ -0,0 +1,123 @@
+package structflag_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/surajbarkale/structflag"
+)
+
+type resolverOptions struct {
+	Password string `secret:"true"`
+	Token    string
+	Host     string
+	Port     int
+}
+
+var testSecrets = structflag.ResolverFunc(func(ctx context.Context, uri string) (string, error) {
+	switch uri {
+	case "vault://db#password":
+		return "hunter2", nil
+	case "vault://slow":
+		<-ctx.Done()
+		return "", ctx.Err()
+	}
+	return "", errors.New("not found")
+})
+
+func TestResolve(t *testing.T) {
+	val := &resolverOptions{Password: "vault://db#password", Token: "ssm://token", Host: "http://example.com"}
+	values, err := structflag.DefaultStructToFlagsConverter.Convert(val)
+	require.NoError(t, err)
+	require.NoError(t, structflag.Resolve(context.Background(), values, map[string]structflag.Resolver{"vault": testSecrets}))
+	assert.Equal(t, resolverOptions{Password: "hunter2", Token: "ssm://token", Host: "http://example.com"}, *val)
+	assert.Equal(t, structflag.SourceDefault, values["Password"].Source())
+
+	require.NoError(t, values["Token"].Set("vault://missing"))
+	err = structflag.Resolve(context.Background(), values, map[string]structflag.Resolver{"vault": testSecrets})
+	assert.EqualError(t, err, "can not resolve vault://missing for flag Token: not found")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = structflag.Resolve(ctx, values, map[string]structflag.Resolver{"vault": testSecrets})
+	assert.Equal(t, context.Canceled, err)
+}
+
+func TestParseContext(t *testing.T) {
+	p := newTestParser(&bytes.Buffer{})
+	p.Resolvers = map[string]structflag.Resolver{"vault": testSecrets}
+	val := &resolverOptions{}
+	_, err := p.ParseContext(context.Background(), val, []string{"-Password", "vault://db#password"})
+	require.NoError(t, err)
+	assert.Equal(t, "hunter2", val.Password)
+	assert.Equal(t, structflag.SourceFlag, p.Values()["Password"].Source())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = p.ParseContext(ctx, &resolverOptions{}, []string{"-Token", "vault://slow"})
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Error(t, p.ValidateContext(ctx, &resolverOptions{}, nil))
+}
+
+// batchSecrets resolves "vault://" URIs to their paths in one call.
+type batchSecrets struct {
+	batches [][]string
+}
+
+func (thiz *batchSecrets) Resolve(ctx context.Context, uri string) (string, error) {
+	return "", errors.New("batch expected")
+}
+
+func (thiz *batchSecrets) ResolveBatch(ctx context.Context, uris []string) (map[string]string, error) {
+	thiz.batches = append(thiz.batches, uris)
+	res := map[string]string{}
+	for _, uri := range uris {
+		if uri != "vault://missing" {
+			res[uri] = strings.TrimPrefix(uri, "vault://")
+		}
+	}
+	return res, nil
+}
+
+func TestResolveBatch(t *testing.T) {
+	val := &resolverOptions{Password: "vault://db", Token: "vault://api", Host: "vault://db"}
+	values, err := structflag.DefaultStructToFlagsConverter.Convert(val)
+	require.NoError(t, err)
+	batch := &batchSecrets{}
+	require.NoError(t, structflag.Resolve(context.Background(), values, map[string]structflag.Resolver{"vault": batch}))
+	assert.Equal(t, resolverOptions{Password: "db", Token: "api", Host: "db"}, *val)
+	assert.Equal(t, [][]string{{"vault://db", "vault://api"}}, batch.batches)
+
+	require.NoError(t, values["Token"].Set("vault://missing"))
+	err = structflag.Resolve(context.Background(), values, map[string]structflag.Resolver{"vault": batch})
+	assert.EqualError(t, err, "can not resolve vault://missing for flag Token: no value returned")
+
+	retry := structflag.NewRetryResolver(testSecrets)
+	retry.Attempts = 1
+	retry.FailOpen = true
+	val = &resolverOptions{Password: "vault://db#password", Token: "vault://missing"}
+	values, err = structflag.DefaultStructToFlagsConverter.Convert(val)
+	require.NoError(t, err)
+	require.NoError(t, structflag.Resolve(context.Background(), values, map[string]structflag.Resolver{"vault": retry}))
+	assert.Equal(t, resolverOptions{Password: "hunter2"}, *val)
+}
+
+func TestParseResolveRepeatPolicy(t *testing.T) {
+	for _, policy := range []structflag.RepeatPolicy{structflag.FirstSetWins, structflag.RejectRepeatedSet} {
+		p := newTestParser(&bytes.Buffer{})
+		p.Converter = structflag.NewStructToFlagsConverter()
+		p.Converter.RepeatPolicy = policy
+		p.Resolvers = map[string]structflag.Resolver{"vault": testSecrets}
+		val := &resolverOptions{}
+		_, err := p.Parse(val, []string{"-Password", "vault://db#password"})
+		require.NoError(t, err)
+		assert.Equal(t, "hunter2", val.Password)
+	}
+}
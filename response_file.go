@@ -0,0 +1,70 @@
+package structflag
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/fs"
+	"io/ioutil"
+	"strings"
+)
+
+// maxResponseFileDepth limits nesting of response files referring to other
+// response files.
+const maxResponseFileDepth = 10
+
+// expandResponseFiles replaces every argument of the form @file with the
+// arguments read from the file. Each non-empty line of the file is a single
+// argument and lines starting with # are ignored. Response files can refer to
+// other response files. Arguments after "--" are not expanded. Files are read
+// from fsys if it is not nil.
+func expandResponseFiles(fsys fs.FS, args []string, depth int) ([]string, error) {
+	res := make([]string, 0, len(args))
+	for i, arg := range args {
+		if arg == "--" {
+			return append(res, args[i:]...), nil
+		}
+		if len(arg) < 2 || arg[0] != '@' {
+			res = append(res, arg)
+			continue
+		}
+		if depth >= maxResponseFileDepth {
+			return nil, fmt.Errorf("response file %s is nested too deeply", arg[1:])
+		}
+		fileArgs, err := readResponseFile(fsys, arg[1:])
+		if err != nil {
+			return nil, err
+		}
+		if fileArgs, err = expandResponseFiles(fsys, fileArgs, depth+1); err != nil {
+			return nil, err
+		}
+		res = append(res, fileArgs...)
+	}
+	return res, nil
+}
+
+func readResponseFile(fsys fs.FS, name string) ([]string, error) {
+	var data []byte
+	var err error
+	if fsys != nil {
+		data, err = fs.ReadFile(fsys, name)
+	} else {
+		data, err = ioutil.ReadFile(name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("can not read response file: %v", err)
+	}
+	var res []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if trimmed := strings.TrimSpace(line); trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		res = append(res, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("can not read response file %s: %v", name, err)
+	}
+	return res, nil
+}
@@ -0,0 +1,75 @@
+package structflag_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTempFile(t *testing.T, dir, name, content string) string {
+	path := filepath.Join(dir, name)
+	require.NoError(t, ioutil.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func TestParseResponseFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "structflag")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	nested := writeTempFile(t, dir, "nested.txt", "--Count\r\n7\r\n")
+	args := writeTempFile(t, dir, "args.txt", `# generated arguments
+--Name
+name with spaces
+
+@`+nested+`
+input.txt
+`)
+	val := &options{}
+	p := newTestParser(&bytes.Buffer{})
+	p.ResponseFiles = true
+	pos, err := p.Parse(val, []string{"-Debug", "@" + args, "--", "@" + args})
+	require.NoError(t, err)
+	assert.Equal(t, options{true, "name with spaces", 7}, *val)
+	assert.Equal(t, []string{"input.txt", "@" + args}, pos)
+
+	_, err = p.Parse(val, []string{"@" + filepath.Join(dir, "missing.txt")})
+	assert.Error(t, err)
+
+	p.ResponseFiles = false
+	pos, err = p.Parse(val, []string{"@" + args})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"@" + args}, pos)
+}
+
+func TestParseRecursiveResponseFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "structflag")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "loop.txt")
+	writeTempFile(t, dir, "loop.txt", "@"+path)
+	p := newTestParser(&bytes.Buffer{})
+	p.ResponseFiles = true
+	_, err = p.Parse(&options{}, []string{"@" + path})
+	assert.Error(t, err)
+}
+
+func TestParseResponseFileFS(t *testing.T) {
+	p := newTestParser(&bytes.Buffer{})
+	p.ResponseFiles = true
+	p.FS = fstest.MapFS{
+		"args/all.txt":   {Data: []byte("-Name\nembedded\n@args/count.txt\n")},
+		"args/count.txt": {Data: []byte("-Count=3\n")},
+	}
+	val := &options{}
+	_, err := p.Parse(val, []string{"@args/all.txt"})
+	require.NoError(t, err)
+	assert.Equal(t, options{Name: "embedded", Count: 3}, *val)
+	_, err = p.Parse(val, []string{"@missing.txt"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,125 @@
+package structflag
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrResolveFailed is wrapped by errors of resolvers which fail open. Resolve
+// uses the default value instead of returning such errors.
+var ErrResolveFailed = errors.New("structflag: resolve failed")
+
+// RetryResolver calls another resolver with a timeout for each attempt and
+// retries failed attempts with exponential backoff, since backends differ in
+// how reliable they are at startup.
+type RetryResolver struct {
+	// Resolver fetches the values.
+	Resolver Resolver
+	// Attempts is the maximum number of calls. Values below 1 mean 1.
+	Attempts int
+	// Timeout limits each call if it is positive.
+	Timeout time.Duration
+	// Backoff is the delay before the first retry. It is doubled after every
+	// retry.
+	Backoff time.Duration
+	// MaxBackoff limits the delay between retries if it is positive.
+	MaxBackoff time.Duration
+	// FailOpen makes Resolve use the default value of the flag when all
+	// attempts fail instead of failing. The default value is used as is unless
+	// it is a URI handled by the same resolver, then the flag is empty.
+	FailOpen bool
+}
+
+// NewRetryResolver returns a resolver which makes 3 attempts with 10 second
+// timeout and backoff starting at 100 milliseconds up to 5 seconds and fails
+// closed. The returned instance can be customized by changing fields.
+func NewRetryResolver(resolver Resolver) *RetryResolver {
+	return &RetryResolver{
+		Resolver:   resolver,
+		Attempts:   3,
+		Timeout:    10 * time.Second,
+		Backoff:    100 * time.Millisecond,
+		MaxBackoff: 5 * time.Second,
+	}
+}
+
+// Resolve calls the resolver until it succeeds, the attempts are exhausted or
+// ctx is done. The error of the last attempt is returned, wrapping
+// ErrResolveFailed with FailOpen.
+func (thiz *RetryResolver) Resolve(ctx context.Context, uri string) (string, error) {
+	var s string
+	err := thiz.retry(ctx, func(ctx context.Context) (err error) {
+		s, err = thiz.Resolver.Resolve(ctx, uri)
+		return err
+	})
+	return s, err
+}
+
+// ResolveBatch retries the batch like Resolve if the resolver is a
+// BatchResolver and resolves the URIs one by one otherwise. With FailOpen, the
+// URIs which were resolved are returned even if others failed.
+func (thiz *RetryResolver) ResolveBatch(ctx context.Context, uris []string) (map[string]string, error) {
+	batch, ok := thiz.Resolver.(BatchResolver)
+	if !ok {
+		res := make(map[string]string, len(uris))
+		var failed error
+		for _, uri := range uris {
+			s, err := thiz.Resolve(ctx, uri)
+			if errors.Is(err, ErrResolveFailed) {
+				failed = err
+				continue
+			} else if err != nil {
+				return nil, err
+			}
+			res[uri] = s
+		}
+		return res, failed
+	}
+	var res map[string]string
+	err := thiz.retry(ctx, func(ctx context.Context) (err error) {
+		res, err = batch.ResolveBatch(ctx, uris)
+		return err
+	})
+	return res, err
+}
+
+// retry calls fn with the timeout until it succeeds, the attempts are
+// exhausted or ctx is done.
+func (thiz *RetryResolver) retry(ctx context.Context, fn func(ctx context.Context) error) error {
+	backoff := thiz.Backoff
+	var err error
+	for attempt := 0; attempt < thiz.Attempts || attempt == 0; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(backoff):
+			}
+			if backoff *= 2; thiz.MaxBackoff > 0 && backoff > thiz.MaxBackoff {
+				backoff = thiz.MaxBackoff
+			}
+		}
+		if err = thiz.attempt(ctx, fn); err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return err
+		}
+	}
+	if thiz.FailOpen {
+		return fmt.Errorf("%w: %v", ErrResolveFailed, err)
+	}
+	return err
+}
+
+// attempt calls fn once with the timeout.
+func (thiz *RetryResolver) attempt(ctx context.Context, fn func(ctx context.Context) error) error {
+	if thiz.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, thiz.Timeout)
+		defer cancel()
+	}
+	return fn(ctx)
+}
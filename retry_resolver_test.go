@@ -0,0 +1,77 @@
+package structflag_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/surajbarkale/structflag"
+)
+
+// flakyResolver fails the given number of calls before succeeding.
+type flakyResolver struct {
+	failures int
+	calls    int
+}
+
+func (thiz *flakyResolver) Resolve(ctx context.Context, uri string) (string, error) {
+	thiz.calls++
+	if thiz.calls <= thiz.failures {
+		return "", errors.New("unavailable")
+	}
+	return "secret", nil
+}
+
+func TestRetryResolver(t *testing.T) {
+	flaky := &flakyResolver{failures: 2}
+	resolver := structflag.NewRetryResolver(flaky)
+	resolver.Backoff = time.Millisecond
+	s, err := resolver.Resolve(context.Background(), "vault://x")
+	require.NoError(t, err)
+	assert.Equal(t, "secret", s)
+	assert.Equal(t, 3, flaky.calls)
+
+	flaky = &flakyResolver{failures: 5}
+	resolver.Resolver = flaky
+	_, err = resolver.Resolve(context.Background(), "vault://x")
+	assert.EqualError(t, err, "unavailable")
+	assert.Equal(t, 3, flaky.calls)
+
+	resolver.Resolver = structflag.ResolverFunc(func(ctx context.Context, uri string) (string, error) {
+		<-ctx.Done()
+		return "", ctx.Err()
+	})
+	resolver.Attempts = 1
+	resolver.Timeout = time.Millisecond
+	_, err = resolver.Resolve(context.Background(), "vault://x")
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+}
+
+func TestRetryResolverFailOpen(t *testing.T) {
+	val := &struct {
+		Password string
+		Token    string
+		Region   string
+	}{Password: "vault://db", Region: "eu"}
+	values, err := structflag.DefaultStructToFlagsConverter.Convert(val)
+	require.NoError(t, err)
+	require.NoError(t, values["Token"].Set("vault://token"))
+	require.NoError(t, values["Region"].Set("vault://region"))
+
+	resolver := structflag.NewRetryResolver(&flakyResolver{failures: 100})
+	resolver.Attempts = 2
+	resolver.Backoff = time.Millisecond
+	resolvers := map[string]structflag.Resolver{"vault": resolver}
+	assert.Error(t, structflag.Resolve(context.Background(), values, resolvers))
+
+	resolver.FailOpen = true
+	require.NoError(t, structflag.Resolve(context.Background(), values, resolvers))
+	assert.Equal(t, "", val.Password)
+	assert.Equal(t, "", val.Token)
+	assert.Equal(t, "eu", val.Region)
+	assert.Equal(t, structflag.SourceDefault, values["Region"].Source())
+}
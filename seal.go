@@ -0,0 +1,45 @@
+package structflag
+
+import "errors"
+
+// ErrSealed is returned when a sealed value is set.
+var ErrSealed = errors.New("structflag: value is sealed")
+
+// sealer is implemented by values which can reject further changes.
+type sealer interface {
+	seal()
+}
+
+// Seal makes Set return ErrSealed for all values except the ones marked as
+// dynamic by DynamicTag of the converter, e.g. to prevent accidental changes
+// after startup while still allowing hot reloadable fields to be updated. Reset
+// is not affected.
+func Seal(values map[string]Value) {
+	for _, value := range values {
+		if isDynamic(value) {
+			continue
+		}
+		if s, ok := value.(sealer); ok {
+			s.seal()
+		}
+	}
+}
+
+// DynamicValues returns the values marked as dynamic by DynamicTag of the
+// converter which can be updated at runtime, e.g. by admin endpoints or
+// configuration watchers which must not change settings only used at startup.
+func DynamicValues(values map[string]Value) map[string]Value {
+	res := map[string]Value{}
+	for name, value := range values {
+		if isDynamic(value) {
+			res[name] = value
+		}
+	}
+	return res
+}
+
+// isDynamic returns true if the value can be updated at runtime.
+func isDynamic(value Value) bool {
+	rv, ok := value.(*reflectedValue)
+	return ok && rv.dynamic
+}
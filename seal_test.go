@@ -0,0 +1,66 @@
+package structflag_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/surajbarkale/structflag"
+)
+
+func TestSeal(t *testing.T) {
+	val := &struct {
+		Port     int
+		LogLevel string `dynamic:"true"`
+	}{Port: 80}
+	values, err := structflag.DefaultStructToFlagsConverter.Convert(val)
+	require.NoError(t, err)
+	require.NoError(t, values["Port"].Set("8080"))
+	structflag.Seal(values)
+
+	err = values["Port"].Set("9090")
+	assert.True(t, errors.Is(err, structflag.ErrSealed))
+	assert.EqualError(t, err, `invalid value "9090" for flag Port: structflag: value is sealed`)
+	assert.Equal(t, 8080, val.Port)
+	assert.Equal(t, structflag.SourceFlag, values["Port"].Source())
+
+	require.NoError(t, values["LogLevel"].Set("debug"))
+	assert.Equal(t, "debug", val.LogLevel)
+
+	values["Port"].Reset()
+	assert.Equal(t, 80, val.Port)
+}
+
+func TestDynamicValues(t *testing.T) {
+	val := &struct {
+		Listen   string
+		LogLevel string `dynamic:"true"`
+		Limits   struct {
+			Rate  int `dynamic:"1"`
+			Burst int `dynamic:"false"`
+		}
+	}{}
+	values, err := structflag.DefaultStructToFlagsConverter.Convert(val)
+	require.NoError(t, err)
+	dynamic := structflag.DynamicValues(values)
+	assert.Len(t, dynamic, 2)
+	assert.Equal(t, values["LogLevel"], dynamic["LogLevel"])
+	assert.Equal(t, values["Limits-Rate"], dynamic["Limits-Rate"])
+}
+
+func TestDynamicTag(t *testing.T) {
+	val := &struct {
+		Listen   string `dynamic:"true"`
+		LogLevel string `reloadable:"true"`
+	}{}
+	converter := structflag.NewStructToFlagsConverter()
+	converter.DynamicTag = "reloadable"
+	values, err := converter.Convert(val)
+	require.NoError(t, err)
+	structflag.Seal(values)
+	assert.True(t, errors.Is(values["Listen"].Set("x"), structflag.ErrSealed))
+	require.NoError(t, values["LogLevel"].Set("debug"))
+	assert.Equal(t, "debug", val.LogLevel)
+}
@@ -0,0 +1,62 @@
+package structflag
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// splitShellWords splits s into words using quoting rules of POSIX shell. Words
+// are separated by white space, single quotes preserve everything up to the
+// closing quote, double quotes allow escaping \, ", $ and ` with a backslash
+// and a backslash outside quotes escapes the following character. Variables and
+// other expansions are not supported.
+func splitShellWords(s string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	runes := []rune(s)
+	for i := 0; i < len(runes); i++ {
+		c := runes[i]
+		switch {
+		case unicode.IsSpace(c):
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+			continue
+		case c == '\\':
+			if i+1 < len(runes) {
+				i++
+				word.WriteRune(runes[i])
+			}
+		case c == '\'':
+			i++
+			for ; i < len(runes) && runes[i] != '\''; i++ {
+				word.WriteRune(runes[i])
+			}
+			if i >= len(runes) {
+				return nil, fmt.Errorf("unterminated single quote in %q", s)
+			}
+		case c == '"':
+			i++
+			for ; i < len(runes) && runes[i] != '"'; i++ {
+				if runes[i] == '\\' && i+1 < len(runes) && strings.ContainsRune("\\\"$`", runes[i+1]) {
+					i++
+				}
+				word.WriteRune(runes[i])
+			}
+			if i >= len(runes) {
+				return nil, fmt.Errorf("unterminated double quote in %q", s)
+			}
+		default:
+			word.WriteRune(c)
+		}
+		inWord = true
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
@@ -0,0 +1,63 @@
+package structflag_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseArgsEnv(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      string
+		expected options
+	}{
+		{"plain", "--Debug --Name abc", options{true, "abc", 0}},
+		{"single quotes", "--Name 'a b  \"c\"'", options{false, `a b  "c"`, 0}},
+		{"double quotes", `--Name "a \"b\" \$c \d"`, options{false, `a "b" $c \d`, 0}},
+		{"backslash", `--Name a\ b\'c`, options{false, "a b'c", 0}},
+		{"joined quotes", `--Name=a'b c'"d e"`, options{false, "ab cd e", 0}},
+		{"empty word", "--Name '' --Count\t3", options{false, "", 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			val := &options{}
+			p := newTestParser(&bytes.Buffer{})
+			p.ArgsEnv = "MYAPP_OPTS"
+			p.LookupEnv = func(key string) (string, bool) {
+				assert.Equal(t, "MYAPP_OPTS", key)
+				return tt.env, true
+			}
+			_, err := p.Parse(val, nil)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, *val)
+		})
+	}
+}
+
+func TestParseArgsEnvPrecedesArgs(t *testing.T) {
+	val := &options{}
+	p := newTestParser(&bytes.Buffer{})
+	p.ArgsEnv = "MYAPP_OPTS"
+	p.LookupEnv = func(key string) (string, bool) {
+		return "--Name env --Count 1 pos1", true
+	}
+	pos, err := p.Parse(val, []string{"--Name", "arg", "pos2"})
+	require.NoError(t, err)
+	assert.Equal(t, options{false, "arg", 1}, *val)
+	assert.Equal(t, []string{"pos1", "pos2"}, pos)
+}
+
+func TestParseArgsEnvErrors(t *testing.T) {
+	for _, env := range []string{`--Name 'abc`, `--Name "abc`, `--Name "abc\"`} {
+		p := newTestParser(&bytes.Buffer{})
+		p.ArgsEnv = "MYAPP_OPTS"
+		p.LookupEnv = func(key string) (string, bool) {
+			return env, true
+		}
+		_, err := p.Parse(&options{}, nil)
+		assert.Error(t, err, env)
+	}
+}
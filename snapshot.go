@@ -0,0 +1,16 @@
+package structflag
+
+import (
+	"reflect"
+)
+
+// Snapshot returns a deep copy of target with the same type. Pointers, slices
+// and maps in the copy do not share memory with target, so the copy is not
+// affected by later changes made through flag values. Unexported struct fields
+// are copied without following pointers.
+func Snapshot(target interface{}) interface{} {
+	if target == nil {
+		return nil
+	}
+	return deepCopy(reflect.ValueOf(target)).Interface()
+}
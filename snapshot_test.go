@@ -0,0 +1,39 @@
+package structflag_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/surajbarkale/structflag"
+)
+
+func TestSnapshot(t *testing.T) {
+	i := 4
+	val := &param{
+		Nested:    nested{Int: 1, IntPtr: &i},
+		NestedPtr: &nested{Float: 1.5},
+		IntArray:  []int{1, 2},
+	}
+	snap := structflag.Snapshot(val).(*param)
+	assert.Equal(t, val, snap)
+	assert.False(t, val == snap)
+
+	sv, err := structflag.NewStructToFlagsConverter().Convert(val)
+	require.NoError(t, err)
+	require.NoError(t, sv["Nested-IntPtr"].Set("5"))
+	require.NoError(t, sv["NestedPtr-Float"].Set("2"))
+	val.IntArray[0] = 7
+	assert.Equal(t, 4, *snap.Nested.IntPtr)
+	assert.Equal(t, float32(1.5), snap.NestedPtr.Float)
+	assert.Equal(t, []int{1, 2}, snap.IntArray)
+}
+
+func TestSnapshotOfValue(t *testing.T) {
+	val := map[string][]int{"a": {1}}
+	snap := structflag.Snapshot(val).(map[string][]int)
+	val["a"][0] = 2
+	assert.Equal(t, map[string][]int{"a": {1}}, snap)
+	assert.Nil(t, structflag.Snapshot(nil))
+}
@@ -0,0 +1,84 @@
+package structflag
+
+// Source identifies where the current value of a Value came from.
+type Source string
+
+const (
+	// SourceDefault is the source of values which were not set.
+	SourceDefault Source = "default"
+	// SourceFlag is the source of values set using Set method, e.g. from
+	// command line flags.
+	SourceFlag Source = "flag"
+	// SourceAPI is the source of values set using ConfigHandler.
+	SourceAPI Source = "api"
+)
+
+// DefaultSourcePriority lists the sources applied by Parser from the lowest to
+// the highest priority.
+var DefaultSourcePriority = []Source{SourceDefault, SourceGenerated, SourceDerived, SourceProfile, SourceDownwardAPI, SourceFile, SourceEnv, SourceFlag}
+
+// canSet returns a function reporting whether a value can be set from source
+// according to SourcePriority. Values which were not set can always be set.
+func (thiz *Parser) canSet(source Source) func(Value) bool {
+	priority := thiz.SourcePriority
+	if priority == nil {
+		priority = DefaultSourcePriority
+	}
+	rank := func(s Source) int {
+		for i, p := range priority {
+			if p == s {
+				return i
+			}
+		}
+		return -1
+	}
+	return func(value Value) bool {
+		current := value.Source()
+		if current == SourceDefault {
+			return true
+		}
+		r := rank(current)
+		return r >= 0 && rank(source) > r
+	}
+}
+
+// isDefault returns true for values which were not set by any source.
+func isDefault(value Value) bool {
+	return value.Source() == SourceDefault
+}
+
+// sourceSetter is implemented by values which record the source of the value.
+type sourceSetter interface {
+	setFrom(s string, source Source) error
+	replaceFrom(s string, source Source) error
+}
+
+// SetFrom updates the value like Set but records source as the source of the
+// value, e.g. for values set by generated code. Values which do not record
+// their source are updated using Set.
+func SetFrom(value Value, s string, source Source) error {
+	return setFrom(value, s, source)
+}
+
+// setFrom updates the value and records the source if the value supports it.
+func setFrom(value Value, s string, source Source) error {
+	if setter, ok := value.(sourceSetter); ok {
+		return setter.setFrom(s, source)
+	}
+	return value.Set(s)
+}
+
+// replaceFrom is like setFrom but ignores RepeatPolicy, for values replaced by
+// the parser, e.g. by sources with higher priority or by expanding templates.
+func replaceFrom(value Value, s string, source Source) error {
+	if setter, ok := value.(sourceSetter); ok {
+		return setter.replaceFrom(s, source)
+	}
+	return value.Set(s)
+}
+
+// restorer is implemented by values which can save their state.
+type restorer interface {
+	// save returns a function which restores the current state of the value.
+	save() func()
+}
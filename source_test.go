@@ -0,0 +1,84 @@
+package structflag_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/surajbarkale/structflag"
+)
+
+type priorityOptions struct {
+	Host    string
+	Port    int
+	Workers int
+	Debug   bool
+}
+
+func TestSourcePriority(t *testing.T) {
+	dir, err := ioutil.TempDir("", "structflag")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	file := writeTempFile(t, dir, "config.json", `{"Host": "file", "Port": 3, "Workers": 3}`)
+	parse := func(priority []structflag.Source) (*priorityOptions, map[string]structflag.Value) {
+		val := &priorityOptions{}
+		p := newTestParser(&bytes.Buffer{})
+		p.SourcePriority = priority
+		p.Env = structflag.NewEnvLoader("APP_")
+		p.Env.LookupEnv = fakeEnv(map[string]string{"APP_HOST": "env", "APP_PORT": "2"})
+		p.ConfigLoader = structflag.NewConfigLoader()
+		p.ConfigFlag = "config"
+		p.Profile = "dev"
+		p.Profiles = map[string]map[string]string{"dev": {"Debug": "true", "Host": "profile"}}
+		_, err := p.Parse(val, []string{"-Host", "flag", "-config", file})
+		require.NoError(t, err)
+		return val, p.Values()
+	}
+
+	val, values := parse(nil)
+	assert.Equal(t, priorityOptions{Host: "flag", Port: 2, Workers: 3, Debug: true}, *val)
+	assert.Equal(t, structflag.SourceEnv, values["Port"].Source())
+
+	val, values = parse([]structflag.Source{structflag.SourceDefault, structflag.SourceProfile, structflag.SourceFlag, structflag.SourceEnv, structflag.SourceFile})
+	assert.Equal(t, priorityOptions{Host: "file", Port: 3, Workers: 3, Debug: true}, *val)
+	assert.Equal(t, structflag.SourceFile, values["Host"].Source())
+
+	val, _ = parse([]structflag.Source{structflag.SourceFlag, structflag.SourceEnv, structflag.SourceProfile})
+	assert.Equal(t, priorityOptions{Host: "profile", Port: 2, Workers: 3, Debug: true}, *val)
+}
+
+func TestSourcePriorityRepeatPolicy(t *testing.T) {
+	dir, err := ioutil.TempDir("", "structflag")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	file := writeTempFile(t, dir, "config.json", `{"Port": 3}`)
+	for _, policy := range []structflag.RepeatPolicy{structflag.FirstSetWins, structflag.RejectRepeatedSet} {
+		val := &priorityOptions{}
+		p := newTestParser(&bytes.Buffer{})
+		p.Converter = structflag.NewStructToFlagsConverter()
+		p.Converter.RepeatPolicy = policy
+		p.SourcePriority = []structflag.Source{structflag.SourceDefault, structflag.SourceFlag, structflag.SourceFile, structflag.SourceEnv}
+		p.Env = structflag.NewEnvLoader("APP_")
+		p.Env.LookupEnv = fakeEnv(map[string]string{"APP_HOST": "env"})
+		p.ConfigLoader = structflag.NewConfigLoader()
+		p.ConfigFlag = "config"
+		_, err := p.Parse(val, []string{"-Host", "flag", "-Port", "1", "-config", file})
+		require.NoError(t, err)
+		assert.Equal(t, priorityOptions{Host: "env", Port: 3}, *val)
+	}
+}
+
+func TestSetFrom(t *testing.T) {
+	val := &priorityOptions{}
+	values, err := structflag.DefaultStructToFlagsConverter.Convert(val)
+	require.NoError(t, err)
+	require.NoError(t, structflag.SetFrom(values["Port"], "5", structflag.SourceGenerated))
+	assert.Equal(t, 5, val.Port)
+	assert.Equal(t, structflag.SourceGenerated, values["Port"].Source())
+	assert.Error(t, structflag.SetFrom(values["Port"], "x", structflag.SourceGenerated))
+	assert.Equal(t, 5, val.Port)
+}
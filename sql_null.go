@@ -0,0 +1,53 @@
+package structflag
+
+import (
+	"encoding"
+	"reflect"
+)
+
+// sqlNullCodec converts database/sql null wrappers, e.g. sql.NullString or
+// sql.Null[T]. Setting a value marks it valid and invalid values are shown as
+// empty strings.
+type sqlNullCodec struct{}
+
+func init() {
+	RegisterCodec(sqlNullCodec{})
+}
+
+// Match returns true for structs from database/sql with a value field followed
+// by Valid field.
+func (sqlNullCodec) Match(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct || t.PkgPath() != "database/sql" || t.NumField() != 2 {
+		return false
+	}
+	valid := t.Field(1)
+	return valid.Name == "Valid" && valid.Type.Kind() == reflect.Bool
+}
+
+// Decode stores the parsed value and marks it valid.
+func (sqlNullCodec) Decode(s string, val reflect.Value) error {
+	inner := reflect.New(val.Field(0).Type()).Elem()
+	// sql.NullTime holds time.Time which is parsed from text
+	if u, ok := inner.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		if err := u.UnmarshalText([]byte(s)); err != nil {
+			return err
+		}
+	} else if err := decodeString(s, inner); err != nil {
+		return err
+	}
+	val.Field(0).Set(inner)
+	val.Field(1).SetBool(true)
+	return nil
+}
+
+// Encode returns empty string for invalid values.
+func (sqlNullCodec) Encode(val reflect.Value) (string, error) {
+	if !val.Field(1).Bool() {
+		return "", nil
+	}
+	if m, ok := val.Field(0).Interface().(encoding.TextMarshaler); ok {
+		text, err := m.MarshalText()
+		return string(text), err
+	}
+	return encodeString(val.Field(0)), nil
+}
@@ -0,0 +1,49 @@
+package structflag_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/surajbarkale/structflag"
+)
+
+func TestSQLNullValues(t *testing.T) {
+	val := &struct {
+		Name    sql.NullString
+		Count   sql.NullInt64
+		Enabled sql.NullBool
+		Ratio   *sql.NullFloat64
+		Since   sql.NullTime
+		Port    sql.Null[int]
+	}{Count: sql.NullInt64{Int64: 3, Valid: true}}
+	values, err := structflag.DefaultStructToFlagsConverter.Convert(val)
+	require.NoError(t, err)
+	assert.Len(t, values, 6)
+	assert.Equal(t, "", values["Name"].String())
+	assert.Equal(t, "", values["Name"].Default())
+	assert.Equal(t, "3", values["Count"].Default())
+
+	require.NoError(t, values["Name"].Set(""))
+	assert.Equal(t, sql.NullString{String: "", Valid: true}, val.Name)
+	require.NoError(t, values["Enabled"].Set("false"))
+	assert.Equal(t, sql.NullBool{Bool: false, Valid: true}, val.Enabled)
+	assert.Equal(t, "false", values["Enabled"].String())
+	require.NoError(t, values["Ratio"].Set("0.5"))
+	assert.Equal(t, &sql.NullFloat64{Float64: 0.5, Valid: true}, val.Ratio)
+	require.NoError(t, values["Since"].Set("2020-01-02T03:04:05Z"))
+	assert.Equal(t, time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC), val.Since.Time)
+	assert.Equal(t, "2020-01-02T03:04:05Z", values["Since"].String())
+	require.NoError(t, values["Port"].Set("80"))
+	assert.Equal(t, sql.Null[int]{V: 80, Valid: true}, val.Port)
+
+	assert.Error(t, values["Count"].Set("x"))
+	assert.Equal(t, int64(3), val.Count.Int64)
+
+	values["Count"].Reset()
+	values["Name"].Reset()
+	assert.False(t, val.Name.Valid)
+}
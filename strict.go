@@ -0,0 +1,46 @@
+package structflag
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// checkSupported returns an error if values of type t can not be converted to
+// and from strings.
+func checkSupported(t reflect.Type) error {
+	return checkSupportedType(t, map[reflect.Type]bool{})
+}
+
+func checkSupportedType(t reflect.Type, visited map[reflect.Type]bool) error {
+	if visited[t] || isCodecType(t) {
+		return nil
+	}
+	visited[t] = true
+	switch t.Kind() {
+	case reflect.Chan, reflect.Func, reflect.UnsafePointer, reflect.Complex64, reflect.Complex128:
+		return fmt.Errorf("type %s is not supported", t)
+	case reflect.Interface:
+		if t.NumMethod() != 0 {
+			return fmt.Errorf("interface type %s is not supported", t)
+		}
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		return checkSupportedType(t.Elem(), visited)
+	case reflect.Map:
+		if err := checkSupportedType(t.Key(), visited); err != nil {
+			return err
+		}
+		return checkSupportedType(t.Elem(), visited)
+	case reflect.Struct:
+		// Nested structs in complex values are converted using encoding/json
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if field.PkgPath != "" || field.Tag.Get("json") == "-" {
+				continue
+			}
+			if err := checkSupportedType(field.Type, visited); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
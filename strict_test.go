@@ -0,0 +1,94 @@
+package structflag_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/surajbarkale/structflag"
+)
+
+type unsupported struct {
+	Name     string
+	Events   chan int
+	Callback func()
+	Writer   fmt.Stringer
+	Raw      unsafe.Pointer
+	Complex  complex128
+	Handlers map[string]func()
+	Nested   struct {
+		Count int
+		Done  *chan bool
+	}
+	Items []struct {
+		Ignored func() `json:"-"`
+		Value   int
+	}
+}
+
+func TestUnsupportedFieldsAreSkipped(t *testing.T) {
+	values, err := structflag.NewStructToFlagsConverter().Convert(&unsupported{})
+	require.NoError(t, err)
+	var names []string
+	for name := range values {
+		names = append(names, name)
+	}
+	assert.ElementsMatch(t, []string{"Name", "Nested-Count", "Items"}, names)
+}
+
+func TestStrict(t *testing.T) {
+	converter := structflag.NewStructToFlagsConverter()
+	converter.Strict = true
+	_, err := converter.Convert(&unsupported{})
+	assert.EqualError(t, err, "field Events can not be converted: type chan int is not supported")
+
+	_, err = converter.Convert(&struct {
+		Writer fmt.Stringer
+	}{})
+	assert.EqualError(t, err, "field Writer can not be converted: interface type fmt.Stringer is not supported")
+
+	_, err = converter.Convert(&struct {
+		Handlers map[string]func()
+	}{})
+	assert.EqualError(t, err, "field Handlers can not be converted: type func() is not supported")
+
+	_, err = converter.Convert(&struct {
+		Name  string
+		Any   interface{}
+		Items []struct{ Value int }
+		Point point
+	}{})
+	assert.NoError(t, err)
+}
+
+func TestOnSkip(t *testing.T) {
+	converter := structflag.NewStructToFlagsConverter()
+	converter.NameTag = "json"
+	converter.FieldFilter = func(path string, field reflect.StructField) bool {
+		return path != "Nested-Count"
+	}
+	skipped := map[string]string{}
+	converter.OnSkip = func(path string, reason error) {
+		skipped[path] = reason.Error()
+	}
+	values, err := converter.Convert(&struct {
+		Name     string
+		Password string `json:"-"`
+		Events   chan int
+		Nested   struct {
+			Count int
+		}
+		private int
+	}{})
+	require.NoError(t, err)
+	assert.Len(t, values, 1)
+	assert.Equal(t, map[string]string{
+		"Password":     "excluded by json struct tag",
+		"Events":       "type chan int is not supported",
+		"Nested-Count": "excluded by field filter",
+	}, skipped)
+}
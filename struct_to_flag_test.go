@@ -1,10 +1,15 @@
 package structflag_test
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
 	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/surajbarkale/structflag"
 )
@@ -27,7 +32,8 @@ type param struct {
 func TestDefaultStructKeys(t *testing.T) {
 	val := &param{}
 	c := structflag.NewStructToFlagsConverter()
-	sv := c.Convert(val)
+	sv, err := c.Convert(val)
+	require.NoError(t, err)
 	expKeys := []string{
 		"Nested-Int",
 		"Nested-IntPtr",
@@ -52,7 +58,8 @@ func TestCustomSeparator(t *testing.T) {
 	val := &param{}
 	c := structflag.NewStructToFlagsConverter()
 	c.WordSeparator = "."
-	sv := c.Convert(val)
+	sv, err := c.Convert(val)
+	require.NoError(t, err)
 	expKeys := []string{
 		"Nested.Int",
 		"Nested.IntPtr",
@@ -77,7 +84,8 @@ func TestCustomNameFunction(t *testing.T) {
 	val := &param{}
 	c := structflag.NewStructToFlagsConverter()
 	c.NameConverterFunc = strings.ToUpper
-	sv := c.Convert(val)
+	sv, err := c.Convert(val)
+	require.NoError(t, err)
 	expKeys := []string{
 		"NESTED-INT",
 		"NESTED-INTPTR",
@@ -105,8 +113,343 @@ func TestDescriptionTag(t *testing.T) {
 	}{}
 	c := structflag.NewStructToFlagsConverter()
 	c.DescriptionTag = "usage"
-	sv := c.Convert(&val)
+	sv, err := c.Convert(&val)
+	require.NoError(t, err)
 	assert := assert.New(t)
 	assert.Equal("The first parameter", sv["Param1"].Description())
 	assert.Equal("Input string", sv["Input"].Description())
 }
+
+func TestNullLiteralResetsPointers(t *testing.T) {
+	i := 5
+	val := struct {
+		IntPtr *int
+		Map    map[string]int
+		Slice  []string
+	}{&i, map[string]int{"a": 1}, []string{"x"}}
+	c := structflag.NewStructToFlagsConverter()
+	sv, err := c.Convert(&val)
+	require.NoError(t, err)
+	assert := assert.New(t)
+	for _, name := range []string{"IntPtr", "Map", "Slice"} {
+		assert.NoError(sv[name].Set("null"), name)
+	}
+	assert.Nil(val.IntPtr)
+	assert.Nil(val.Map)
+	assert.Nil(val.Slice)
+}
+
+func TestCustomNullLiteral(t *testing.T) {
+	i := 5
+	val := struct {
+		IntPtr *int
+	}{&i}
+	c := structflag.NewStructToFlagsConverter()
+	c.NullLiteral = "<nil>"
+	sv, err := c.Convert(&val)
+	require.NoError(t, err)
+	assert := assert.New(t)
+	assert.Error(sv["IntPtr"].Set("null"))
+	assert.NoError(sv["IntPtr"].Set("<nil>"))
+	assert.Nil(val.IntPtr)
+}
+
+func TestResetAll(t *testing.T) {
+	i := 5
+	val := &param{
+		Nested:    nested{Int: 3, IntPtr: &i},
+		String:    "abc",
+		IntArray:  []int{1, 2},
+		NestedPtr: &nested{Float: 1.5},
+	}
+	sv, err := structflag.NewStructToFlagsConverter().Convert(val)
+	require.NoError(t, err)
+	require.NoError(t, sv["Nested-Int"].Set("10"))
+	require.NoError(t, sv["Nested-IntPtr"].Set("11"))
+	require.NoError(t, sv["String"].Set("xyz"))
+	require.NoError(t, sv["StringPtr"].Set("str"))
+	require.NoError(t, sv["IntArray"].Set("[7]"))
+	require.NoError(t, sv["NestedPtr-Float"].Set("2.5"))
+	structflag.ResetAll(sv)
+	assert := assert.New(t)
+	assert.Equal(3, val.Nested.Int)
+	assert.True(&i == val.Nested.IntPtr)
+	assert.Equal(5, i)
+	assert.Equal("abc", val.String)
+	assert.Nil(val.StringPtr)
+	assert.Equal([]int{1, 2}, val.IntArray)
+	assert.Equal(float32(1.5), val.NestedPtr.Float)
+}
+
+func TestResetDoesNotShareState(t *testing.T) {
+	val := struct {
+		Map map[string]int
+	}{map[string]int{"a": 1}}
+	sv, err := structflag.NewStructToFlagsConverter().Convert(&val)
+	require.NoError(t, err)
+	val.Map["a"] = 2
+	sv["Map"].Reset()
+	assert.Equal(t, map[string]int{"a": 1}, val.Map)
+	val.Map["a"] = 3
+	sv["Map"].Reset()
+	assert.Equal(t, map[string]int{"a": 1}, val.Map)
+}
+
+func TestReservedNames(t *testing.T) {
+	val := &struct {
+		Help bool
+		H    bool
+	}{}
+	c := structflag.NewStructToFlagsConverter()
+	c.NameConverterFunc = strings.ToLower
+	_, err := c.Convert(val)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `"help" is reserved`)
+
+	c.ReservedNames = []string{"help"}
+	_, err = c.Convert(&struct{ H bool }{})
+	assert.NoError(t, err)
+
+	c.ReservedNames = nil
+	sv, err := c.Convert(val)
+	require.NoError(t, err)
+	assert.Len(t, sv, 2)
+}
+
+func TestInvalidFlagNames(t *testing.T) {
+	val := &struct {
+		Name  string
+		Other struct {
+			Name string
+		}
+	}{}
+	tests := []struct {
+		name      string
+		separator string
+		converter func(string) string
+	}{
+		{"leading dash", "-", func(s string) string { return "-" + s }},
+		{"equals", "=", func(s string) string { return s }},
+		{"empty", "", func(s string) string { return "" }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := structflag.NewStructToFlagsConverter()
+			c.WordSeparator = tt.separator
+			c.NameConverterFunc = tt.converter
+			_, err := c.Convert(val)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestFieldFilter(t *testing.T) {
+	val := &param{}
+	c := structflag.NewStructToFlagsConverter()
+	var visited []string
+	c.FieldFilter = func(path string, field reflect.StructField) bool {
+		visited = append(visited, path)
+		return !strings.HasPrefix(path, "NestedPtr") && field.Name != "StringPtr"
+	}
+	sv, err := c.Convert(val)
+	require.NoError(t, err)
+	expKeys := []string{
+		"Nested-Int",
+		"Nested-IntPtr",
+		"Nested-Float",
+		"Nested-FloatPtr",
+		"String",
+		"IntArray",
+	}
+	assert := assert.New(t)
+	assert.Equal(len(expKeys), len(sv))
+	for _, k := range expKeys {
+		assert.Contains(sv, k)
+	}
+	assert.Nil(val.NestedPtr)
+	assert.Contains(visited, "NestedPtr")
+	assert.NotContains(visited, "NestedPtr-Int")
+}
+
+func TestNameTag(t *testing.T) {
+	val := &struct {
+		ListenAddr string `json:"listen_addr,omitempty"`
+		Timeout    int    `json:",omitempty"`
+		Internal   string `json:"-"`
+		Untagged   bool
+		Database   struct {
+			URL string `json:"url"`
+		} `json:"db"`
+	}{}
+	c := structflag.NewStructToFlagsConverter()
+	c.NameTag = "json"
+	c.WordSeparator = "."
+	sv, err := c.Convert(val)
+	require.NoError(t, err)
+	expKeys := []string{
+		"listen_addr",
+		"Timeout",
+		"Untagged",
+		"db.url",
+	}
+	assert := assert.New(t)
+	assert.Equal(len(expKeys), len(sv))
+	for _, k := range expKeys {
+		assert.Contains(sv, k)
+	}
+}
+
+func TestPrefix(t *testing.T) {
+	c := structflag.NewStructToFlagsConverter()
+	c.Prefix = "app."
+	sv, err := c.Convert(&param{})
+	require.NoError(t, err)
+	assert.Len(t, sv, 11)
+	assert.Contains(t, sv, "app.Nested-Int")
+	assert.Contains(t, sv, "app.String")
+}
+
+func TestConvertAll(t *testing.T) {
+	db := &struct {
+		URL     string
+		MaxConn int
+	}{}
+	http := &struct {
+		Addr string
+	}{}
+	c := structflag.NewStructToFlagsConverter()
+	sv, err := c.ConvertAll(map[string]interface{}{"db": db, "http": http})
+	require.NoError(t, err)
+	assert.Len(t, sv, 3)
+	require.Contains(t, sv, "db-URL")
+	require.Contains(t, sv, "db-MaxConn")
+	require.Contains(t, sv, "http-Addr")
+	require.NoError(t, sv["http-Addr"].Set(":80"))
+	assert.Equal(t, ":80", http.Addr)
+}
+
+func TestConvertAllCollision(t *testing.T) {
+	c := structflag.NewStructToFlagsConverter()
+	c.NameConverterFunc = strings.ToLower
+	_, err := c.ConvertAll(map[string]interface{}{
+		"a":   &struct{ B struct{ C string } }{},
+		"a-b": &struct{ C string }{},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `"a-b-c"`)
+}
+
+func TestCopyOnGet(t *testing.T) {
+	i := 5
+	val := &struct {
+		IntPtr *int
+		List   []int
+		Map    map[string]int
+	}{&i, []int{1}, map[string]int{"a": 1}}
+	c := structflag.NewStructToFlagsConverter()
+	c.CopyOnGet = true
+	sv, err := c.Convert(val)
+	require.NoError(t, err)
+	*sv["IntPtr"].Get().(*int) = 6
+	sv["List"].Get().([]int)[0] = 2
+	sv["Map"].Get().(map[string]int)["a"] = 2
+	assert := assert.New(t)
+	assert.Equal(5, i)
+	assert.Equal([]int{1}, val.List)
+	assert.Equal(map[string]int{"a": 1}, val.Map)
+	assert.Equal([]int{1}, sv["List"].Get())
+
+	c.CopyOnGet = false
+	sv, err = c.Convert(val)
+	require.NoError(t, err)
+	*sv["IntPtr"].Get().(*int) = 6
+	assert.Equal(6, i)
+}
+
+func TestRepeatPolicy(t *testing.T) {
+	type repeated struct {
+		Port int
+		List []int
+	}
+	tests := []struct {
+		name   string
+		policy structflag.RepeatPolicy
+		port   int
+		err    bool
+	}{
+		{"last", structflag.LastSetWins, 2, false},
+		{"first", structflag.FirstSetWins, 1, false},
+		{"reject", structflag.RejectRepeatedSet, 1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			val := &repeated{}
+			c := structflag.NewStructToFlagsConverter()
+			c.RepeatPolicy = tt.policy
+			sv, err := c.Convert(val)
+			require.NoError(t, err)
+			require.NoError(t, sv["Port"].Set("1"))
+			if tt.err {
+				err := sv["Port"].Set("2")
+				var valueErr *structflag.ValueError
+				require.True(t, errors.As(err, &valueErr))
+				assert.True(t, errors.Is(err, structflag.ErrRepeatedSet))
+				assert.EqualError(t, err, `invalid value "2" for flag Port: value can only be set once`)
+			} else {
+				assert.NoError(t, sv["Port"].Set("2"))
+			}
+			assert.Equal(t, tt.port, val.Port)
+			require.NoError(t, sv["List"].Set("[1]"))
+			require.NoError(t, sv["List"].Set("[2]"))
+			assert.Equal(t, []int{2}, val.List)
+
+			sv["Port"].Reset()
+			require.NoError(t, sv["Port"].Set("3"))
+			assert.Equal(t, 3, val.Port)
+		})
+	}
+}
+
+func TestUnmarshal(t *testing.T) {
+	converter := structflag.NewStructToFlagsConverter()
+	var calls []string
+	converter.Unmarshal = func(data []byte, v interface{}) error {
+		calls = append(calls, string(data))
+		return json.Unmarshal([]byte(strings.Replace(string(data), "'", `"`, -1)), v)
+	}
+	val := &struct {
+		Names []string
+		Point *point
+		Count int
+	}{}
+	values, err := converter.Convert(val)
+	require.NoError(t, err)
+	require.NoError(t, values["Names"].Set("['a']"))
+	require.NoError(t, values["Point"].Set("1:2"))
+	require.NoError(t, values["Count"].Set("3"))
+	assert.Equal(t, []string{"a"}, val.Names)
+	assert.Equal(t, []string{"['a']"}, calls)
+}
+
+func TestOnSet(t *testing.T) {
+	val := &struct {
+		Port     int
+		Password string `secret:"true"`
+	}{Port: 80}
+	var audit []string
+	c := structflag.NewStructToFlagsConverter()
+	c.OnSet = func(path, oldValue, newValue string, source structflag.Source) {
+		audit = append(audit, fmt.Sprintf("%s %s: %s -> %s", source, path, oldValue, newValue))
+	}
+	sv, err := c.Convert(val)
+	require.NoError(t, err)
+	require.NoError(t, sv["Port"].Set("8080"))
+	require.NoError(t, sv["Password"].Set("hunter2"))
+	require.NoError(t, sv["Password"].Set("hunter3"))
+	assert.Error(t, sv["Port"].Set("x"))
+	assert.Equal(t, []string{
+		"flag Port: 80 -> 8080",
+		"flag Password:  -> ******",
+		"flag Password: ****** -> ******",
+	}, audit)
+}
@@ -1,7 +1,12 @@
 package structflag
 
 import (
+	"errors"
+	"fmt"
 	"reflect"
+	"sort"
+	"strconv"
+	"strings"
 )
 
 // StructToFlagsConverter is useful for converting all fields in a struct to
@@ -9,21 +14,143 @@ import (
 type StructToFlagsConverter struct {
 	// WordSeparator is used to separate child struct fields from parent structs.
 	WordSeparator string
+	// Prefix is prepended to all generated flag names.
+	Prefix string
 	// DescriptionTag is used to query struct tag to generate description for values.
 	DescriptionTag string
+	// AliasesTag is used to query struct tag to get comma separated list of
+	// additional flag names for values. Aliases are used as is, without adding
+	// prefixes or calling NameConverterFunc.
+	AliasesTag string
+	// WasNamedTag is used to query struct tag to get comma separated list of
+	// previous flag names of renamed fields, e.g. `wasNamed:"old-name"`. They
+	// are accepted like aliases but reported as deprecated.
+	WasNamedTag string
+	// SecretTag is used to query struct tag to find values which must not be
+	// shown to users, e.g. `secret:"true"`.
+	SecretTag string
 	// NameConverterFunc is used to change field names before adding them to output.
 	NameConverterFunc func(string) string
+	// NameTag is used to query struct tag to get field names, e.g. "json". Options
+	// after a comma are ignored, fields tagged with "-" are skipped and fields
+	// without the tag use the Go field name. Names are passed through
+	// NameConverterFunc in both cases.
+	NameTag string
+	// FieldFilter decides whether a field is converted. It is called with the flag
+	// path of each field, including nested structs. Returning false skips the
+	// field and all fields nested in it. All fields are converted if it is nil.
+	FieldFilter func(path string, field reflect.StructField) bool
+	// OnNameCollision is called when a field maps to a flag name which is already
+	// used by another field. It returns the name to use for the field or an
+	// error. If it is nil, then Convert returns an error on collision.
+	OnNameCollision NameCollisionFunc
+	// ReservedNames lists flag names which can not be generated from fields
+	// because they are used by the flag parser itself.
+	ReservedNames []string
+	// Strict makes Convert return an error for fields of types which can not be
+	// converted to and from strings, e.g. channels, functions or interfaces with
+	// methods. Such fields are skipped otherwise.
+	Strict bool
+	// OnSkip is called for fields which are not converted because they are
+	// excluded using NameTag or FieldFilter, or because Strict is false and
+	// their type is not supported. The path is the flag name the field would
+	// get. Unexported fields are skipped without calling it.
+	OnSkip func(path string, reason error)
+	// CopyOnGet makes Get method of generated values return deep copies instead
+	// of sharing pointers, slices and maps with the struct.
+	CopyOnGet bool
+	// RepeatPolicy defines how generated values handle repeated Set calls. It
+	// does not apply to slice values.
+	RepeatPolicy RepeatPolicy
+	// SchemeTag is used to query struct tag to get URL schemes accepted by
+	// url.URL values, e.g. `scheme:"http,https"`. Value "true" accepts any
+	// scheme but rejects relative URLs.
+	SchemeTag string
+	// FormatTag is used to query struct tag to get the name of a format
+	// registered with RegisterFormat used to convert the field, e.g.
+	// `format:"dims"`.
+	FormatTag string
+	// GroupTag is used to query struct tag to get the group of values, e.g.
+	// `group:"tls"`. Groups are shown as separate sections in usage output and
+	// can be used to select the values bound to flags. Values in nested structs
+	// inherit the group of the struct field.
+	GroupTag string
+	// OptionalTag is used to query struct tag to find pointer to struct fields
+	// which stay nil until one of the nested values is set, e.g.
+	// `optional:"true"`. Other struct pointers are allocated by Convert.
+	OptionalTag string
+	// ChoicesTag is used to query struct tag to get the allowed values shown
+	// in descriptions as {{.Choices}}, e.g. `choices:"json,text"`.
+	ChoicesTag string
+	// EnvTag is used to query struct tag to get the environment variable shown
+	// in descriptions as {{.Env}}, e.g. `env:"PORT"`.
+	EnvTag string
+	// ExampleTag is used to query struct tag to get an example value shown in
+	// usage output and manifests, e.g. `example:"redis://localhost:6379"`.
+	ExampleTag string
+	// RequiredTag is used to query struct tag to find values marked as
+	// required in manifests, e.g. `required:"true"`.
+	RequiredTag string
+	// HiddenTag is used to query struct tag to find values marked as hidden in
+	// manifests, e.g. `hidden:"true"`.
+	HiddenTag string
+	// DeprecatedTag is used to query struct tag to get the deprecation message
+	// of values in manifests, e.g. `deprecated:"use -Speed"`.
+	DeprecatedTag string
+	// SinceTag is used to query struct tag to get the version which introduced
+	// values in manifests, e.g. `since:"v1.4"`.
+	SinceTag string
+	// RemovedTag is used to query struct tag to get the version which removed
+	// values in manifests, e.g. `removed:"v2.0"`.
+	RemovedTag string
+	// DynamicTag is used to query struct tag to find values which can be
+	// updated at runtime, e.g. `dynamic:"true"`. They are not sealed by Seal
+	// and are set by Reloader.
+	DynamicTag string
+	// Unmarshal decodes struct, map, slice and array values instead of
+	// encoding/json when it is set, e.g. to accept more lenient syntax. Values
+	// are still shown as JSON.
+	Unmarshal func(data []byte, v interface{}) error
+	// NullLiteral is the value which resets pointer, map, slice and interface
+	// fields to nil. Set it to empty string to disable this behavior.
+	NullLiteral string
+	// OnSet is called after each successful Set of generated values with the
+	// flag path, the previous and the new value and the source of the change,
+	// e.g. to write configuration changes to an audit log. Non-empty values of
+	// secret fields are replaced by "******".
+	OnSet func(path, oldValue, newValue string, source Source)
 }
 
+// withoutOnSet returns a copy of the converter which does not call OnSet, for
+// values of copies which are never applied to the target.
+func (thiz *StructToFlagsConverter) withoutOnSet() *StructToFlagsConverter {
+	converter := *thiz
+	converter.OnSet = nil
+	return &converter
+}
+
+// DefaultStructToFlagsConverter is the converter used by package level functions.
+var DefaultStructToFlagsConverter = NewStructToFlagsConverter()
+
 /*
 NewStructToFlagsConverter returns a new converter that uses "-" for separating words,
-does not change field names and extracts description from "description" struct tag. The
-returned instance can be customized by changing fields. It can be used with flags
-package like this:
+does not change field names, extracts description from "description" struct tag,
+aliases from "aliases" struct tag, previous names from "wasNamed" struct tag,
+secret marker from "secret" struct tag, optional marker from "optional" struct
+tag, URL schemes from "scheme" struct tag, formats from "format" struct tag,
+groups from "group" struct tag, allowed values from "choices" struct tag,
+environment variables from "env" struct tag, examples from "example" struct
+tag, manifest data from "required", "hidden", "deprecated", "since" and
+"removed" struct tags and runtime updatable values from "dynamic" struct tag,
+reserves "help" and "h" flag names and uses
+"null" to reset pointer fields. The returned instance can be customized by
+changing fields. It can be used with flags package like this:
+
 	package main
 
 	import (
 		"flag"
+		"os"
 
 		"github.com/surajbarkale/structflag"
 	)
@@ -40,39 +167,171 @@ package like this:
 
 	func main() {
 		a := &args{Debug: true}
-		for name, value := range structflag.DefaultStructToFlagsConverter.Convert(&a) {
+		values, err := structflag.DefaultStructToFlagsConverter.Convert(&a)
+		if err != nil {
+			panic(err)
+		}
+		for name, value := range values {
 			flag.Var(value, name, value.Description())
 		}
-		flag.PrintDefaults()
+		structflag.PrintDefaults(os.Stderr, values)
 	}
 
 This program should print output:
-	-Debug
-		Enable debug mode (default true)
-	-Extra-Pages
-
-	-Extra-WrapLines
-			(default false)
-	-InputFile
-		Name of input file
+
+	$ go run .
+	  -Debug
+	    	Enable debug mode (default true)
+	  -Extra-Pages value
+	  -Extra-WrapLines
+	  -InputFile string
+	    	Name of input file
 */
 func NewStructToFlagsConverter() *StructToFlagsConverter {
 	return &StructToFlagsConverter{
 		WordSeparator:     "-",
 		DescriptionTag:    "description",
+		AliasesTag:        "aliases",
+		WasNamedTag:       "wasNamed",
+		SecretTag:         "secret",
+		OptionalTag:       "optional",
+		SchemeTag:         "scheme",
+		FormatTag:         "format",
+		GroupTag:          "group",
+		ChoicesTag:        "choices",
+		EnvTag:            "env",
+		ExampleTag:        "example",
+		RequiredTag:       "required",
+		HiddenTag:         "hidden",
+		DeprecatedTag:     "deprecated",
+		SinceTag:          "since",
+		RemovedTag:        "removed",
+		DynamicTag:        "dynamic",
 		NameConverterFunc: func(s string) string { return s },
+		ReservedNames:     []string{"help", "h"},
+		NullLiteral:       "null",
 	}
 }
 
 // Convert generates the flag values compatible with the structure. You must pass a
-// pointer to the value
-func (thiz *StructToFlagsConverter) Convert(input interface{}) map[string]Value {
+// pointer to the value. An error is returned if multiple fields map to the same
+// flag name and OnNameCollision does not resolve the collision, or if a flag name
+// is reserved or can not be used with flag package.
+//
+// Convert used to return only the values. This is a breaking change: callers
+// must now check the error, e.g. replace
+//
+//	values := converter.Convert(&args)
+//
+// with
+//
+//	values, err := converter.Convert(&args)
+//	if err != nil {
+//		return err
+//	}
+func (thiz *StructToFlagsConverter) Convert(input interface{}) (map[string]Value, error) {
+	output := map[string]Value{}
+	if err := thiz.convertStruct(thiz.Prefix, reflect.ValueOf(input), output); err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+// ConvertAll generates the flag values for multiple structures. Flag names for
+// each structure are prefixed by its key followed by WordSeparator. Name
+// collisions are handled in the same way as in Convert. You must pass pointers
+// to the values.
+func (thiz *StructToFlagsConverter) ConvertAll(inputs map[string]interface{}) (map[string]Value, error) {
+	keys := make([]string, 0, len(inputs))
+	for key := range inputs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
 	output := map[string]Value{}
-	thiz.reflectStructToFlags("", reflect.ValueOf(input), output)
-	return output
+	for _, key := range keys {
+		if err := thiz.convertStruct(thiz.Prefix+key+thiz.WordSeparator, reflect.ValueOf(inputs[key]), output); err != nil {
+			return nil, err
+		}
+	}
+	return output, nil
+}
+
+// convertStruct adds the values generated from input to output.
+func (thiz *StructToFlagsConverter) convertStruct(prefix string, input reflect.Value, output map[string]Value) error {
+	used := func(name string) bool {
+		_, ok := output[name]
+		return ok
+	}
+	return thiz.walkStruct(prefix, input, func(info FieldInfo) error {
+		name := info.Path
+		if used(name) {
+			resolve := thiz.OnNameCollision
+			if resolve == nil {
+				resolve = ErrorOnNameCollision
+			}
+			var err error
+			if name, err = resolve(name, used); err != nil {
+				return err
+			}
+			if used(name) {
+				return fmt.Errorf("flag name %q resolved for field %s is already used", name, info.Path)
+			}
+		}
+		if err := thiz.checkName(name); err != nil {
+			return fmt.Errorf("invalid flag name for field %s: %v", info.Path, err)
+		}
+		output[name] = info.Value
+		return nil
+	})
+}
+
+// fieldName returns the name of the field before conversion. It returns false if
+// the field is excluded using the name tag.
+func (thiz *StructToFlagsConverter) fieldName(field reflect.StructField) (string, bool) {
+	if thiz.NameTag == "" {
+		return field.Name, true
+	}
+	tag, ok := field.Tag.Lookup(thiz.NameTag)
+	if tag == "-" {
+		return "", false
+	}
+	if idx := strings.Index(tag, ","); idx >= 0 {
+		tag = tag[:idx]
+	}
+	if !ok || tag == "" {
+		return field.Name, true
+	}
+	return tag, true
 }
 
-func (thiz *StructToFlagsConverter) reflectStructToFlags(prefix string, input reflect.Value, output map[string]Value) {
+// checkName returns an error if the name is reserved or would be rejected by
+// flag package.
+func (thiz *StructToFlagsConverter) checkName(name string) error {
+	switch {
+	case name == "":
+		return fmt.Errorf("flag name is empty")
+	case strings.HasPrefix(name, "-"):
+		return fmt.Errorf("flag name %q begins with -", name)
+	case strings.Contains(name, "="):
+		return fmt.Errorf("flag name %q contains =", name)
+	}
+	for _, reserved := range thiz.ReservedNames {
+		if name == reserved {
+			return fmt.Errorf("flag name %q is reserved", name)
+		}
+	}
+	return nil
+}
+
+func (thiz *StructToFlagsConverter) walkStruct(prefix string, input reflect.Value, fn func(FieldInfo) error) error {
+	return thiz.walkFields(prefix, input, nil, "", fn)
+}
+
+// walkFields calls fn for the leaf fields of input. If attach is not nil, then
+// input is not stored in the parent struct yet and attach stores it. It is
+// called by the values after they are set. Fields without a group tag get the
+// given group.
+func (thiz *StructToFlagsConverter) walkFields(prefix string, input reflect.Value, attach func(), group string, fn func(FieldInfo) error) error {
 	for input.Kind() == reflect.Ptr || input.Kind() == reflect.Interface {
 		input = input.Elem()
 	}
@@ -83,21 +342,118 @@ func (thiz *StructToFlagsConverter) reflectStructToFlags(prefix string, input re
 		if !field.CanSet() {
 			continue
 		}
-		fieldKind := field.Kind()
-		fieldPath := prefix + thiz.NameConverterFunc(inputType.Field(i).Name)
+		fieldName, ok := thiz.fieldName(inputType.Field(i))
+		if !ok {
+			thiz.skip(prefix+thiz.NameConverterFunc(inputType.Field(i).Name), fmt.Errorf("excluded by %s struct tag", thiz.NameTag))
+			continue
+		}
+		fieldPath := prefix + thiz.NameConverterFunc(fieldName)
+		if thiz.FieldFilter != nil && !thiz.FieldFilter(fieldPath, inputType.Field(i)) {
+			thiz.skip(fieldPath, errors.New("excluded by field filter"))
+			continue
+		}
+		fieldGroup := group
+		if g := inputType.Field(i).Tag.Get(thiz.GroupTag); thiz.GroupTag != "" && g != "" {
+			fieldGroup = g
+		}
+		var codec Codec
+		if format := inputType.Field(i).Tag.Get(thiz.FormatTag); thiz.FormatTag != "" && format != "" {
+			var err error
+			if codec, err = thiz.formatCodec(format, field.Type()); err != nil {
+				return fmt.Errorf("invalid format for field %s: %v", fieldPath, err)
+			}
+		}
 		// Recursively go through the members that are structs or pointers to struct
-		if fieldKind == reflect.Struct || (fieldKind == reflect.Ptr && field.Type().Elem().Kind() == reflect.Struct) {
+		if codec == nil && isStructField(field) {
+			nested, nestedAttach := field, attach
 			// If struct pointer is nil, then initialize it with empty struct
-			if fieldKind == reflect.Ptr && field.IsNil() {
-				field.Set(reflect.New(field.Type().Elem()))
+			if field.Kind() == reflect.Ptr && field.IsNil() {
+				if thiz.isOptional(inputType.Field(i)) {
+					// Values are bound to a detached struct until one is set
+					nested = reflect.New(field.Type().Elem())
+					nestedAttach = attachFunc(field, nested, attach)
+				} else {
+					field.Set(reflect.New(field.Type().Elem()))
+				}
+			}
+			if err := thiz.walkFields(fieldPath+thiz.WordSeparator, nested, nestedAttach, fieldGroup, fn); err != nil {
+				return err
 			}
-			thiz.reflectStructToFlags(fieldPath+thiz.WordSeparator, field, output)
 		} else {
+			if codec == nil {
+				if err := checkSupported(field.Type()); err != nil {
+					if thiz.Strict {
+						return fmt.Errorf("field %s can not be converted: %v", fieldPath, err)
+					}
+					thiz.skip(fieldPath, err)
+					continue
+				}
+			}
 			var description string
 			if thiz.DescriptionTag != "" {
 				description = inputType.Field(i).Tag.Get(thiz.DescriptionTag)
 			}
-			output[fieldPath] = NewReflectedValue(field, description)
+			var secret bool
+			if thiz.SecretTag != "" {
+				secret, _ = strconv.ParseBool(inputType.Field(i).Tag.Get(thiz.SecretTag))
+			}
+			dynamic, _ := strconv.ParseBool(tagValue(inputType.Field(i).Tag, thiz.DynamicTag))
+			value := &reflectedValue{
+				target:       field,
+				path:         fieldPath,
+				initial:      deepCopy(field),
+				description:  description,
+				field:        inputType.Field(i),
+				aliases:      tagList(inputType.Field(i).Tag, thiz.AliasesTag),
+				oldNames:     tagList(inputType.Field(i).Tag, thiz.WasNamedTag),
+				group:        fieldGroup,
+				secret:       secret,
+				choices:      tagValue(inputType.Field(i).Tag, thiz.ChoicesTag),
+				env:          tagValue(inputType.Field(i).Tag, thiz.EnvTag),
+				example:      tagValue(inputType.Field(i).Tag, thiz.ExampleTag),
+				dynamic:      dynamic,
+				source:       SourceDefault,
+				nullLiteral:  thiz.NullLiteral,
+				copyOnGet:    thiz.CopyOnGet,
+				repeatPolicy: thiz.RepeatPolicy,
+				attach:       attach,
+				onSet:        thiz.OnSet,
+				codec:        codec,
+				unmarshal:    thiz.Unmarshal,
+			}
+			value.defValue = value.defaultString()
+			if thiz.SchemeTag != "" {
+				value.check = schemeCheck(field.Type(), inputType.Field(i).Tag.Get(thiz.SchemeTag))
+			}
+			err := fn(FieldInfo{
+				Path:  fieldPath,
+				Tag:   inputType.Field(i).Tag,
+				Kind:  value.Kind(),
+				Value: value,
+			})
+			if err != nil {
+				return err
+			}
 		}
 	}
+	return nil
+}
+
+// skip reports a field which is not converted.
+func (thiz *StructToFlagsConverter) skip(path string, reason error) {
+	if thiz.OnSkip != nil {
+		thiz.OnSkip(path, reason)
+	}
+}
+
+// isStructField returns true if the field is a struct or a pointer to struct which
+// is converted into multiple values, i.e. it is not handled by a codec or
+// converted from text.
+func isStructField(field reflect.Value) bool {
+	t := field.Type()
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	// Structs converted from text like time.Time are values, not nested flags
+	return !isCodecType(field.Type()) && !isTextType(t) && t.Kind() == reflect.Struct
 }
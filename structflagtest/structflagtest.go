@@ -0,0 +1,179 @@
+/*
+Package structflagtest provides helpers for testing command line handling of
+programs using structflag. A typical table driven test looks like this:
+
+	func TestArgs(t *testing.T) {
+		cfg := structflagtest.MustParse[config](t, "--Port", "80", "-v")
+		assert.Equal(t, 80, cfg.Port)
+	}
+
+Usage output can be compared with a golden file. Set UPDATE_GOLDEN environment
+variable to a non-empty value to write the current output to the golden files.
+Differences are reported line by line. Use HelpPrinter to freeze output of
+structflag.HelpPrinter, which otherwise depends on the terminal:
+
+	parser.Usage = structflagtest.HelpPrinter().PrintDefaults
+	structflagtest.AssertGoldenUsage(t, parser, &config{}, "testdata/usage.golden")
+*/
+package structflagtest
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/surajbarkale/structflag"
+)
+
+// UpdateGoldenEnv is the environment variable enabling updates of golden files.
+const UpdateGoldenEnv = "UPDATE_GOLDEN"
+
+// UsageWidth is the line width used by HelpPrinter.
+const UsageWidth = 80
+
+// HelpPrinter returns a structflag.HelpPrinter which writes the same output in
+// every run regardless of the terminal, using UsageWidth and no colors.
+func HelpPrinter() *structflag.HelpPrinter {
+	printer := structflag.NewHelpPrinter()
+	printer.Width = UsageWidth
+	printer.Color = structflag.ColorNever
+	return printer
+}
+
+// MustParse parses args into a new T using a parser returned by
+// structflag.NewParser and fails the test on error.
+func MustParse[T any](t testing.TB, args ...string) *T {
+	t.Helper()
+	target := new(T)
+	MustParseInto(t, nil, target, args...)
+	return target
+}
+
+// MustParseInto parses args into target and fails the test on error. If the
+// parser is nil, then a parser returned by structflag.NewParser is used.
+// Positional arguments are returned.
+func MustParseInto(t testing.TB, parser *structflag.Parser, target interface{}, args ...string) []string {
+	t.Helper()
+	if parser == nil {
+		parser = newParser()
+	}
+	res, err := parser.Parse(target, args)
+	if err != nil {
+		t.Fatalf("can not parse %q: %v", args, err)
+	}
+	return res
+}
+
+// SetFlag sets the value of the flag generated for target by
+// structflag.DefaultStructToFlagsConverter and fails the test on error.
+func SetFlag(t testing.TB, target interface{}, name, value string) {
+	t.Helper()
+	values, err := structflag.DefaultStructToFlagsConverter.Convert(target)
+	if err != nil {
+		t.Fatalf("can not convert %T: %v", target, err)
+	}
+	v, ok := values[name]
+	if !ok {
+		t.Fatalf("flag %s is not defined for %T", name, target)
+	}
+	if err := v.Set(value); err != nil {
+		t.Fatalf("can not set flag %s to %q: %v", name, value, err)
+	}
+}
+
+// Usage returns the help output printed by the parser for target. If the
+// parser is nil, then a parser returned by structflag.NewParser with name "app"
+// is used.
+func Usage(t testing.TB, parser *structflag.Parser, target interface{}) string {
+	t.Helper()
+	if parser == nil {
+		parser = newParser()
+	}
+	var b bytes.Buffer
+	if err := parser.PrintUsage(&b, target); err != nil {
+		t.Fatalf("can not print usage: %v", err)
+	}
+	return b.String()
+}
+
+// AssertGoldenUsage compares help output printed by the parser for target with
+// the content of golden file. See Usage for details about the parser.
+func AssertGoldenUsage(t testing.TB, parser *structflag.Parser, target interface{}, golden string) {
+	t.Helper()
+	AssertGolden(t, Usage(t, parser, target), golden)
+}
+
+// AssertGolden compares actual with the content of golden file. The file is
+// written instead if UPDATE_GOLDEN environment variable is not empty.
+func AssertGolden(t testing.TB, actual, golden string) {
+	t.Helper()
+	if os.Getenv(UpdateGoldenEnv) != "" {
+		if err := os.MkdirAll(filepath.Dir(golden), 0755); err != nil {
+			t.Fatalf("can not create golden file directory: %v", err)
+		}
+		if err := ioutil.WriteFile(golden, []byte(actual), 0644); err != nil {
+			t.Fatalf("can not write golden file: %v", err)
+		}
+		return
+	}
+	expected, err := ioutil.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("can not read golden file, set %s=1 to create it: %v", UpdateGoldenEnv, err)
+	}
+	if string(expected) != actual {
+		t.Errorf("output does not match %s, set %s=1 to update it\n--- expected\n+++ actual\n%s", golden, UpdateGoldenEnv, lineDiff(string(expected), actual))
+	}
+}
+
+// lineDiff returns the lines of expected and actual prefixed by "-" for lines
+// only in expected, "+" for lines only in actual and " " for common lines.
+func lineDiff(expected, actual string) string {
+	a, b := strings.SplitAfter(expected, "\n"), strings.SplitAfter(actual, "\n")
+	// common[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	common := make([][]int, len(a)+1)
+	for i := range common {
+		common[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				common[i][j] = common[i+1][j+1] + 1
+			} else if common[i+1][j] >= common[i][j+1] {
+				common[i][j] = common[i+1][j]
+			} else {
+				common[i][j] = common[i][j+1]
+			}
+		}
+	}
+	var diff strings.Builder
+	line := func(prefix, s string) {
+		if s != "" {
+			diff.WriteString(prefix + strings.TrimSuffix(s, "\n") + "\n")
+		}
+	}
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			line(" ", a[i])
+			i, j = i+1, j+1
+		case j == len(b) || i < len(a) && common[i+1][j] >= common[i][j+1]:
+			line("-", a[i])
+			i++
+		default:
+			line("+", b[j])
+			j++
+		}
+	}
+	return diff.String()
+}
+
+func newParser() *structflag.Parser {
+	parser := structflag.NewParser()
+	parser.Name = "app"
+	parser.Output = ioutil.Discard
+	return parser
+}
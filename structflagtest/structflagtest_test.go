@@ -0,0 +1,123 @@
+package structflagtest_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/surajbarkale/structflag"
+	"github.com/surajbarkale/structflag/structflagtest"
+)
+
+type config struct {
+	Port    int  `description:"Port to listen on"`
+	Verbose bool `aliases:"v"`
+	Nested  struct {
+		Int int
+	}
+}
+
+// recorder captures failures reported by helpers.
+type recorder struct {
+	testing.TB
+	failed  bool
+	message string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Fatalf(format string, args ...interface{}) {
+	r.failed = true
+	r.message = fmt.Sprintf(format, args...)
+	// Stop the goroutine like testing.T does
+	panic(r)
+}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.failed = true
+	r.message = fmt.Sprintf(format, args...)
+}
+
+func run(fn func(t testing.TB)) *recorder {
+	r := &recorder{}
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer func() {
+			if p := recover(); p != nil && p != r {
+				panic(p)
+			}
+		}()
+		fn(r)
+	}()
+	wg.Wait()
+	return r
+}
+
+func TestMustParse(t *testing.T) {
+	cfg := structflagtest.MustParse[config](t, "--Port", "80", "-v")
+	assert.Equal(t, 80, cfg.Port)
+	assert.True(t, cfg.Verbose)
+
+	r := run(func(t testing.TB) {
+		structflagtest.MustParse[config](t, "--Port", "x")
+	})
+	assert.True(t, r.failed)
+	assert.Contains(t, r.message, "--Port")
+}
+
+func TestSetFlag(t *testing.T) {
+	cfg := &config{}
+	structflagtest.SetFlag(t, cfg, "Nested-Int", "5")
+	assert.Equal(t, 5, cfg.Nested.Int)
+
+	r := run(func(t testing.TB) {
+		structflagtest.SetFlag(t, cfg, "Missing", "5")
+	})
+	assert.True(t, r.failed)
+	r = run(func(t testing.TB) {
+		structflagtest.SetFlag(t, cfg, "Port", "x")
+	})
+	assert.True(t, r.failed)
+}
+
+func TestGoldenUsage(t *testing.T) {
+	structflagtest.AssertGoldenUsage(t, nil, &config{Port: 80}, filepath.Join("testdata", "usage.golden"))
+
+	r := run(func(t testing.TB) {
+		structflagtest.AssertGoldenUsage(t, nil, &config{Port: 90}, filepath.Join("testdata", "usage.golden"))
+	})
+	assert.True(t, r.failed)
+	assert.Contains(t, r.message, "\n-    \tPort to listen on (default 80)\n+    \tPort to listen on (default 90)\n   -Verbose\n")
+}
+
+func TestHelpPrinter(t *testing.T) {
+	parser := structflag.NewParser()
+	parser.Name = "app"
+	parser.Usage = structflagtest.HelpPrinter().PrintDefaults
+	assert.Equal(t, `  -Nested-Int int
+  -Port int        Port to listen on (default 80)
+  -Verbose, -v
+  -help, -h        Show this help and exit
+`, structflagtest.Usage(t, parser, &config{Port: 80}))
+}
+
+func TestUpdateGolden(t *testing.T) {
+	dir := t.TempDir()
+	golden := filepath.Join(dir, "new", "usage.golden")
+	r := run(func(t testing.TB) {
+		structflagtest.AssertGolden(t, "output", golden)
+	})
+	assert.True(t, r.failed)
+
+	require.NoError(t, os.Setenv(structflagtest.UpdateGoldenEnv, "1"))
+	structflagtest.AssertGolden(t, "output", golden)
+	require.NoError(t, os.Unsetenv(structflagtest.UpdateGoldenEnv))
+	structflagtest.AssertGolden(t, "output", golden)
+}
@@ -0,0 +1,62 @@
+package structflag
+
+import (
+	"sort"
+	"strings"
+)
+
+// suggest returns the candidates closest to name by edit distance, ignoring
+// case. Candidates which differ in more than a third of the characters of name
+// are not considered similar.
+func suggest(name string, candidates []string) []string {
+	best := len(name)/3 + 1
+	var res []string
+	for _, candidate := range candidates {
+		d := editDistance(strings.ToLower(name), strings.ToLower(candidate))
+		switch {
+		case d < best:
+			best, res = d, []string{candidate}
+		case d == best:
+			res = append(res, candidate)
+		}
+	}
+	sort.Strings(res)
+	return res
+}
+
+// didYouMean formats the suggestions using DefaultMessages.
+func didYouMean(matches []string) string {
+	return DefaultMessages.didYouMean(matches)
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min3(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
@@ -0,0 +1,61 @@
+package structflag
+
+import (
+	"reflect"
+	"strings"
+)
+
+// tagList returns comma separated items from the struct tag. It returns nil if
+// the tag name is empty.
+func tagList(tag reflect.StructTag, name string) []string {
+	if name == "" {
+		return nil
+	}
+	return splitList(tag.Get(name))
+}
+
+// tagValue returns the value of the struct tag with given name. It returns
+// empty string if the name is empty.
+func tagValue(tag reflect.StructTag, name string) string {
+	if name == "" {
+		return ""
+	}
+	return tag.Get(name)
+}
+
+// splitList returns non-empty items of a comma separated list with spaces
+// trimmed.
+func splitList(s string) []string {
+	var res []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			res = append(res, item)
+		}
+	}
+	return res
+}
+
+// tagKeys returns the keys of a struct tag in the conventional format.
+func tagKeys(tag reflect.StructTag) []string {
+	var keys []string
+	s := string(tag)
+	for {
+		s = strings.TrimLeft(s, " ")
+		idx := strings.Index(s, ":\"")
+		if idx <= 0 {
+			return keys
+		}
+		keys = append(keys, s[:idx])
+		// Skip the quoted value
+		i := idx + 2
+		for ; i < len(s) && s[i] != '"'; i++ {
+			if s[i] == '\\' {
+				i++
+			}
+		}
+		if i >= len(s) {
+			return keys
+		}
+		s = s[i+1:]
+	}
+}
@@ -0,0 +1,12 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd
+
+package structflag
+
+import (
+	"os"
+)
+
+// terminalWidth is not supported on this platform.
+func terminalWidth(f *os.File) int {
+	return 0
+}
@@ -0,0 +1,220 @@
+/*
+Package tuiflag provides an interactive terminal form for editing structflag
+values, e.g. for first run setup wizards:
+
+	values, err := structflag.DefaultStructToFlagsConverter.Convert(&cfg)
+	if err != nil {
+		panic(err)
+	}
+	if err := tuiflag.Edit(values); err != nil {
+		panic(err)
+	}
+	tuiflag.WriteConfig(file, values)
+
+The form is built with bubbletea. Input is validated as it is typed and the
+values are only updated when the form is saved.
+*/
+package tuiflag
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/surajbarkale/structflag"
+)
+
+// ErrCancelled is returned by Edit when the user leaves the form without saving.
+var ErrCancelled = errors.New("tuiflag: editing cancelled")
+
+// Model is a bubbletea model editing values in a form with one input per value.
+// Values are sorted by name. Use NewModel to create a model.
+type Model struct {
+	// Title is shown above the form.
+	Title string
+
+	values    map[string]structflag.Value
+	names     []string
+	inputs    []textinput.Model
+	errs      []error
+	focus     int
+	submitted bool
+}
+
+// NewModel returns a model editing the values. Secret values are not shown
+// while they are typed.
+func NewModel(values map[string]structflag.Value) *Model {
+	thiz := &Model{
+		Title:  "Configuration",
+		values: values,
+	}
+	for name := range values {
+		thiz.names = append(thiz.names, name)
+	}
+	sort.Strings(thiz.names)
+	for _, name := range thiz.names {
+		input := textinput.New()
+		input.Prompt = ""
+		input.SetValue(values[name].String())
+		if values[name].IsSecret() {
+			input.EchoMode = textinput.EchoPassword
+		}
+		thiz.inputs = append(thiz.inputs, input)
+	}
+	thiz.errs = make([]error, len(thiz.inputs))
+	if len(thiz.inputs) > 0 {
+		thiz.inputs[0].Focus()
+	}
+	return thiz
+}
+
+// Init implements tea.Model.
+func (thiz *Model) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+// Update implements tea.Model. Tab, arrow keys and enter move between inputs,
+// enter on the last input or ctrl+s saves the form and esc or ctrl+c cancels
+// it. The form can not be saved while any input is invalid.
+func (thiz *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if key, ok := msg.(tea.KeyMsg); ok {
+		switch key.String() {
+		case "esc", "ctrl+c":
+			return thiz, tea.Quit
+		case "ctrl+s":
+			return thiz, thiz.submit()
+		case "enter":
+			if thiz.focus == len(thiz.inputs)-1 {
+				return thiz, thiz.submit()
+			}
+			return thiz, thiz.move(1)
+		case "tab", "down":
+			return thiz, thiz.move(1)
+		case "shift+tab", "up":
+			return thiz, thiz.move(-1)
+		}
+	}
+	if len(thiz.inputs) == 0 {
+		return thiz, nil
+	}
+	var cmd tea.Cmd
+	thiz.inputs[thiz.focus], cmd = thiz.inputs[thiz.focus].Update(msg)
+	thiz.errs[thiz.focus] = thiz.validate(thiz.focus)
+	return thiz, cmd
+}
+
+// View implements tea.Model.
+func (thiz *Model) View() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\n\n", thiz.Title)
+	for i, name := range thiz.names {
+		cursor := " "
+		if i == thiz.focus {
+			cursor = ">"
+		}
+		fmt.Fprintf(&b, "%s %s: %s\n", cursor, name, thiz.inputs[i].View())
+		if description := thiz.values[name].Description(); description != "" {
+			fmt.Fprintf(&b, "    %s\n", strings.Replace(description, "\n", "\n    ", -1))
+		}
+		if thiz.errs[i] != nil {
+			fmt.Fprintf(&b, "    ! %v\n", thiz.errs[i])
+		}
+	}
+	b.WriteString("\ntab: next • shift+tab: previous • ctrl+s: save • esc: cancel\n")
+	return b.String()
+}
+
+// Submitted returns true if the form was saved.
+func (thiz *Model) Submitted() bool {
+	return thiz.submitted
+}
+
+// Apply sets the values which were changed in the form. All values are
+// attempted and the first error is returned.
+func (thiz *Model) Apply() error {
+	var first error
+	for i, name := range thiz.names {
+		value := thiz.values[name]
+		if s := thiz.inputs[i].Value(); s != value.String() {
+			if err := value.Set(s); err != nil && first == nil {
+				first = err
+				// Values which are not created by a converter do not name the flag
+				var valueErr *structflag.ValueError
+				if !errors.As(err, &valueErr) || valueErr.Path == "" {
+					first = fmt.Errorf("invalid value %q for %s: %v", s, name, err)
+				}
+			}
+		}
+	}
+	return first
+}
+
+// move focuses the input at the given offset from the current one.
+func (thiz *Model) move(offset int) tea.Cmd {
+	if len(thiz.inputs) == 0 {
+		return nil
+	}
+	thiz.inputs[thiz.focus].Blur()
+	thiz.focus = (thiz.focus + offset + len(thiz.inputs)) % len(thiz.inputs)
+	return thiz.inputs[thiz.focus].Focus()
+}
+
+// submit quits the program unless an input is invalid, in which case the first
+// invalid input is focused.
+func (thiz *Model) submit() tea.Cmd {
+	for i := range thiz.inputs {
+		if thiz.errs[i] = thiz.validate(i); thiz.errs[i] != nil {
+			return thiz.move(i - thiz.focus)
+		}
+	}
+	thiz.submitted = true
+	return tea.Quit
+}
+
+// validate sets the input to a scratch value of the same type so that the
+// edited value is not changed before the form is saved.
+func (thiz *Model) validate(i int) error {
+	value, s := thiz.values[thiz.names[i]], thiz.inputs[i].Value()
+	if s == value.String() {
+		return nil
+	}
+	t := reflect.TypeOf(value.Get())
+	if t == nil {
+		return nil
+	}
+	return structflag.NewReflectedValue(reflect.New(t).Elem(), "").Set(s)
+}
+
+// Edit runs a form editing the values and sets the changed values when the
+// form is saved. ErrCancelled is returned if the form is cancelled. The options
+// are passed to tea.NewProgram.
+func Edit(values map[string]structflag.Value, opts ...tea.ProgramOption) error {
+	model := NewModel(values)
+	if _, err := tea.NewProgram(model, opts...).Run(); err != nil {
+		return err
+	}
+	if !model.Submitted() {
+		return ErrCancelled
+	}
+	return model.Apply()
+}
+
+// WriteConfig writes the values to w as a JSON object mapping flag names to
+// their string representation. This is the format accepted by
+// structflag.ConfigHandler.
+func WriteConfig(w io.Writer, values map[string]structflag.Value) error {
+	config := make(map[string]string, len(values))
+	for name, value := range values {
+		config[name] = value.String()
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(config)
+}
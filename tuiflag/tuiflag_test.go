@@ -0,0 +1,87 @@
+package tuiflag_test
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/surajbarkale/structflag"
+	"github.com/surajbarkale/structflag/tuiflag"
+)
+
+type settings struct {
+	Name     string `description:"User name"`
+	Port     int
+	Password string `secret:"true"`
+}
+
+func typeText(m tea.Model, s string) tea.Model {
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)})
+	return m
+}
+
+func press(m tea.Model, key tea.KeyType) (tea.Model, tea.Cmd) {
+	return m.Update(tea.KeyMsg{Type: key})
+}
+
+func TestModelValidatesAndApplies(t *testing.T) {
+	cfg := &settings{Name: "bob", Port: 8}
+	values, err := structflag.DefaultStructToFlagsConverter.Convert(cfg)
+	require.NoError(t, err)
+	model := tuiflag.NewModel(values)
+	var m tea.Model = model
+	assert.Contains(t, m.View(), "> Name: bob")
+	assert.Contains(t, m.View(), "User name")
+
+	m, _ = press(m, tea.KeyTab)
+	m = typeText(m, "pw")
+	assert.NotContains(t, m.View(), "pw")
+	m, _ = press(m, tea.KeyTab)
+	m = typeText(m, "x")
+	assert.Contains(t, m.View(), "> Port: 8x")
+	assert.Contains(t, m.View(), "! strconv.ParseInt")
+	assert.Equal(t, 8, cfg.Port, "values must not change before saving")
+
+	// Saving is refused while an input is invalid
+	m, _ = press(m, tea.KeyCtrlS)
+	assert.False(t, model.Submitted())
+
+	m, _ = press(m, tea.KeyBackspace)
+	m = typeText(m, "0")
+	m, cmd := press(m, tea.KeyEnter)
+	assert.NotNil(t, cmd)
+	assert.True(t, model.Submitted())
+	require.NoError(t, model.Apply())
+	assert.Equal(t, 80, cfg.Port)
+	assert.Equal(t, "pw", cfg.Password)
+	assert.Equal(t, structflag.SourceFlag, values["Port"].Source())
+	assert.Equal(t, structflag.SourceDefault, values["Name"].Source())
+}
+
+func TestEdit(t *testing.T) {
+	cfg := &settings{Port: 8}
+	values, err := structflag.DefaultStructToFlagsConverter.Convert(cfg)
+	require.NoError(t, err)
+	// Type a name and save with ctrl+s
+	input := strings.NewReader("alice\x13")
+	require.NoError(t, tuiflag.Edit(values, tea.WithInput(input), tea.WithOutput(io.Discard)))
+	assert.Equal(t, "alice", cfg.Name)
+
+	input = strings.NewReader("carol\x1b")
+	err = tuiflag.Edit(values, tea.WithInput(input), tea.WithOutput(io.Discard))
+	assert.Equal(t, tuiflag.ErrCancelled, err)
+	assert.Equal(t, "alice", cfg.Name)
+}
+
+func TestWriteConfig(t *testing.T) {
+	values, err := structflag.DefaultStructToFlagsConverter.Convert(&settings{Name: "bob", Port: 8})
+	require.NoError(t, err)
+	var b bytes.Buffer
+	require.NoError(t, tuiflag.WriteConfig(&b, values))
+	assert.JSONEq(t, `{"Name":"bob","Port":"8","Password":""}`, b.String())
+}
@@ -0,0 +1,76 @@
+package structflag
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+var urlType = reflect.TypeOf(url.URL{})
+
+// urlCodec converts url.URL values using url.Parse and URL.String.
+type urlCodec struct{}
+
+func init() {
+	RegisterCodec(urlCodec{})
+}
+
+// Match returns true for url.URL.
+func (urlCodec) Match(t reflect.Type) bool {
+	return t == urlType
+}
+
+// Decode parses the URL.
+func (urlCodec) Decode(s string, val reflect.Value) error {
+	u, err := url.Parse(s)
+	if err != nil {
+		return err
+	}
+	val.Set(reflect.ValueOf(*u))
+	return nil
+}
+
+// Encode returns the URL in normalized form.
+func (urlCodec) Encode(val reflect.Value) (string, error) {
+	u := val.Interface().(url.URL)
+	return u.String(), nil
+}
+
+// schemeCheck returns a function checking the scheme of URL values. Scheme
+// "true" accepts any scheme, otherwise it is a comma separated list of
+// accepted schemes. Nil is returned if the type is not a URL or the scheme is
+// empty.
+func schemeCheck(t reflect.Type, scheme string) func(reflect.Value) error {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t != urlType || scheme == "" {
+		return nil
+	}
+	var schemes []string
+	if scheme != "true" {
+		schemes = splitList(scheme)
+	}
+	return func(val reflect.Value) error {
+		for val.Kind() == reflect.Ptr {
+			if val.IsNil() {
+				return nil
+			}
+			val = val.Elem()
+		}
+		u := val.Interface().(url.URL)
+		if u.Scheme == "" {
+			return fmt.Errorf("URL %q has no scheme", u.String())
+		}
+		for _, s := range schemes {
+			if strings.EqualFold(s, u.Scheme) {
+				return nil
+			}
+		}
+		if len(schemes) > 0 {
+			return fmt.Errorf("URL scheme %q is not one of %s", u.Scheme, strings.Join(schemes, ", "))
+		}
+		return nil
+	}
+}
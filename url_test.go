@@ -0,0 +1,44 @@
+package structflag_test
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/surajbarkale/structflag"
+)
+
+func TestURLValues(t *testing.T) {
+	val := &struct {
+		Endpoint url.URL
+		Proxy    *url.URL
+		Upstream *url.URL `scheme:"http, https"`
+		Callback url.URL  `scheme:"true"`
+	}{Endpoint: url.URL{Scheme: "http", Host: "localhost"}}
+	values, err := structflag.DefaultStructToFlagsConverter.Convert(val)
+	require.NoError(t, err)
+	assert.Len(t, values, 4)
+	assert.Equal(t, "http://localhost", values["Endpoint"].Default())
+
+	require.NoError(t, values["Endpoint"].Set("HTTPS://example.com/a%20b?q=1"))
+	assert.Equal(t, "https", val.Endpoint.Scheme)
+	assert.Equal(t, "/a b", val.Endpoint.Path)
+	assert.Equal(t, "https://example.com/a%20b?q=1", values["Endpoint"].String())
+	assert.Error(t, values["Endpoint"].Set("http://[::1"))
+
+	require.NoError(t, values["Proxy"].Set("socks5://proxy:1080"))
+	assert.Equal(t, "proxy:1080", val.Proxy.Host)
+	require.NoError(t, values["Proxy"].Set("null"))
+	assert.Nil(t, val.Proxy)
+
+	require.NoError(t, values["Upstream"].Set("https://up"))
+	assert.EqualError(t, values["Upstream"].Set("ftp://up"), `invalid value "ftp://up" for flag Upstream: URL scheme "ftp" is not one of http, https`)
+	assert.Equal(t, "https://up", val.Upstream.String())
+	assert.Equal(t, structflag.SourceFlag, values["Upstream"].Source())
+
+	assert.EqualError(t, values["Callback"].Set("/relative"), `invalid value "/relative" for flag Callback: URL "/relative" has no scheme`)
+	assert.Equal(t, structflag.SourceDefault, values["Callback"].Source())
+	require.NoError(t, values["Callback"].Set("custom://cb"))
+}
@@ -0,0 +1,153 @@
+package structflag
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"text/template"
+)
+
+// DescriptionData is available to descriptions containing text/template actions,
+// e.g. "Listen address, also read from {{.Env}} (default {{.Default}})".
+type DescriptionData struct {
+	// Name is the flag name.
+	Name string
+	// Type is the short type name shown next to the flag name.
+	Type string
+	// Default is the default value of the flag. It is empty for secret values.
+	Default string
+	// Choices is the value of the struct tag selected by ChoicesTag of the
+	// converter.
+	Choices string
+	// Env is the value of the struct tag selected by EnvTag of the converter.
+	Env string
+	// Example is the value of the struct tag selected by ExampleTag of the
+	// converter.
+	Example string
+}
+
+// PrintDefaults writes usage information for the values to w in the format used
+// by flag package. Values are sorted by name and values in groups are shown in
+// sections after the values without a group. Unlike flag.PrintDefaults, the
+// default shown for each value is the one captured when the value was created,
+// so it does not change after the values are parsed. Descriptions containing
+// template actions are expanded using DescriptionData and are responsible for
+// showing the default value themselves. Examples read by the converter are
+// shown after the default. JSON defaults longer than DefaultMaxWidth are
+// shortened to "{...}" or "[...]". Defaults of secret values are not shown.
+// Messages are taken from DefaultMessages.
+func PrintDefaults(w io.Writer, values map[string]Value) {
+	printDefaults(w, values, DefaultMessages, false)
+}
+
+// PrintFullDefaults is like PrintDefaults but shows JSON defaults completely,
+// pretty-printed on multiple lines.
+func PrintFullDefaults(w io.Writer, values map[string]Value) {
+	printDefaults(w, values, DefaultMessages, true)
+}
+
+// printDefaults writes usage information using the messages. JSON defaults are
+// pretty-printed if full is true and truncated otherwise.
+func printDefaults(w io.Writer, values map[string]Value, messages *Messages, full bool) {
+	for _, group := range groupNames(values) {
+		if group.name != "" {
+			fmt.Fprintf(w, "\n"+messages.Group+"\n", group.name)
+		}
+		printGroup(w, values, group.names, messages, full)
+	}
+}
+
+// printGroup writes usage information for the values with given names.
+func printGroup(w io.Writer, values map[string]Value, names []string, messages *Messages, full bool) {
+	for _, name := range names {
+		value := values[name]
+		var b strings.Builder
+		fmt.Fprintf(&b, "  -%s", name)
+		if typ := valueTypeName(value); typ != "" {
+			b.WriteString(" " + typ)
+		}
+		usage, expanded := expandDescription(name, value)
+		if def := value.Default(); def != "" && !expanded && !value.IsSecret() {
+			if usage != "" {
+				usage += " "
+			}
+			usage += fmt.Sprintf(messages.Default, formatDefault(def, DefaultMaxWidth, full, messages))
+		}
+		if example := valueExample(value); example != "" && !expanded {
+			if usage != "" {
+				usage += " "
+			}
+			usage += fmt.Sprintf(messages.Example, example)
+		}
+		if usage != "" {
+			b.WriteString("\n    \t")
+			b.WriteString(strings.Replace(usage, "\n", "\n    \t", -1))
+		}
+		fmt.Fprintln(w, b.String())
+	}
+}
+
+// expandDescription executes description of the value as a template. It returns
+// false if the description is not a template or can not be executed.
+func expandDescription(name string, value Value) (string, bool) {
+	description := value.Description()
+	if !strings.Contains(description, "{{") {
+		return description, false
+	}
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(description)
+	if err != nil {
+		return description, false
+	}
+	def := value.Default()
+	if value.IsSecret() {
+		def = ""
+	}
+	var choices, env string
+	if rv, ok := value.(*reflectedValue); ok {
+		choices, env = rv.choices, rv.env
+	}
+	data := DescriptionData{
+		Name:    name,
+		Type:    valueTypeName(value),
+		Default: def,
+		Choices: choices,
+		Env:     env,
+		Example: valueExample(value),
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return description, false
+	}
+	return b.String(), true
+}
+
+// valueExample returns the example value read by the converter or empty string
+// if the value has none.
+func valueExample(value Value) string {
+	if rv, ok := value.(*reflectedValue); ok {
+		return rv.example
+	}
+	return ""
+}
+
+// valueTypeName returns a short name for the type of the value shown next to
+// the flag name. Empty string is returned for boolean values.
+func valueTypeName(value Value) string {
+	if t := value.Field().Type; t != nil && (t == durationType || (t.Kind() == reflect.Ptr && t.Elem() == durationType)) {
+		return "duration"
+	}
+	switch value.Kind() {
+	case reflect.Bool:
+		return ""
+	case reflect.String:
+		return "string"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "int"
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return "uint"
+	case reflect.Float32, reflect.Float64:
+		return "float"
+	}
+	return "value"
+}
@@ -0,0 +1,150 @@
+package structflag_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/surajbarkale/structflag"
+)
+
+func TestPrintDefaults(t *testing.T) {
+	val := &struct {
+		Debug     bool    `description:"Enable debug mode"`
+		InputFile *string `description:"Name of input file"`
+		Count     int     `description:"Number of items"`
+		Pages     []int
+		Names     []string `description:"Names to use\nas input"`
+		Ratio     float64
+	}{Debug: true, Count: 3, Pages: []int{}, Names: []string{"a"}}
+	sv, err := structflag.NewStructToFlagsConverter().Convert(val)
+	require.NoError(t, err)
+	require.NoError(t, sv["Count"].Set("8"))
+	require.NoError(t, sv["InputFile"].Set("file.txt"))
+	var b bytes.Buffer
+	structflag.PrintDefaults(&b, sv)
+	exp := `  -Count int
+    	Number of items (default 3)
+  -Debug
+    	Enable debug mode (default true)
+  -InputFile string
+    	Name of input file
+  -Names value
+    	Names to use
+    	as input (default ["a"])
+  -Pages value
+  -Ratio float
+`
+	assert.Equal(t, exp, b.String())
+}
+
+func TestDefaultIsCapturedOnCreation(t *testing.T) {
+	i := 4
+	val := &struct {
+		Int    int
+		IntPtr *int
+		Empty  []int
+		Map    map[string]int
+	}{IntPtr: &i, Empty: []int{}, Map: map[string]int{"a": 1}}
+	sv, err := structflag.NewStructToFlagsConverter().Convert(val)
+	require.NoError(t, err)
+	for _, name := range []string{"IntPtr", "Empty", "Map"} {
+		require.NoError(t, sv[name].Set("null"))
+	}
+	require.NoError(t, sv["Int"].Set("3"))
+	assert.Equal(t, "", sv["Int"].Default())
+	assert.Equal(t, "4", sv["IntPtr"].Default())
+	assert.Equal(t, "", sv["Empty"].Default())
+	assert.Equal(t, `{"a":1}`, sv["Map"].Default())
+}
+
+func TestPrintDefaultsExpandsTemplates(t *testing.T) {
+	val := &struct {
+		Addr  string `description:"Listen address, also read from {{.Env}} (default {{.Default}})" env:"ADDR"`
+		Mode  string `description:"One of {{.Choices}}" choices:"fast,slow"`
+		Level int    `description:"Broken {{.Unknown}}"`
+	}{Addr: ":80", Mode: "fast", Level: 2}
+	sv, err := structflag.NewStructToFlagsConverter().Convert(val)
+	require.NoError(t, err)
+	var b bytes.Buffer
+	structflag.PrintDefaults(&b, sv)
+	exp := `  -Addr string
+    	Listen address, also read from ADDR (default :80)
+  -Level int
+    	Broken {{.Unknown}} (default 2)
+  -Mode string
+    	One of fast,slow
+`
+	assert.Equal(t, exp, b.String())
+
+	custom := &struct {
+		Addr string `description:"Read from {{.Env}}, one of {{.Choices}}" envvar:"ADDR" oneOf:"a,b" env:"X" choices:"y"`
+	}{}
+	converter := structflag.NewStructToFlagsConverter()
+	converter.EnvTag = "envvar"
+	converter.ChoicesTag = "oneOf"
+	sv, err = converter.Convert(custom)
+	require.NoError(t, err)
+	b.Reset()
+	structflag.PrintDefaults(&b, sv)
+	assert.Equal(t, "  -Addr string\n    \tRead from ADDR, one of a,b\n", b.String())
+}
+
+func TestPrintDefaultsExamples(t *testing.T) {
+	val := &struct {
+		Cache string `description:"Cache URL" example:"redis://localhost:6379"`
+		Addr  string `description:"Listen on {{.Example}}" example:":8080"`
+		Size  int    `example:"10"`
+	}{Size: 5}
+	sv, err := structflag.NewStructToFlagsConverter().Convert(val)
+	require.NoError(t, err)
+	var b bytes.Buffer
+	structflag.PrintDefaults(&b, sv)
+	exp := `  -Addr string
+    	Listen on :8080
+  -Cache string
+    	Cache URL (example: redis://localhost:6379)
+  -Size int
+    	(default 5) (example: 10)
+`
+	assert.Equal(t, exp, b.String())
+
+	printer := structflag.NewHelpPrinter()
+	printer.Width = 80
+	b.Reset()
+	printer.PrintDefaults(&b, sv)
+	exp = `  -Addr string   Listen on :8080
+  -Cache string  Cache URL (example: redis://localhost:6379)
+  -Size int      (default 5) (example: 10)
+`
+	assert.Equal(t, exp, b.String())
+
+	converter := structflag.NewStructToFlagsConverter()
+	converter.ExampleTag = "sample"
+	sv, err = converter.Convert(&struct {
+		Cache string `description:"Cache URL" sample:"memory" example:"redis"`
+	}{})
+	require.NoError(t, err)
+	b.Reset()
+	structflag.PrintDefaults(&b, sv)
+	assert.Equal(t, "  -Cache string\n    \tCache URL (example: memory)\n", b.String())
+}
+
+func TestPrintDefaultsHidesSecrets(t *testing.T) {
+	val := &struct {
+		Password string `description:"Database password" secret:"true"`
+		Token    string `description:"Token (default {{.Default}})" secret:"true"`
+	}{Password: "hunter2", Token: "abc"}
+	sv, err := structflag.NewStructToFlagsConverter().Convert(val)
+	require.NoError(t, err)
+	var b bytes.Buffer
+	structflag.PrintDefaults(&b, sv)
+	exp := `  -Password string
+    	Database password
+  -Token string
+    	Token (default )
+`
+	assert.Equal(t, exp, b.String())
+}
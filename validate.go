@@ -0,0 +1,38 @@
+package structflag
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"reflect"
+)
+
+// Validate runs the same steps as Parse, reading flags, environment variables
+// and configuration files and checking the values, but works on a copy of
+// target, so the target is not changed. Nothing is written to Output, the
+// program does not exit and OnSet hooks of the converter and OnFlagsUsed are
+// not called, so it can be used to implement a command checking the
+// configuration. You must pass a pointer to the value.
+func (thiz *Parser) Validate(target interface{}, args []string) error {
+	return thiz.ValidateContext(context.Background(), target, args)
+}
+
+// ValidateContext is like Validate but passes ctx to the sources of values like
+// ParseContext.
+func (thiz *Parser) ValidateContext(ctx context.Context, target interface{}, args []string) error {
+	if v := reflect.ValueOf(target); v.Kind() != reflect.Ptr || v.IsNil() {
+		return fmt.Errorf("validate target must be a non-nil pointer, got %T", target)
+	}
+	parser := *thiz
+	parser.Output = ioutil.Discard
+	parser.ErrorHandling = flag.ContinueOnError
+	parser.OnFlagsUsed = nil
+	parser.Converter = thiz.Converter.withoutOnSet()
+	if thiz.ConfigLoader != nil {
+		loader := *thiz.ConfigLoader
+		parser.ConfigLoader = &loader
+	}
+	_, err := parser.ParseContext(ctx, Snapshot(target), args)
+	return err
+}
@@ -0,0 +1,41 @@
+package structflag_test
+
+import (
+	"bytes"
+	"flag"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/surajbarkale/structflag"
+)
+
+func TestValidate(t *testing.T) {
+	val := &struct {
+		Port    int
+		TLSCert string `requires:"TLSKey"`
+		TLSKey  string
+	}{Port: 80}
+	var out bytes.Buffer
+	p := newTestParser(&out)
+	p.ErrorHandling = flag.ExitOnError
+	p.Exit = func(code int) {
+		t.Fatalf("exit %d", code)
+	}
+	var changes int
+	p.Converter = structflag.NewStructToFlagsConverter()
+	p.Converter.OnSet = func(path, oldValue, newValue string, source structflag.Source) {
+		changes++
+	}
+
+	require.NoError(t, p.Validate(val, []string{"-Port", "8080"}))
+	assert.Error(t, p.Validate(val, []string{"-Port", "x"}))
+	assert.Error(t, p.Validate(val, []string{"-TLSCert", "cert.pem"}))
+	assert.Equal(t, flag.ErrHelp, p.Validate(val, []string{"-help"}))
+	assert.Error(t, p.Validate(*val, nil))
+	assert.Equal(t, 80, val.Port)
+	assert.Empty(t, out.String())
+	assert.Equal(t, 0, changes)
+	assert.Nil(t, p.Values())
+}
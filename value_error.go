@@ -0,0 +1,115 @@
+package structflag
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ValueError is returned when a string can not be converted into a value.
+type ValueError struct {
+	// Path is the flag path of the value. It is empty for values which were not
+	// created from a struct field.
+	Path string
+	// Input is the string which could not be converted.
+	Input string
+	// Expected describes the syntax accepted by the value, e.g. "integer" or
+	// "JSON array of string". It is empty if there is no simple description.
+	Expected string
+	// Err is the conversion error.
+	Err error
+
+	// format is the message used instead of DefaultMessages.InvalidValue
+	format string
+}
+
+func (thiz *ValueError) Error() string {
+	if thiz.Path == "" {
+		return thiz.detail()
+	}
+	format := thiz.format
+	if format == "" {
+		format = DefaultMessages.InvalidValue
+	}
+	return fmt.Sprintf(format, thiz.Input, thiz.Path, thiz.detail())
+}
+
+// detail returns the conversion error with the expected syntax.
+func (thiz *ValueError) detail() string {
+	if thiz.Expected == "" {
+		return thiz.Err.Error()
+	}
+	return thiz.Err.Error() + ", expected " + thiz.Expected
+}
+
+// Unwrap returns the conversion error.
+func (thiz *ValueError) Unwrap() error {
+	return thiz.Err
+}
+
+// errorDetail returns the message of err without the flag path and input if it
+// is a ValueError, for callers which describe the value themselves.
+func errorDetail(err error) string {
+	var valueErr *ValueError
+	if errors.As(err, &valueErr) {
+		return valueErr.detail()
+	}
+	return err.Error()
+}
+
+// expectedSyntax describes the string syntax of values of type t. Types handled
+// by codecs and TextUnmarshaler implementations report their own errors.
+func expectedSyntax(t reflect.Type) string {
+	for t.Kind() == reflect.Ptr && !isCodecType(t) {
+		t = t.Elem()
+	}
+	if isAtomic(t) {
+		return expectedSyntax(atomicType(t))
+	}
+	switch {
+	case isCodecType(t), isTextType(t):
+		return ""
+	case t == rawMessageType:
+		return "JSON value"
+	case t == durationType:
+		return "duration such as 1m30s"
+	}
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array:
+		return "JSON array of " + syntaxNoun(t.Elem())
+	case reflect.Map:
+		return "JSON object with " + syntaxNoun(t.Elem()) + " values"
+	case reflect.Struct:
+		return "JSON object"
+	case reflect.String, reflect.Interface:
+		return ""
+	}
+	return syntaxNoun(t)
+}
+
+// syntaxNoun names the kind of values of type t.
+func syntaxNoun(t reflect.Type) string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == durationType {
+		return "duration"
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "integer"
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return "unsigned integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.String:
+		return "string"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Map, reflect.Struct:
+		return "object"
+	}
+	return t.String()
+}
@@ -0,0 +1,60 @@
+package structflag_test
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/surajbarkale/structflag"
+)
+
+func TestValueError(t *testing.T) {
+	val := &struct {
+		Port    int
+		Workers uint
+		Ratio   float64
+		Verbose bool
+		Hosts   []string
+		Limits  map[string]int
+		Server  struct{ Retries *int }
+	}{}
+	values, err := structflag.DefaultStructToFlagsConverter.Convert(val)
+	require.NoError(t, err)
+	assert.EqualError(t, values["Port"].Set("x"), `invalid value "x" for flag Port: strconv.ParseInt: parsing "x": invalid syntax, expected integer`)
+	assert.EqualError(t, values["Workers"].Set("-1"), `invalid value "-1" for flag Workers: strconv.ParseUint: parsing "-1": invalid syntax, expected unsigned integer`)
+	assert.EqualError(t, values["Ratio"].Set("half"), `invalid value "half" for flag Ratio: strconv.ParseFloat: parsing "half": invalid syntax, expected number`)
+	assert.EqualError(t, values["Verbose"].Set("maybe"), `invalid value "maybe" for flag Verbose: strconv.ParseBool: parsing "maybe": invalid syntax, expected boolean`)
+	assert.Contains(t, values["Hosts"].Set("a,b").Error(), ", expected JSON array of string")
+	assert.Contains(t, values["Limits"].Set("a=1").Error(), ", expected JSON object with integer values")
+	assert.EqualError(t, values["Server-Retries"].Set("many"), `invalid value "many" for flag Server-Retries: strconv.ParseInt: parsing "many": invalid syntax, expected integer`)
+
+	err = values["Port"].Set("x")
+	var valueErr *structflag.ValueError
+	require.True(t, errors.As(err, &valueErr))
+	assert.Equal(t, "Port", valueErr.Path)
+	assert.Equal(t, "x", valueErr.Input)
+	assert.Equal(t, "integer", valueErr.Expected)
+	assert.True(t, errors.Is(err, strconv.ErrSyntax))
+}
+
+func TestValueErrorArgs(t *testing.T) {
+	val := &struct {
+		Port int `aliases:"p"`
+	}{}
+	parser := structflag.NewParser()
+	_, err := parser.Parse(val, []string{"-p", "x"})
+	assert.EqualError(t, err, `invalid value "x" for flag -p: strconv.ParseInt: parsing "x": invalid syntax, expected integer`)
+}
+
+func TestValueErrorProfile(t *testing.T) {
+	val := &struct {
+		Port int `default_prod:"x"`
+	}{}
+	parser := structflag.NewParser()
+	parser.Profile = "prod"
+	_, err := parser.Parse(val, nil)
+	assert.EqualError(t, err, `invalid value "x" for flag Port in profile prod: strconv.ParseInt: parsing "x": invalid syntax, expected integer`)
+}
@@ -0,0 +1,100 @@
+/*
+Package viperflag moves configuration between structflag structures and viper,
+so that both can be used while migrating an application:
+
+	settings, err := viperflag.ToViperMap(&config)
+	if err != nil {
+		return err
+	}
+	v.MergeConfigMap(settings)
+	...
+	err = viperflag.FromViper(v, &config)
+
+Viper keys are the flag names generated by Converter, which uses "." to
+separate nested struct fields, so Server.Port field is the "server.port" key.
+*/
+package viperflag
+
+import (
+	"encoding"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"github.com/surajbarkale/structflag"
+)
+
+// Converter generates the values and keys used by this package. It is
+// DefaultStructToFlagsConverter with "." as word separator.
+var Converter = newConverter()
+
+func newConverter() *structflag.StructToFlagsConverter {
+	converter := structflag.NewStructToFlagsConverter()
+	converter.WordSeparator = "."
+	return converter
+}
+
+// ToViperMap returns the values of target as nested maps which can be passed to
+// viper.MergeConfigMap. The target is not modified. You must pass a pointer to
+// the value.
+func ToViperMap(target interface{}) (map[string]interface{}, error) {
+	values, err := Converter.Convert(structflag.Snapshot(target))
+	if err != nil {
+		return nil, err
+	}
+	res := map[string]interface{}{}
+	for name, value := range values {
+		keys := strings.Split(name, ".")
+		m := res
+		for _, key := range keys[:len(keys)-1] {
+			nested, ok := m[key].(map[string]interface{})
+			if !ok {
+				nested = map[string]interface{}{}
+				m[key] = nested
+			}
+			m = nested
+		}
+		m[keys[len(keys)-1]] = value.Get()
+	}
+	return res, nil
+}
+
+// FromViper sets the fields of target which have a value in v. Strings are used
+// as flag values and other values are converted to JSON first. You must pass a
+// pointer to the value.
+func FromViper(v *viper.Viper, target interface{}) error {
+	values, err := Converter.Convert(target)
+	if err != nil {
+		return err
+	}
+	for name, value := range values {
+		if !v.IsSet(name) {
+			continue
+		}
+		s, err := viperString(v.Get(name))
+		if err != nil {
+			return err
+		}
+		if err := value.Set(s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// viperString converts a value stored in viper into a flag value.
+func viperString(v interface{}) (string, error) {
+	switch v := v.(type) {
+	case string:
+		return v, nil
+	case time.Duration:
+		return v.String(), nil
+	case encoding.TextMarshaler:
+		text, err := v.MarshalText()
+		return string(text), err
+	}
+	encoded, err := json.Marshal(v)
+	return string(encoded), err
+}
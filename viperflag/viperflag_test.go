@@ -0,0 +1,59 @@
+package viperflag_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/surajbarkale/structflag/viperflag"
+)
+
+type config struct {
+	Name   string
+	Tags   []string
+	Server struct {
+		Port    int
+		Timeout time.Duration
+	}
+}
+
+func TestToViperMap(t *testing.T) {
+	val := &config{Name: "app", Tags: []string{"a"}}
+	val.Server.Port = 80
+	val.Server.Timeout = time.Second
+	settings, err := viperflag.ToViperMap(val)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"Name": "app",
+		"Tags": []string{"a"},
+		"Server": map[string]interface{}{
+			"Port":    80,
+			"Timeout": time.Second,
+		},
+	}, settings)
+
+	v := viper.New()
+	require.NoError(t, v.MergeConfigMap(settings))
+	assert.Equal(t, 80, v.GetInt("server.port"))
+	assert.Equal(t, time.Second, v.GetDuration("server.timeout"))
+}
+
+func TestFromViper(t *testing.T) {
+	v := viper.New()
+	v.Set("name", "app")
+	v.Set("tags", []string{"x", "y"})
+	v.Set("server.port", 8080)
+	v.Set("server.timeout", "1m")
+	val := &config{}
+	require.NoError(t, viperflag.FromViper(v, val))
+	assert.Equal(t, "app", val.Name)
+	assert.Equal(t, []string{"x", "y"}, val.Tags)
+	assert.Equal(t, 8080, val.Server.Port)
+	assert.Equal(t, time.Minute, val.Server.Timeout)
+
+	v.Set("server.port", "x")
+	assert.Error(t, viperflag.FromViper(v, val))
+}
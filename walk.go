@@ -0,0 +1,30 @@
+package structflag
+
+import (
+	"reflect"
+)
+
+// FieldInfo describes a single leaf field visited by Walk.
+type FieldInfo struct {
+	// Path is the flag name generated for the field.
+	Path string
+	// Tag is the struct tag of the field.
+	Tag reflect.StructTag
+	// Kind is the kind of the field with pointers dereferenced.
+	Kind reflect.Kind
+	// Value is the flag value bound to the field.
+	Value Value
+}
+
+// Walk calls fn for every field that Convert would return, in the order of
+// declaration. Nested structs are visited depth first. Walking stops at the
+// first error returned by fn and the error is returned to the caller. You must
+// pass a pointer to the value.
+func (thiz *StructToFlagsConverter) Walk(input interface{}, fn func(FieldInfo) error) error {
+	return thiz.walkStruct(thiz.Prefix, reflect.ValueOf(input), fn)
+}
+
+// Walk visits all fields of input using DefaultStructToFlagsConverter.
+func Walk(input interface{}, fn func(FieldInfo) error) error {
+	return DefaultStructToFlagsConverter.Walk(input, fn)
+}
@@ -0,0 +1,67 @@
+package structflag_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/surajbarkale/structflag"
+)
+
+func TestWalkVisitsFieldsInOrder(t *testing.T) {
+	val := &param{}
+	var paths []string
+	var kinds []reflect.Kind
+	err := structflag.Walk(val, func(info structflag.FieldInfo) error {
+		paths = append(paths, info.Path)
+		kinds = append(kinds, info.Kind)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"Nested-Int",
+		"Nested-IntPtr",
+		"Nested-Float",
+		"Nested-FloatPtr",
+		"NestedPtr-Int",
+		"NestedPtr-IntPtr",
+		"NestedPtr-Float",
+		"NestedPtr-FloatPtr",
+		"String",
+		"StringPtr",
+		"IntArray",
+	}, paths)
+	assert.Equal(t, reflect.Int, kinds[1])
+	assert.Equal(t, reflect.Float32, kinds[2])
+	assert.Equal(t, reflect.Slice, kinds[10])
+}
+
+func TestWalkExposesTagsAndValues(t *testing.T) {
+	val := &struct {
+		Name string `description:"The name" env:"NAME"`
+	}{}
+	err := structflag.Walk(val, func(info structflag.FieldInfo) error {
+		assert.Equal(t, "NAME", info.Tag.Get("env"))
+		assert.Equal(t, "The name", info.Value.Description())
+		return info.Value.Set("walked")
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "walked", val.Name)
+}
+
+func TestWalkStopsOnError(t *testing.T) {
+	stop := errors.New("stop")
+	count := 0
+	err := structflag.Walk(&param{}, func(info structflag.FieldInfo) error {
+		count++
+		if info.Path == "NestedPtr-Int" {
+			return stop
+		}
+		return nil
+	})
+	assert.Equal(t, stop, err)
+	assert.Equal(t, 5, count)
+}
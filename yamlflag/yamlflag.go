@@ -0,0 +1,73 @@
+/*
+Package yamlflag lets complex structflag values be written in YAML, which is a
+superset of JSON that does not require quoting keys and strings:
+
+	converter := structflag.NewStructToFlagsConverter()
+	converter.Unmarshal = yamlflag.Unmarshal
+
+With this converter a map field can be set with {a: 1, b: [x, y]} instead of
+{"a": 1, "b": ["x", "y"]}. Struct fields are matched in the same way as in
+encoding/json.
+*/
+package yamlflag
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Unmarshal parses YAML data and stores the result in v. The data is converted
+// to JSON first, so v is decoded by encoding/json and keeps its JSON semantics.
+func Unmarshal(data []byte, v interface{}) error {
+	var doc interface{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	converted, err := jsonCompatible(doc)
+	if err != nil {
+		return err
+	}
+	encoded, err := json.Marshal(converted)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(encoded, v)
+}
+
+// jsonCompatible converts maps with non-string keys produced by YAML decoder
+// into maps which can be encoded as JSON.
+func jsonCompatible(v interface{}) (interface{}, error) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, elem := range v {
+			converted, err := jsonCompatible(elem)
+			if err != nil {
+				return nil, err
+			}
+			v[key] = converted
+		}
+		return v, nil
+	case map[interface{}]interface{}:
+		res := make(map[string]interface{}, len(v))
+		for key, elem := range v {
+			converted, err := jsonCompatible(elem)
+			if err != nil {
+				return nil, err
+			}
+			res[fmt.Sprint(key)] = converted
+		}
+		return res, nil
+	case []interface{}:
+		for i, elem := range v {
+			converted, err := jsonCompatible(elem)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = converted
+		}
+		return v, nil
+	}
+	return v, nil
+}
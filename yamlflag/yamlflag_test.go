@@ -0,0 +1,54 @@
+package yamlflag_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/surajbarkale/structflag"
+	"github.com/surajbarkale/structflag/yamlflag"
+)
+
+type limits struct {
+	CPU    float64 `json:"cpu"`
+	Memory string
+}
+
+func TestYAMLValues(t *testing.T) {
+	converter := structflag.NewStructToFlagsConverter()
+	converter.Unmarshal = yamlflag.Unmarshal
+	val := &struct {
+		Labels  map[string]interface{}
+		Names   []string
+		Limits  []limits
+		Ports   map[int]string
+		Name    string
+		Pointer *[]int
+	}{}
+	values, err := converter.Convert(val)
+	require.NoError(t, err)
+
+	require.NoError(t, values["Labels"].Set("{a: 1, b: [x, y]}"))
+	assert.Equal(t, map[string]interface{}{"a": float64(1), "b": []interface{}{"x", "y"}}, val.Labels)
+	assert.Equal(t, `{"a":1,"b":["x","y"]}`, values["Labels"].String())
+	require.NoError(t, values["Names"].Set("[alice, bob]"))
+	assert.Equal(t, []string{"alice", "bob"}, val.Names)
+	require.NoError(t, values["Limits"].Set("[{cpu: 0.5, memory: 1Gi}]"))
+	assert.Equal(t, []limits{{CPU: 0.5, Memory: "1Gi"}}, val.Limits)
+	require.NoError(t, values["Ports"].Set("{80: http, 443: https}"))
+	assert.Equal(t, map[int]string{80: "http", 443: "https"}, val.Ports)
+	require.NoError(t, values["Pointer"].Set("[1, 2]"))
+	assert.Equal(t, []int{1, 2}, *val.Pointer)
+
+	// JSON is valid YAML
+	require.NoError(t, values["Names"].Set(`["carol"]`))
+	assert.Equal(t, []string{"carol"}, val.Names)
+	// Scalars are not affected
+	require.NoError(t, values["Name"].Set("[x]"))
+	assert.Equal(t, "[x]", val.Name)
+
+	assert.Error(t, values["Names"].Set("{a: 1"))
+	assert.Error(t, values["Names"].Set("{a: 1}"))
+	assert.Equal(t, []string{"carol"}, val.Names)
+}